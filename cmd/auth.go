@@ -13,9 +13,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var authProfile string
+
 var authCmd = &cobra.Command{
 	Use:   "auth",
-	Short: "Authenticate with Last.fm",
+	Short: "Authenticate with Last.fm or another Audioscrobbler server",
 	Long: `Authenticate with Last.fm to enable scrobbling.
 
 This command will guide you through the Last.fm authentication process:
@@ -23,18 +25,31 @@ This command will guide you through the Last.fm authentication process:
 2. A browser URL will be provided for you to authorize the application
 3. After authorization, a session key will be saved to your config file
 
-You can get API credentials from: https://www.last.fm/api/account/create`,
+You can get API credentials from: https://www.last.fm/api/account/create
+
+With --profile, the same flow runs against another Audioscrobbler 2.0
+compatible server, such as Libre.fm or a self-hosted GNU FM, and the account
+is saved as a named profile. The daemon scrobbles to every authenticated
+profile as well as Last.fm. The "librefm" profile knows Libre.fm's URLs; for
+any other name you are asked for the server's API and authorization URLs.`,
+	Example: `  scribbles auth
+  scribbles auth --profile librefm
+  scribbles auth --profile gnufm`,
 	RunE: runAuth,
 }
 
 func init() {
 	rootCmd.AddCommand(authCmd)
+
+	authCmd.Flags().StringVar(&authProfile, "profile", "", "Authenticate a named profile on an Audioscrobbler-compatible server instead of Last.fm")
 }
 
-func promptCredentials(reader *bufio.Reader, cfg *config.Config) error {
-	if cfg.LastFM.APIKey != "" && cfg.LastFM.APISecret != "" {
+// promptCredentials asks for an API key and secret for a service, offering
+// to keep existing ones
+func promptCredentials(reader *bufio.Reader, service string, apiKey, apiSecret *string) error {
+	if *apiKey != "" && *apiSecret != "" {
 		fmt.Println("Found existing API credentials.")
-		fmt.Printf("API Key: %s\n", cfg.LastFM.APIKey)
+		fmt.Printf("API Key: %s\n", *apiKey)
 		fmt.Print("\nUse existing credentials? [Y/n]: ")
 		response, err := reader.ReadString('\n')
 		if err != nil {
@@ -42,48 +57,85 @@ func promptCredentials(reader *bufio.Reader, cfg *config.Config) error {
 		}
 		response = strings.TrimSpace(strings.ToLower(response))
 		if response != "" && response != "y" && response != "yes" {
-			cfg.LastFM.APIKey = ""
-			cfg.LastFM.APISecret = ""
+			*apiKey = ""
+			*apiSecret = ""
 		}
 	}
 
-	if cfg.LastFM.APIKey == "" {
-		fmt.Print("Enter your Last.fm API Key: ")
-		apiKey, err := reader.ReadString('\n')
+	if *apiKey == "" {
+		fmt.Printf("Enter your %s API Key: ", service)
+		key, err := reader.ReadString('\n')
 		if err != nil {
 			return fmt.Errorf("failed to read API key: %w", err)
 		}
-		cfg.LastFM.APIKey = strings.TrimSpace(apiKey)
+		*apiKey = strings.TrimSpace(key)
 	}
 
-	if cfg.LastFM.APISecret == "" {
-		fmt.Print("Enter your Last.fm API Secret: ")
-		apiSecret, err := reader.ReadString('\n')
+	if *apiSecret == "" {
+		fmt.Printf("Enter your %s API Secret: ", service)
+		secret, err := reader.ReadString('\n')
 		if err != nil {
 			return fmt.Errorf("failed to read API secret: %w", err)
 		}
-		cfg.LastFM.APISecret = strings.TrimSpace(apiSecret)
+		*apiSecret = strings.TrimSpace(secret)
 	}
 
-	if cfg.LastFM.APIKey == "" || cfg.LastFM.APISecret == "" {
+	if *apiKey == "" || *apiSecret == "" {
 		return fmt.Errorf("API key and secret are required")
 	}
 
 	return nil
 }
 
-func getSessionWithRetries(ctx context.Context, client *scrobbler.Client, token string) (string, error) {
+// promptServer asks for the URLs of a profile's server when they are not
+// known yet
+func promptServer(reader *bufio.Reader, profile *config.ProfileConfig) error {
+	if profile.APIURL == "" {
+		fmt.Print("Enter the server's API URL (e.g. https://gnufm.example.com/2.0/): ")
+		apiURL, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read API URL: %w", err)
+		}
+		profile.APIURL = strings.TrimSpace(apiURL)
+		if profile.APIURL == "" {
+			return fmt.Errorf("API URL is required")
+		}
+	}
+
+	if profile.AuthURL == "" {
+		suggested := defaultAuthURL(profile.APIURL)
+		fmt.Printf("Enter the server's authorization page URL [%s]: ", suggested)
+		authURL, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read authorization URL: %w", err)
+		}
+		profile.AuthURL = strings.TrimSpace(authURL)
+		if profile.AuthURL == "" {
+			profile.AuthURL = suggested
+		}
+	}
+
+	return nil
+}
+
+// defaultAuthURL guesses the authorization page of a server from its API
+// URL, following the GNU FM layout used by Libre.fm: an API at /2.0/ and
+// the page at /api/auth/
+func defaultAuthURL(apiURL string) string {
+	base := strings.TrimSuffix(strings.TrimRight(apiURL, "/"), "/2.0")
+	return base + "/api/auth/"
+}
+
+func getSessionWithRetries(ctx context.Context, client *scrobbler.Client, token string) (sessionKey, username string, err error) {
 	const (
 		maxRetries = 3
 		retryDelay = 2 * time.Second
 	)
 
-	var sessionKey string
-	var err error
 	for i := range maxRetries {
-		sessionKey, err = client.GetSession(ctx, token)
+		sessionKey, username, err = client.GetSession(ctx, token)
 		if err == nil {
-			return sessionKey, nil
+			return sessionKey, username, nil
 		}
 
 		if i < maxRetries-1 {
@@ -93,34 +145,91 @@ func getSessionWithRetries(ctx context.Context, client *scrobbler.Client, token
 		}
 	}
 
-	return "", fmt.Errorf("failed to get session key after %d attempts: %w", maxRetries, err)
+	return "", "", fmt.Errorf("failed to get session key after %d attempts: %w", maxRetries, err)
 }
 
 func runAuth(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-	reader := bufio.NewReader(os.Stdin)
-
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	if authProfile != "" {
+		return runProfileAuth(cfg, authProfile)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+
 	fmt.Println("Last.fm Authentication")
 	fmt.Println("======================")
 	fmt.Println()
 	fmt.Println("You can get API credentials from: https://www.last.fm/api/account/create")
 	fmt.Println()
 
-	if err := promptCredentials(reader, cfg); err != nil {
+	if err := promptCredentials(reader, "Last.fm", &cfg.LastFM.APIKey, &cfg.LastFM.APISecret); err != nil {
 		return err
 	}
 
 	client := scrobbler.New(cfg.LastFM.APIKey, cfg.LastFM.APISecret)
+	sessionKey, username, err := authorize(reader, client)
+	if err != nil {
+		return err
+	}
+
+	cfg.LastFM.SessionKey = sessionKey
+	cfg.LastFM.Username = username
+	return saveAuth(cfg)
+}
+
+// runProfileAuth authenticates a named profile against its server
+func runProfileAuth(cfg *config.Config, name string) error {
+	if err := config.ValidateProfileName(name); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	profile := cfg.Profile(name)
+
+	title := fmt.Sprintf("Authentication for profile %q", name)
+	fmt.Println(title)
+	fmt.Println(strings.Repeat("=", len(title)))
+	fmt.Println()
+
+	if err := promptServer(reader, &profile); err != nil {
+		return err
+	}
+	fmt.Printf("Server: %s\n\n", profile.APIURL)
+
+	if err := promptCredentials(reader, name, &profile.APIKey, &profile.APISecret); err != nil {
+		return err
+	}
+
+	client := scrobbler.NewForEndpoint(scrobbler.Endpoint{
+		Name:    name,
+		APIURL:  profile.APIURL,
+		AuthURL: profile.AuthURL,
+	}, profile.APIKey, profile.APISecret, "")
+
+	sessionKey, username, err := authorize(reader, client)
+	if err != nil {
+		return err
+	}
+
+	profile.SessionKey = sessionKey
+	profile.Username = username
+	cfg.Profiles[name] = profile
+	return saveAuth(cfg)
+}
+
+// authorize runs the token flow: the user approves a token in their
+// browser, and it is exchanged for a session key
+func authorize(reader *bufio.Reader, client *scrobbler.Client) (sessionKey, username string, err error) {
+	ctx := context.Background()
 
 	fmt.Println("\nGenerating authentication token...")
 	token, authURL, err := client.AuthenticateWithToken(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to generate auth token: %w", err)
+		return "", "", fmt.Errorf("failed to generate auth token: %w", err)
 	}
 
 	fmt.Println("\nPlease visit this URL to authorize scribbles:")
@@ -129,12 +238,11 @@ func runAuth(cmd *cobra.Command, args []string) error {
 	_, _ = reader.ReadString('\n')
 
 	fmt.Println("Retrieving session key...")
-	sessionKey, err := getSessionWithRetries(ctx, client, token)
-	if err != nil {
-		return err
-	}
+	return getSessionWithRetries(ctx, client, token)
+}
 
-	cfg.LastFM.SessionKey = sessionKey
+// saveAuth saves the config after a successful authentication
+func saveAuth(cfg *config.Config) error {
 	if err := cfg.Save(); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
@@ -0,0 +1,21 @@
+package cmd
+
+import "testing"
+
+func TestDefaultAuthURL(t *testing.T) {
+	tests := []struct {
+		apiURL string
+		want   string
+	}{
+		{"https://libre.fm/2.0/", "https://libre.fm/api/auth/"},
+		{"https://gnufm.example.com/2.0", "https://gnufm.example.com/api/auth/"},
+		{"https://music.example.com/gnufm/2.0/", "https://music.example.com/gnufm/api/auth/"},
+		{"https://maloja.example.com/apis/audioscrobbler/", "https://maloja.example.com/apis/audioscrobbler/api/auth/"},
+	}
+
+	for _, tt := range tests {
+		if got := defaultAuthURL(tt.apiURL); got != tt.want {
+			t.Errorf("defaultAuthURL(%q) = %q, want %q", tt.apiURL, got, tt.want)
+		}
+	}
+}
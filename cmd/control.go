@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/jfmyers9/scribbles/internal/config"
 	"github.com/jfmyers9/scribbles/internal/music"
 	"github.com/spf13/cobra"
 )
@@ -13,40 +14,40 @@ import (
 // playCmd represents the play command
 var playCmd = &cobra.Command{
 	Use:   "play",
-	Short: "Resume playback in Apple Music",
-	Long:  `Resume playback in Apple Music. If paused, starts playing the current track.`,
+	Short: "Resume playback",
+	Long:  `Resume playback in the configured music player (Apple Music or MPD). If paused, starts playing the current track.`,
 	RunE:  runPlay,
 }
 
 // pauseCmd represents the pause command
 var pauseCmd = &cobra.Command{
 	Use:   "pause",
-	Short: "Pause playback in Apple Music",
-	Long:  `Pause playback in Apple Music. Pauses the currently playing track.`,
+	Short: "Pause playback",
+	Long:  `Pause playback in the configured music player (Apple Music or MPD). Pauses the currently playing track.`,
 	RunE:  runPause,
 }
 
 // playpauseCmd represents the playpause command
 var playpauseCmd = &cobra.Command{
 	Use:   "playpause",
-	Short: "Toggle play/pause in Apple Music",
-	Long:  `Toggle between play and pause states in Apple Music. If playing, pauses. If paused, resumes.`,
+	Short: "Toggle play/pause",
+	Long:  `Toggle between play and pause states in the configured music player (Apple Music or MPD). If playing, pauses. If paused, resumes.`,
 	RunE:  runPlayPause,
 }
 
 // nextCmd represents the next command
 var nextCmd = &cobra.Command{
 	Use:   "next",
-	Short: "Skip to next track in Apple Music",
-	Long:  `Skip to the next track in Apple Music. Advances to the next track in the current playlist or queue.`,
+	Short: "Skip to next track",
+	Long:  `Skip to the next track in the configured music player (Apple Music or MPD). Advances to the next track in the current playlist or queue.`,
 	RunE:  runNext,
 }
 
 // prevCmd represents the prev command
 var prevCmd = &cobra.Command{
 	Use:   "prev",
-	Short: "Go to previous track in Apple Music",
-	Long:  `Go to the previous track in Apple Music. Returns to the previous track in the current playlist or queue.`,
+	Short: "Go to previous track",
+	Long:  `Go to the previous track in the configured music player (Apple Music or MPD). Returns to the previous track in the current playlist or queue.`,
 	RunE:  runPrev,
 }
 
@@ -84,11 +85,61 @@ func init() {
 	rootCmd.AddCommand(volumeCmd)
 }
 
+// newMusicClient returns a client for the configured music player, or
+// for the highest priority source if several are configured
+func newMusicClient(cfg *config.Config) music.Client {
+	src := cfg.MusicSources()[0]
+	if src.Player == config.PlayerWebhook {
+		// Webhook events only reach the daemon's client
+		return daemonOnlyClient{Client: newSourceClient(src), player: src.Player}
+	}
+	return newSourceClient(src)
+}
+
+// daemonOnlyClient is a player whose tracks are pushed to the daemon, so
+// only the daemon knows what is playing
+type daemonOnlyClient struct {
+	music.Client
+	player string
+}
+
+// GetCurrentTrack fails: without the daemon, the track is unknown
+func (c daemonOnlyClient) GetCurrentTrack(ctx context.Context) (*music.Track, error) {
+	return nil, fmt.Errorf("the %s player only reports its track to the daemon: is the daemon running?", c.player)
+}
+
+// newSourceClient returns a client for a music source's player
+func newSourceClient(src config.SourceConfig) music.Client {
+	switch src.Player {
+	case config.PlayerMPD:
+		return music.NewMPDClient(src.MPD.Address, src.MPD.Password)
+	case config.PlayerWebhook:
+		return music.NewWebhookClient(src.WebhookSource.Address, src.WebhookSource.Token, src.WebhookSource.User)
+	case config.PlayerSubsonic:
+		return music.NewSubsonicClient(src.Subsonic.URL, src.Subsonic.Username, src.Subsonic.Password)
+	default:
+		return music.NewAppleScriptClient()
+	}
+}
+
+// loadMusicClient loads the configuration and returns a client for its
+// music player
+func loadMusicClient() (music.Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return newMusicClient(cfg), nil
+}
+
 func runPlay(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	client := music.NewAppleScriptClient()
+	client, err := loadMusicClient()
+	if err != nil {
+		return err
+	}
 	if err := client.Play(ctx); err != nil {
 		return fmt.Errorf("failed to play: %w", err)
 	}
@@ -100,7 +151,10 @@ func runPause(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	client := music.NewAppleScriptClient()
+	client, err := loadMusicClient()
+	if err != nil {
+		return err
+	}
 	if err := client.Pause(ctx); err != nil {
 		return fmt.Errorf("failed to pause: %w", err)
 	}
@@ -112,7 +166,10 @@ func runPlayPause(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	client := music.NewAppleScriptClient()
+	client, err := loadMusicClient()
+	if err != nil {
+		return err
+	}
 	if err := client.PlayPause(ctx); err != nil {
 		return fmt.Errorf("failed to playpause: %w", err)
 	}
@@ -124,7 +181,10 @@ func runNext(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	client := music.NewAppleScriptClient()
+	client, err := loadMusicClient()
+	if err != nil {
+		return err
+	}
 	if err := client.NextTrack(ctx); err != nil {
 		return fmt.Errorf("failed to skip to next track: %w", err)
 	}
@@ -136,7 +196,10 @@ func runPrev(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	client := music.NewAppleScriptClient()
+	client, err := loadMusicClient()
+	if err != nil {
+		return err
+	}
 	if err := client.PreviousTrack(ctx); err != nil {
 		return fmt.Errorf("failed to go to previous track: %w", err)
 	}
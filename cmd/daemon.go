@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"time"
 
@@ -24,19 +25,26 @@ var (
 	daemonDataDir  string
 	daemonTUI      bool
 	daemonDiscord  bool
+	daemonHTTP     bool
 )
 
 // daemonCmd represents the daemon command
 var daemonCmd = &cobra.Command{
 	Use:   "daemon",
 	Short: "Run the scrobbling daemon",
-	Long: `Run the scrobbling daemon that monitors Apple Music and scrobbles tracks to Last.fm.
+	Long: `Run the scrobbling daemon that monitors Apple Music and scrobbles tracks to Last.fm,
+ListenBrainz, the Audioscrobbler-compatible profiles added with "scribbles auth
+--profile" and/or webhooks. Every scrobble is delivered to each configured
+service independently.
 
 The daemon will:
 - Poll Apple Music every few seconds to detect track changes
 - Track playback time and handle pause/resume correctly
-- Scrobble tracks to Last.fm when they meet the scrobbling threshold (50% or 4 minutes)
+- Scrobble tracks when they meet the scrobbling threshold (50% or 4 minutes)
 - Queue failed scrobbles for retry
+- Record every play in the local listening history
+- Serve its status and take commands on a control socket (see "scribbles scrobbling")
+- Optionally serve an HTTP API on localhost (--http)
 - Optionally show the current track via Discord Rich Presence (--discord)
 - Handle graceful shutdown on SIGINT/SIGTERM
 
@@ -53,6 +61,7 @@ func init() {
 	daemonCmd.Flags().StringVar(&daemonDataDir, "data-dir", "", "Data directory for state and queue (default: ~/.local/share/scribbles)")
 	daemonCmd.Flags().BoolVar(&daemonTUI, "tui", false, "Enable terminal UI for now playing display")
 	daemonCmd.Flags().BoolVar(&daemonDiscord, "discord", false, "Enable Discord Rich Presence")
+	daemonCmd.Flags().BoolVar(&daemonHTTP, "http", false, "Enable the local HTTP API (address from http.address)")
 }
 
 func runDaemon(cmd *cobra.Command, args []string) error {
@@ -65,7 +74,8 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
-	if err := cfg.ValidateLastFM(); err != nil {
+	backends, err := newBackends(cfg)
+	if err != nil {
 		return err
 	}
 
@@ -108,22 +118,36 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 
 	logger.Info().Str("data_dir", dataDir).Msg("Using data directory")
 
-	musicClient := music.NewAppleScriptClient()
-	scrobblerClient := scrobbler.NewWithSession(
-		cfg.LastFM.APIKey,
-		cfg.LastFM.APISecret,
-		cfg.LastFM.SessionKey,
-	)
+	for _, b := range backends {
+		logger.Info().Str("backend", b.Name()).Msg("Using scrobbling backend")
+	}
+
+	var sources []daemon.Source
+	for _, src := range cfg.MusicSources() {
+		logger.Info().Str("source", src.Name).Str("player", src.Player).Msg("Using music source")
+		sources = append(sources, daemon.Source{
+			Name:     src.Name,
+			Client:   newSourceClient(src),
+			Priority: src.Priority,
+		})
+	}
 
 	daemonCfg := daemon.Config{
 		PollInterval:      time.Duration(cfg.PollInterval) * time.Second,
 		StateFile:         filepath.Join(dataDir, "state.json"),
 		QueueDB:           filepath.Join(dataDir, "queue.db"),
+		HistoryDB:         filepath.Join(dataDir, "history.db"),
+		PreferRecent:      cfg.Arbitration == config.ArbitrationRecent,
 		ProcessInterval:   30 * time.Second,
 		ScrobbleThreshold: 0.5,
+		ControlSocket:     filepath.Join(dataDir, daemon.ControlSocketFile),
+	}
+	if daemonHTTP || cfg.HTTP.Enabled {
+		daemonCfg.HTTPAddr = cfg.HTTP.Address
+		daemonCfg.HTTPAllowOrigin = cfg.HTTP.AllowOrigin
 	}
 
-	d, err := daemon.New(daemonCfg, musicClient, scrobblerClient, logger)
+	d, err := daemon.New(daemonCfg, sources, backends, logger)
 	if err != nil {
 		return fmt.Errorf("failed to create daemon: %w", err)
 	}
@@ -131,15 +155,17 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 	// Start Discord Rich Presence if enabled
 	enableDiscord := daemonDiscord || cfg.Discord.Enabled
 	if enableDiscord && cfg.Discord.AppID != "" {
-		discordUpdates := d.EnableDiscord()
+		sub := d.Subscribe("discord", 16)
+		defer sub.Close()
 		presence := discord.New(cfg.Discord.AppID, logger)
 		discordCtx, discordCancel := context.WithCancel(context.Background())
 		defer discordCancel()
-		go presence.Run(discordCtx, discordUpdates)
+		go presence.Run(discordCtx, sub.Events())
 	}
 
 	if enableTUI {
-		return runDaemonWithTUI(d, musicClient, cfg, logger)
+		// The TUI's playback controls act on the highest priority source
+		return runDaemonWithTUI(d, sources[0].Client, cfg, logger)
 	}
 
 	if err := d.Run(); err != nil {
@@ -155,9 +181,78 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// newBackends returns every configured scrobbling backend: Last.fm,
+// ListenBrainz, each authenticated profile and each webhook. Each scrobble
+// is delivered to all of them.
+func newBackends(cfg *config.Config) ([]scrobbler.Backend, error) {
+	var backends []scrobbler.Backend
+
+	lastfmErr := cfg.ValidateLastFM()
+	if lastfmErr == nil {
+		backends = append(backends, scrobbler.NewWithSession(cfg.LastFM.APIKey, cfg.LastFM.APISecret, cfg.LastFM.SessionKey))
+	}
+
+	if cfg.ListenBrainz.Token != "" {
+		lb, err := scrobbler.NewListenBrainz(scrobbler.ListenBrainzConfig{
+			Token:   cfg.ListenBrainz.Token,
+			BaseURL: cfg.ListenBrainz.URL,
+		})
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, lb)
+	}
+
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		// Profiles that are not authenticated yet are left out
+		if cfg.ValidateProfile(name) != nil {
+			continue
+		}
+		p := cfg.Profiles[name]
+		backends = append(backends, scrobbler.NewForEndpoint(scrobbler.Endpoint{
+			Name:    name,
+			APIURL:  p.APIURL,
+			AuthURL: p.AuthURL,
+		}, p.APIKey, p.APISecret, p.SessionKey))
+	}
+
+	names = names[:0]
+	for name := range cfg.Webhooks {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		w := cfg.Webhooks[name]
+		wh, err := scrobbler.NewWebhook(scrobbler.WebhookConfig{
+			Name:       name,
+			URL:        w.URL,
+			Secret:     w.Secret,
+			Template:   w.Template,
+			Headers:    w.Headers,
+			NowPlaying: w.NowPlaying,
+		})
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, wh)
+	}
+
+	if len(backends) == 0 {
+		return nil, lastfmErr
+	}
+
+	return backends, nil
+}
+
 func runDaemonWithTUI(d *daemon.Daemon, musicClient music.Client, cfg *config.Config, logger zerolog.Logger) error {
-	// Enable TUI updates channel
-	updates := d.EnableTUI()
+	// Subscribe the TUI to the daemon's events
+	sub := d.Subscribe("tui", 16)
+	defer sub.Close()
 
 	// Create TUI config from app config
 	tuiCfg := tui.Config{
@@ -186,7 +281,7 @@ func runDaemonWithTUI(d *daemon.Daemon, musicClient music.Client, cfg *config.Co
 	}()
 
 	// Run TUI (blocks until user quits)
-	err := tuiApp.Run(ctx, updates, d.GetState, d.GetPlayedDuration)
+	err := tuiApp.Run(ctx, sub.Events(), d.GetState, d.GetPlayedDuration)
 
 	// Cancel context to signal daemon to stop
 	cancel()
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jfmyers9/scribbles/internal/config"
+)
+
+func TestNewBackends(t *testing.T) {
+	cfg := &config.Config{
+		LastFM: config.LastFMConfig{APIKey: "key", APISecret: "secret", SessionKey: "session"},
+		Profiles: map[string]config.ProfileConfig{
+			"librefm": {
+				APIURL: "https://libre.fm/2.0/", APIKey: "key", APISecret: "secret", SessionKey: "session",
+			},
+			"gnufm": {
+				APIURL: "https://gnufm.example.com/2.0/", APIKey: "key", APISecret: "secret", SessionKey: "session",
+			},
+			// Not authenticated yet
+			"maloja": {APIURL: "https://maloja.example.com/apis/audioscrobbler/", APIKey: "key", APISecret: "secret"},
+		},
+		Webhooks: map[string]config.WebhookConfig{
+			"warehouse": {URL: "https://warehouse.example.com/plays"},
+		},
+	}
+
+	backends, err := newBackends(cfg)
+	if err != nil {
+		t.Fatalf("newBackends: %v", err)
+	}
+
+	var names []string
+	for _, b := range backends {
+		names = append(names, b.Name())
+	}
+	if want := []string{"lastfm", "gnufm", "librefm", "webhook:warehouse"}; !slices.Equal(names, want) {
+		t.Errorf("expected backends %v, got %v", want, names)
+	}
+}
+
+func TestNewBackends_NoneConfigured(t *testing.T) {
+	if _, err := newBackends(&config.Config{}); err == nil {
+		t.Error("expected an error without any backend")
+	}
+}
+
+func TestNewBackends_InvalidWebhook(t *testing.T) {
+	cfg := &config.Config{
+		Webhooks: map[string]config.WebhookConfig{"ha": {URL: "http://ha.local", Template: "{{.Track"}},
+	}
+	if _, err := newBackends(cfg); err == nil {
+		t.Error("expected an error for an invalid webhook template")
+	}
+}
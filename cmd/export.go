@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/history"
+	"github.com/jfmyers9/scribbles/internal/listens"
+	"github.com/jfmyers9/scribbles/internal/scrobbler"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportDataDir string
+	exportFrom    string
+	exportFormat  string
+	exportFile    string
+	exportArtist  string
+	exportSince   string
+	exportUntil   string
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export listening history or the scrobble queue",
+	Long: `Export plays from the local listening history (default) or the scrobble
+queue, oldest first.
+
+Formats:
+  csv            - one row per play with a header row
+  jsonl          - one JSON object per line
+  listenbrainz   - a JSON array of ListenBrainz listens
+  scrobbler-log  - the .scrobbler.log format used by Rockbox and other
+                   portable players
+
+The format defaults to the --file extension (.csv, .jsonl, .json, .log),
+or csv when writing to stdout.`,
+	Example: `  scribbles export --file plays.csv
+  scribbles export --since 2025-01-01 --until 2026-01-01 --format listenbrainz > 2025.json
+  scribbles export --from queue --format jsonl`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVar(&exportDataDir, "data-dir", "", "Data directory for state, queue and history (default: ~/.local/share/scribbles)")
+	exportCmd.Flags().StringVar(&exportFrom, "from", "history", "What to export (history, queue)")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "", "Output format (csv, jsonl, listenbrainz, scrobbler-log)")
+	exportCmd.Flags().StringVarP(&exportFile, "file", "f", "", "Write to this file instead of stdout")
+	exportCmd.Flags().StringVar(&exportArtist, "artist", "", "Only plays by this artist (case-insensitive)")
+	exportCmd.Flags().StringVar(&exportSince, "since", "", "Only plays at or after this time")
+	exportCmd.Flags().StringVar(&exportUntil, "until", "", "Only plays before this time")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	format, err := transferFormat(exportFormat, exportFile, listens.FormatCSV)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	since, err := parseTimeFlag(exportSince, now)
+	if err != nil {
+		return fmt.Errorf("--since: %w", err)
+	}
+	until, err := parseTimeFlag(exportUntil, now)
+	if err != nil {
+		return fmt.Errorf("--until: %w", err)
+	}
+
+	var out []listens.Listen
+	switch exportFrom {
+	case "history":
+		out, err = exportHistory(history.Filter{Artist: exportArtist, Since: since, Until: until})
+	case "queue":
+		out, err = exportQueue(scrobbler.Filter{Artist: exportArtist, Since: since, Until: until})
+	default:
+		return fmt.Errorf("invalid --from %q (must be history or queue)", exportFrom)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Stores list newest first; interchange files are chronological
+	slices.Reverse(out)
+
+	var w io.Writer = cmd.OutOrStdout()
+	if exportFile != "" {
+		f, err := os.Create(exportFile)
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+
+	if err := listens.Write(w, format, out); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	if exportFile != "" {
+		fmt.Printf("Exported %d plays to %s\n", len(out), exportFile)
+	}
+	return nil
+}
+
+// transferFormat resolves the --format flag, falling back to detecting the
+// format from the file name, or def when there is no file
+func transferFormat(flag, path string, def listens.Format) (listens.Format, error) {
+	if flag != "" {
+		return listens.ParseFormat(flag)
+	}
+	if path == "" || path == "-" {
+		return def, nil
+	}
+	return listens.DetectFormat(path)
+}
+
+func exportHistory(filter history.Filter) ([]listens.Listen, error) {
+	store, err := openHistory(exportDataDir)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = store.Close() }()
+
+	plays, err := store.List(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]listens.Listen, len(plays))
+	for i, p := range plays {
+		out[i] = listens.Listen{
+			Artist:    p.Artist,
+			Track:     p.Track,
+			Album:     p.Album,
+			Duration:  p.Duration,
+			Timestamp: p.StartedAt,
+			Played:    p.Played,
+			Skipped:   p.Skipped,
+			Source:    p.Source,
+		}
+	}
+	return out, nil
+}
+
+func exportQueue(filter scrobbler.Filter) ([]listens.Listen, error) {
+	queue, err := openQueue(exportDataDir)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = queue.Close() }()
+
+	scrobbles, err := queue.List(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]listens.Listen, len(scrobbles))
+	for i, s := range scrobbles {
+		out[i] = listens.Listen{
+			Artist:    s.Artist,
+			Track:     s.TrackName,
+			Album:     s.Album,
+			Duration:  s.Duration,
+			Timestamp: s.Timestamp,
+		}
+	}
+	return out, nil
+}
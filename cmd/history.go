@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/history"
+	"github.com/mattn/go-runewidth"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyDataDir string
+	historyOutput  string
+	historyArtist  string
+	historyAlbum   string
+	historySince   string
+	historyUntil   string
+	historyLimit   int
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show your local listening history",
+	Long: `Show every play recorded by the daemon, newest first.
+
+The history (history.db in the data directory) is kept locally and
+permanently, independent of Last.fm: it includes skipped tracks and works
+completely offline. A play is marked as skipped when it ended before the
+scrobble point (half the track or 4 minutes).
+
+Time ranges accept a date (2026-03-01), a date and time ("2026-03-01 18:30"),
+or a duration ago (90m, 24h, 7d, 2w).`,
+	Example: `  scribbles history --since 7d
+  scribbles history --artist "Cher" --album "Believe"
+  scribbles history --since 2026-01-01 --limit 0 -o csv > plays.csv`,
+	Args: cobra.NoArgs,
+	RunE: runHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().StringVar(&historyDataDir, "data-dir", "", "Data directory for state and history (default: ~/.local/share/scribbles)")
+	historyCmd.Flags().StringVarP(&historyOutput, "output", "o", "text", "Output format (text, json, csv)")
+	historyCmd.Flags().StringVar(&historyArtist, "artist", "", "Filter by artist (case-insensitive)")
+	historyCmd.Flags().StringVar(&historyAlbum, "album", "", "Filter by album (case-insensitive)")
+	historyCmd.Flags().StringVar(&historySince, "since", "", "Only plays started at or after this time")
+	historyCmd.Flags().StringVar(&historyUntil, "until", "", "Only plays started before this time")
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 50, "Maximum number of plays (0 = all)")
+}
+
+// openHistory opens the listening history in the data directory
+func openHistory(dataDir string) (*history.Store, error) {
+	store, err := history.NewStore(filepath.Join(resolveDataDir(dataDir), "history.db"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	return store, nil
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	filter := history.Filter{
+		Artist: historyArtist,
+		Album:  historyAlbum,
+		Limit:  historyLimit,
+	}
+
+	var err error
+	if filter.Since, err = parseTimeFlag(historySince, now); err != nil {
+		return fmt.Errorf("--since: %w", err)
+	}
+	if filter.Until, err = parseTimeFlag(historyUntil, now); err != nil {
+		return fmt.Errorf("--until: %w", err)
+	}
+
+	store, err := openHistory(historyDataDir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	plays, err := store.List(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	switch historyOutput {
+	case "text":
+		return writeHistoryText(cmd.OutOrStdout(), plays)
+	case "json":
+		return writeHistoryJSON(cmd.OutOrStdout(), plays)
+	case "csv":
+		return writeHistoryCSV(cmd.OutOrStdout(), plays)
+	default:
+		return fmt.Errorf("invalid output format %q (must be text, json or csv)", historyOutput)
+	}
+}
+
+// writeHistoryJSON writes plays as a JSON array
+func writeHistoryJSON(w io.Writer, plays []history.Play) error {
+	if plays == nil {
+		plays = []history.Play{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(plays)
+}
+
+// writeHistoryCSV writes plays as CSV with a header row
+func writeHistoryCSV(w io.Writer, plays []history.Play) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{
+		"started_at", "artist", "track", "album",
+		"duration_seconds", "played_seconds", "skipped", "scrobbled", "source",
+	}); err != nil {
+		return err
+	}
+
+	for _, p := range plays {
+		if err := cw.Write([]string{
+			p.StartedAt.Format(time.RFC3339),
+			p.Artist,
+			p.Track,
+			p.Album,
+			strconv.Itoa(int(p.Duration.Seconds())),
+			strconv.Itoa(int(p.Played.Seconds())),
+			strconv.FormatBool(p.Skipped),
+			strconv.FormatBool(p.Scrobbled),
+			p.Source,
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeHistoryText writes plays as an aligned table
+func writeHistoryText(w io.Writer, plays []history.Play) error {
+	if len(plays) == 0 {
+		_, err := fmt.Fprintln(w, "No plays found")
+		return err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "STARTED\tARTIST\tTRACK\tALBUM\tPLAYED\t")
+	for _, p := range plays {
+		var skipped string
+		if p.Skipped {
+			skipped = "skipped"
+		}
+		fmt.Fprintf(&buf, "%s\t%s\t%s\t%s\t%s/%s\t%s\n",
+			p.StartedAt.Local().Format("2006-01-02 15:04"),
+			runewidth.Truncate(p.Artist, 24, "..."),
+			runewidth.Truncate(p.Track, 32, "..."),
+			runewidth.Truncate(p.Album, 24, "..."),
+			tuiFormatDuration(p.Played),
+			tuiFormatDuration(p.Duration),
+			skipped,
+		)
+	}
+
+	return writeTable(w, buf.Bytes())
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/history"
+)
+
+func testPlays() []history.Play {
+	started := time.Date(2026, 3, 1, 18, 30, 0, 0, time.UTC)
+	return []history.Play{
+		{
+			Track:     "Believe",
+			Artist:    "Cher",
+			Album:     "Believe",
+			Duration:  239 * time.Second,
+			Played:    239 * time.Second,
+			StartedAt: started,
+			Scrobbled: true,
+			Source:    "apple-music",
+		},
+		{
+			Track:     "Yesterday, \"Remastered\"",
+			Artist:    "The Beatles",
+			Duration:  125 * time.Second,
+			Played:    12 * time.Second,
+			StartedAt: started.Add(-time.Hour),
+			Skipped:   true,
+			Source:    "apple-music",
+		},
+	}
+}
+
+func TestWriteHistoryText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeHistoryText(&buf, testPlays()); err != nil {
+		t.Fatalf("writeHistoryText: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d lines:\n%s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[1], "03:59/03:59") || strings.Contains(lines[1], "skipped") {
+		t.Errorf("expected full play, got %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "00:12/02:05") || !strings.HasSuffix(lines[2], "skipped") {
+		t.Errorf("expected skipped play, got %q", lines[2])
+	}
+}
+
+func TestWriteHistoryText_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeHistoryText(&buf, nil); err != nil {
+		t.Fatalf("writeHistoryText: %v", err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "No plays found" {
+		t.Errorf("unexpected output for empty history: %q", got)
+	}
+}
+
+func TestWriteHistoryCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeHistoryCSV(&buf, testPlays()); err != nil {
+		t.Fatalf("writeHistoryCSV: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header and 2 records, got %d", len(records))
+	}
+	if records[0][0] != "started_at" || records[0][8] != "source" {
+		t.Errorf("unexpected header: %v", records[0])
+	}
+
+	want := []string{"2026-03-01T17:30:00Z", "The Beatles", "Yesterday, \"Remastered\"", "", "125", "12", "true", "false", "apple-music"}
+	for i := range want {
+		if records[2][i] != want[i] {
+			t.Errorf("field %d: expected %q, got %q", i, want[i], records[2][i])
+		}
+	}
+}
+
+func TestWriteHistoryJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeHistoryJSON(&buf, testPlays()); err != nil {
+		t.Fatalf("writeHistoryJSON: %v", err)
+	}
+
+	var entries []struct {
+		Track     string `json:"track"`
+		Played    int    `json:"played_seconds"`
+		Skipped   bool   `json:"skipped"`
+		Scrobbled bool   `json:"scrobbled"`
+		Source    string `json:"source"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if e := entries[0]; e.Track != "Believe" || e.Played != 239 || !e.Scrobbled || e.Skipped {
+		t.Errorf("unexpected first entry: %+v", e)
+	}
+	if e := entries[1]; !e.Skipped || e.Played != 12 || e.Source != "apple-music" {
+		t.Errorf("unexpected second entry: %+v", e)
+	}
+}
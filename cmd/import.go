@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jfmyers9/scribbles/internal/history"
+	"github.com/jfmyers9/scribbles/internal/listens"
+	"github.com/jfmyers9/scribbles/internal/scrobbler"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importDataDir string
+	importFormat  string
+	importTo      string
+	importDryRun  bool
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import plays into the scrobble queue or listening history",
+	Long: `Import plays from a file ("-" for stdin).
+
+By default plays are added to the scrobble queue, and the daemon submits
+them to Last.fm on its next pass. This is how to scrobble plays from
+portable players and car stereos that write a .scrobbler.log. Skipped plays
+are not queued, and plays already in the queue or the listening history
+(same artist, track and time) are left out, so importing the same file
+twice is safe. Note that Last.fm ignores scrobbles more than two weeks old;
+see "scribbles queue list --status ignored" after importing.
+
+With --to history, plays are added to the local listening history instead,
+for "scribbles history" and "scribbles stats". Plays without a played
+duration are assumed to have been played in full unless skipped.
+
+Formats are the same as for "scribbles export" and are detected from the
+file extension unless --format is given.`,
+	Example: `  scribbles import /Volumes/SANSA/.scrobbler.log
+  scribbles import listens.json --to history
+  scribbles import plays.csv --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringVar(&importDataDir, "data-dir", "", "Data directory for state, queue and history (default: ~/.local/share/scribbles)")
+	importCmd.Flags().StringVar(&importFormat, "format", "", "Input format (csv, jsonl, listenbrainz, scrobbler-log)")
+	importCmd.Flags().StringVar(&importTo, "to", "queue", "Where to import plays (queue, history)")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Report what would be imported without changing anything")
+}
+
+// importResult counts the outcome of an import
+type importResult struct {
+	Imported   int
+	Duplicates int
+	Skipped    int
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	format, err := transferFormat(importFormat, path, "")
+	if err != nil {
+		return err
+	}
+	if format == "" {
+		return fmt.Errorf("--format is required when reading from stdin")
+	}
+
+	var r io.Reader = cmd.InOrStdin()
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open import file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	in, err := listens.Read(r, format)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	plays, err := openHistory(importDataDir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = plays.Close() }()
+
+	ctx := context.Background()
+	var result importResult
+	switch importTo {
+	case "queue":
+		queue, err := openQueue(importDataDir)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = queue.Close() }()
+
+		result, err = importIntoQueue(ctx, queue, plays, in, importDryRun)
+		if err != nil {
+			return err
+		}
+	case "history":
+		result, err = importIntoHistory(ctx, plays, in, importDryRun)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid --to %q (must be queue or history)", importTo)
+	}
+
+	verb := "Imported"
+	if importDryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %d of %d plays into the %s (%d duplicates, %d skipped)\n",
+		verb, result.Imported, len(in), importTo, result.Duplicates, result.Skipped)
+	return nil
+}
+
+// importIntoQueue queues listens for scrobbling, leaving out skipped
+// listens and those already in the queue or history
+func importIntoQueue(ctx context.Context, queue *scrobbler.Queue, plays *history.Store, in []listens.Listen, dryRun bool) (importResult, error) {
+	var result importResult
+	seen := make(map[string]bool)
+	for _, l := range in {
+		if l.Skipped {
+			result.Skipped++
+			continue
+		}
+		if seen[listenKey(l)] {
+			result.Duplicates++
+			continue
+		}
+		seen[listenKey(l)] = true
+
+		s := scrobbler.Scrobble{
+			Artist:    l.Artist,
+			Track:     l.Track,
+			Album:     l.Album,
+			Duration:  l.Duration,
+			Timestamp: l.Timestamp,
+		}
+
+		queued, err := queue.Has(ctx, s)
+		if err != nil {
+			return result, err
+		}
+		played, err := plays.Has(ctx, l.Artist, l.Track, l.Timestamp)
+		if err != nil {
+			return result, err
+		}
+		if queued || played {
+			result.Duplicates++
+			continue
+		}
+
+		if !dryRun {
+			if _, err := queue.Add(ctx, s); err != nil {
+				return result, err
+			}
+		}
+		result.Imported++
+	}
+	return result, nil
+}
+
+// importIntoHistory adds listens to the listening history, leaving out
+// those already there
+func importIntoHistory(ctx context.Context, plays *history.Store, in []listens.Listen, dryRun bool) (importResult, error) {
+	var result importResult
+	seen := make(map[string]bool)
+	for _, l := range in {
+		if seen[listenKey(l)] {
+			result.Duplicates++
+			continue
+		}
+		seen[listenKey(l)] = true
+
+		exists, err := plays.Has(ctx, l.Artist, l.Track, l.Timestamp)
+		if err != nil {
+			return result, err
+		}
+		if exists {
+			result.Duplicates++
+			continue
+		}
+
+		p := history.Play{
+			Track:     l.Track,
+			Artist:    l.Artist,
+			Album:     l.Album,
+			Duration:  l.Duration,
+			Played:    l.Played,
+			StartedAt: l.Timestamp,
+			Skipped:   l.Skipped,
+			Source:    l.Source,
+		}
+		if p.Played == 0 && !p.Skipped {
+			p.Played = p.Duration
+		}
+		if p.Source == "" {
+			p.Source = "import"
+		}
+
+		if !dryRun {
+			if _, err := plays.Add(ctx, p); err != nil {
+				return result, err
+			}
+		}
+		result.Imported++
+	}
+	return result, nil
+}
+
+// listenKey identifies a listen for deduplication within a single import,
+// matching the Has lookups of the queue and history
+func listenKey(l listens.Listen) string {
+	return fmt.Sprintf("%s\x00%s\x00%d", strings.ToLower(l.Artist), strings.ToLower(l.Track), l.Timestamp.Unix())
+}
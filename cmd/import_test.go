@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/history"
+	"github.com/jfmyers9/scribbles/internal/listens"
+	"github.com/jfmyers9/scribbles/internal/scrobbler"
+)
+
+func newImportStores(t *testing.T) (*scrobbler.Queue, *history.Store) {
+	t.Helper()
+
+	queue, err := scrobbler.NewQueue(":memory:")
+	if err != nil {
+		t.Fatalf("NewQueue: %v", err)
+	}
+	t.Cleanup(func() { _ = queue.Close() })
+
+	plays, err := history.NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { _ = plays.Close() })
+
+	return queue, plays
+}
+
+func importFixture() []listens.Listen {
+	ts := time.Date(2026, 3, 1, 18, 40, 0, 0, time.UTC)
+	return []listens.Listen{
+		{Artist: "The Beatles", Track: "Yesterday", Album: "Help!", Duration: 125 * time.Second, Timestamp: ts},
+		{Artist: "Cher", Track: "Believe", Duration: 239 * time.Second, Timestamp: ts.Add(2 * time.Minute), Skipped: true},
+		{Artist: "Portishead", Track: "Roads", Duration: 305 * time.Second, Timestamp: ts.Add(3 * time.Minute)},
+		{Artist: "portishead", Track: "roads", Duration: 305 * time.Second, Timestamp: ts.Add(3 * time.Minute)},
+		{Artist: "Madonna", Track: "Frozen", Duration: 372 * time.Second, Timestamp: ts.Add(9 * time.Minute)},
+	}
+}
+
+func TestImportIntoQueue(t *testing.T) {
+	queue, plays := newImportStores(t)
+	ctx := context.Background()
+	in := importFixture()
+
+	// Already scrobbled by the daemon and recorded in history
+	if _, err := plays.Add(ctx, history.Play{Artist: "Madonna", Track: "Frozen", StartedAt: in[4].Timestamp, Scrobbled: true}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	dry, err := importIntoQueue(ctx, queue, plays, in, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if count, _ := queue.Count(ctx, false); count != 0 {
+		t.Errorf("expected dry run to leave the queue empty, got %d", count)
+	}
+
+	result, err := importIntoQueue(ctx, queue, plays, in, false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	want := importResult{Imported: 2, Duplicates: 2, Skipped: 1}
+	if result != want || dry != want {
+		t.Errorf("expected %+v, got %+v (dry run %+v)", want, result, dry)
+	}
+
+	pending, err := queue.GetPending(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetPending: %v", err)
+	}
+	if len(pending) != 2 || pending[0].TrackName != "Yesterday" || pending[0].Album != "Help!" || pending[1].TrackName != "Roads" {
+		t.Errorf("unexpected queued scrobbles: %+v", pending)
+	}
+
+	again, err := importIntoQueue(ctx, queue, plays, in, false)
+	if err != nil {
+		t.Fatalf("second import: %v", err)
+	}
+	if again.Imported != 0 || again.Duplicates != 4 {
+		t.Errorf("expected re-import to be a no-op, got %+v", again)
+	}
+}
+
+func TestImportIntoHistory(t *testing.T) {
+	_, plays := newImportStores(t)
+	ctx := context.Background()
+
+	result, err := importIntoHistory(ctx, plays, importFixture(), false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if want := (importResult{Imported: 4, Duplicates: 1}); result != want {
+		t.Errorf("expected %+v, got %+v", want, result)
+	}
+
+	got, err := plays.List(ctx, history.Filter{Artist: "The Beatles"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(got) != 1 || got[0].Played != 125*time.Second || got[0].Source != "import" {
+		t.Errorf("expected a full play from an unknown source, got %+v", got)
+	}
+
+	skipped, err := plays.List(ctx, history.Filter{Artist: "Cher"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(skipped) != 1 || !skipped[0].Skipped || skipped[0].Played != 0 {
+		t.Errorf("expected skipped play to keep an unknown played duration, got %+v", skipped)
+	}
+}
+
+func TestTransferFormat(t *testing.T) {
+	tests := []struct {
+		flag, path string
+		want       listens.Format
+		wantErr    bool
+	}{
+		{flag: "jsonl", path: "plays.csv", want: listens.FormatJSONL},
+		{path: ".scrobbler.log", want: listens.FormatScrobblerLog},
+		{path: "", want: listens.FormatCSV},
+		{path: "-", want: listens.FormatCSV},
+		{path: "plays.txt", wantErr: true},
+		{flag: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := transferFormat(tt.flag, tt.path, listens.FormatCSV)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("transferFormat(%q, %q) = %q, %v; want %q (error %v)", tt.flag, tt.path, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
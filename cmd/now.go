@@ -18,11 +18,14 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var nowDataDir string
+
 // nowCmd represents the now command
 var nowCmd = &cobra.Command{
 	Use:   "now",
 	Short: "Display currently playing track from Apple Music",
-	Long: `Query Apple Music and display the currently playing track.
+	Long: `Display the currently playing track. Asks the running daemon when there is
+one, and queries Apple Music directly otherwise.
 
 The output format can be customized in ~/.config/scribbles/config.yaml
 using a Go template. Available fields: .Name, .Artist, .Album, .Duration, .Position
@@ -42,6 +45,7 @@ func init() {
 	nowCmd.Flags().IntP("width", "w", 0, "Fixed output width (0=disabled, overrides config)")
 	// Add marquee flag to enable scrolling
 	nowCmd.Flags().Bool("marquee", false, "Enable marquee scrolling for long text (overrides config)")
+	nowCmd.Flags().StringVar(&nowDataDir, "data-dir", "", "Data directory of the daemon (default: ~/.local/share/scribbles)")
 }
 
 func runNow(cmd *cobra.Command, args []string) error {
@@ -61,17 +65,17 @@ func runNow(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create music client
-	client := music.NewAppleScriptClient()
+	client := newMusicClient(cfg)
 
-	// Get current track
-	track, err := client.GetCurrentTrack(ctx)
+	// Get current track, preferring the daemon's view
+	track, err := currentTrack(ctx, controlSocketPath(nowDataDir), client)
 	if err != nil {
 		// If Music app is not running or other error, exit with code 1
 		return fmt.Errorf("failed to get current track: %w", err)
 	}
 
 	// If not playing, exit with code 1
-	if track.State != music.StatePlaying {
+	if track == nil || track.State != music.StatePlaying {
 		os.Exit(1)
 		return nil
 	}
@@ -0,0 +1,471 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/config"
+	"github.com/jfmyers9/scribbles/internal/scrobbler"
+	"github.com/mattn/go-runewidth"
+	"github.com/spf13/cobra"
+)
+
+var (
+	queueDataDir string
+	queueOutput  string
+
+	queueStatus string
+	queueArtist string
+	queueSince  string
+	queueUntil  string
+	queueLimit  int
+
+	queueEditArtist string
+	queueEditTrack  string
+	queueEditAlbum  string
+)
+
+// queueCmd represents the queue command
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Inspect and manage the scrobble queue",
+	Long: `Inspect and manage the scrobble queue (queue.db in the data directory).
+
+Every scrobble the daemon records passes through the queue and is delivered to
+each configured backend (Last.fm, ListenBrainz) independently. Each delivery,
+and each entry as a whole, is in one of these states:
+  pending   - waiting to be submitted, possibly after a retry backoff
+  scrobbled - accepted by the backend (by every backend, for an entry)
+  ignored   - rejected by the backend with an ignore code (e.g. timestamp too old)
+  failed    - out of retries or rejected with a permanent error
+
+An entry stays pending while any backend is still owed it; the OWED BY column
+lists those backends.
+
+These commands are safe to use while the daemon is running.`,
+}
+
+var queueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List queued scrobbles",
+	Long: `List queued scrobbles, newest first.
+
+Time ranges accept a date (2026-03-01), a date and time ("2026-03-01 18:30"),
+or a duration ago (90m, 24h, 7d, 2w).`,
+	Args: cobra.NoArgs,
+	RunE: runQueueList,
+}
+
+var queueShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a queued scrobble in detail",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runQueueShow,
+}
+
+var queueRetryCmd = &cobra.Command{
+	Use:   "retry <id>...",
+	Short: "Clear errors and resubmit scrobbles",
+	Long: `Clear the error, attempt count and failed or ignored state of the given
+scrobbles so the daemon submits them again on its next pass.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runQueueRetry,
+}
+
+var queueDropCmd = &cobra.Command{
+	Use:   "drop <id>...",
+	Short: "Remove scrobbles from the queue",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runQueueDrop,
+}
+
+var queueEditCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Fix the artist, track or album of a scrobble",
+	Long: `Fix the artist, track or album of a scrobble that has not been scrobbled
+yet. The entry's error is cleared and it is resubmitted on the daemon's next
+pass.`,
+	Example: `  scribbles queue edit 42 --artist "Guns N' Roses"`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runQueueEdit,
+}
+
+var queuePurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Delete all scrobbles matching a filter",
+	Long: `Delete all scrobbles matching the given filters.
+
+At least one filter is required so the whole queue is never purged by
+accident.`,
+	Example: `  scribbles queue purge --status scrobbled --until 30d
+  scribbles queue purge --status failed --artist "Unknown Artist"`,
+	Args: cobra.NoArgs,
+	RunE: runQueuePurge,
+}
+
+func init() {
+	rootCmd.AddCommand(queueCmd)
+	queueCmd.AddCommand(queueListCmd, queueShowCmd, queueRetryCmd, queueDropCmd, queueEditCmd, queuePurgeCmd)
+
+	queueCmd.PersistentFlags().StringVar(&queueDataDir, "data-dir", "", "Data directory for state and queue (default: ~/.local/share/scribbles)")
+
+	for _, c := range []*cobra.Command{queueListCmd, queueShowCmd} {
+		c.Flags().StringVarP(&queueOutput, "output", "o", "table", "Output format (table, json)")
+	}
+
+	for _, c := range []*cobra.Command{queueListCmd, queuePurgeCmd} {
+		c.Flags().StringVar(&queueStatus, "status", "", "Filter by status (pending, scrobbled, ignored, failed)")
+		c.Flags().StringVar(&queueArtist, "artist", "", "Filter by artist (case-insensitive)")
+		c.Flags().StringVar(&queueSince, "since", "", "Only scrobbles played at or after this time")
+		c.Flags().StringVar(&queueUntil, "until", "", "Only scrobbles played before this time")
+	}
+	queueListCmd.Flags().IntVarP(&queueLimit, "limit", "n", 50, "Maximum number of entries (0 = all)")
+
+	queueEditCmd.Flags().StringVar(&queueEditArtist, "artist", "", "New artist name")
+	queueEditCmd.Flags().StringVar(&queueEditTrack, "track", "", "New track name")
+	queueEditCmd.Flags().StringVar(&queueEditAlbum, "album", "", "New album name")
+}
+
+// resolveDataDir returns the --data-dir flag value, or the default data
+// directory if it is unset
+func resolveDataDir(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	return config.GetDataDir()
+}
+
+// openQueue opens the scrobble queue in the data directory
+func openQueue(dataDir string) (*scrobbler.Queue, error) {
+	queue, err := scrobbler.NewQueue(filepath.Join(resolveDataDir(dataDir), "queue.db"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open queue: %w", err)
+	}
+	return queue, nil
+}
+
+// queueFilter builds a filter from the list/purge flags
+func queueFilter() (scrobbler.Filter, error) {
+	var filter scrobbler.Filter
+	var err error
+
+	if queueStatus != "" {
+		if filter.Status, err = scrobbler.ParseStatus(queueStatus); err != nil {
+			return filter, err
+		}
+	}
+	filter.Artist = queueArtist
+
+	now := time.Now()
+	if filter.Since, err = parseTimeFlag(queueSince, now); err != nil {
+		return filter, fmt.Errorf("--since: %w", err)
+	}
+	if filter.Until, err = parseTimeFlag(queueUntil, now); err != nil {
+		return filter, fmt.Errorf("--until: %w", err)
+	}
+
+	return filter, nil
+}
+
+// parseQueueIDs parses scrobble ID arguments
+func parseQueueIDs(args []string) ([]int64, error) {
+	ids := make([]int64, len(args))
+	for i, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrobble id %q", arg)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func runQueueList(cmd *cobra.Command, args []string) error {
+	filter, err := queueFilter()
+	if err != nil {
+		return err
+	}
+	filter.Limit = queueLimit
+
+	queue, err := openQueue(queueDataDir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = queue.Close() }()
+
+	scrobbles, err := queue.List(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	switch queueOutput {
+	case "json":
+		return writeQueueJSON(cmd.OutOrStdout(), scrobbles)
+	case "table":
+		return writeQueueTable(cmd.OutOrStdout(), scrobbles)
+	default:
+		return fmt.Errorf("invalid output format %q (must be table or json)", queueOutput)
+	}
+}
+
+func runQueueShow(cmd *cobra.Command, args []string) error {
+	ids, err := parseQueueIDs(args)
+	if err != nil {
+		return err
+	}
+
+	queue, err := openQueue(queueDataDir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = queue.Close() }()
+
+	s, err := queue.Get(context.Background(), ids[0])
+	if err != nil {
+		return err
+	}
+
+	switch queueOutput {
+	case "json":
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(s)
+	case "table":
+		return writeQueueDetail(cmd.OutOrStdout(), *s)
+	default:
+		return fmt.Errorf("invalid output format %q (must be table or json)", queueOutput)
+	}
+}
+
+func runQueueRetry(cmd *cobra.Command, args []string) error {
+	ids, err := parseQueueIDs(args)
+	if err != nil {
+		return err
+	}
+
+	queue, err := openQueue(queueDataDir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = queue.Close() }()
+
+	for _, id := range ids {
+		if err := queue.Retry(context.Background(), id); err != nil {
+			return err
+		}
+		fmt.Printf("Scheduled scrobble %d for resubmission\n", id)
+	}
+
+	return nil
+}
+
+func runQueueDrop(cmd *cobra.Command, args []string) error {
+	ids, err := parseQueueIDs(args)
+	if err != nil {
+		return err
+	}
+
+	queue, err := openQueue(queueDataDir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = queue.Close() }()
+
+	for _, id := range ids {
+		if err := queue.Delete(context.Background(), id); err != nil {
+			return err
+		}
+		fmt.Printf("Dropped scrobble %d\n", id)
+	}
+
+	return nil
+}
+
+func runQueueEdit(cmd *cobra.Command, args []string) error {
+	ids, err := parseQueueIDs(args)
+	if err != nil {
+		return err
+	}
+
+	var edit scrobbler.ScrobbleEdit
+	if cmd.Flags().Changed("artist") {
+		edit.Artist = &queueEditArtist
+	}
+	if cmd.Flags().Changed("track") {
+		edit.Track = &queueEditTrack
+	}
+	if cmd.Flags().Changed("album") {
+		edit.Album = &queueEditAlbum
+	}
+	if edit.Artist == nil && edit.Track == nil && edit.Album == nil {
+		return fmt.Errorf("nothing to edit: pass --artist, --track and/or --album")
+	}
+
+	queue, err := openQueue(queueDataDir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = queue.Close() }()
+
+	ctx := context.Background()
+	if err := queue.Edit(ctx, ids[0], edit); err != nil {
+		return err
+	}
+
+	s, err := queue.Get(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Updated scrobble %d: %s - %s", s.ID, s.Artist, s.TrackName)
+	if s.Album != "" {
+		fmt.Printf(" (%s)", s.Album)
+	}
+	fmt.Println()
+
+	return nil
+}
+
+func runQueuePurge(cmd *cobra.Command, args []string) error {
+	filter, err := queueFilter()
+	if err != nil {
+		return err
+	}
+	if filter == (scrobbler.Filter{}) {
+		return fmt.Errorf("refusing to purge the whole queue: pass --status, --artist, --since or --until")
+	}
+
+	queue, err := openQueue(queueDataDir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = queue.Close() }()
+
+	deleted, err := queue.Purge(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Purged %d scrobbles\n", deleted)
+	return nil
+}
+
+// writeQueueJSON writes scrobbles as a JSON array
+func writeQueueJSON(w io.Writer, scrobbles []scrobbler.QueuedScrobble) error {
+	if scrobbles == nil {
+		scrobbles = []scrobbler.QueuedScrobble{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(scrobbles)
+}
+
+// writeQueueTable writes scrobbles as an aligned table
+func writeQueueTable(w io.Writer, scrobbles []scrobbler.QueuedScrobble) error {
+	if len(scrobbles) == 0 {
+		_, err := fmt.Fprintln(w, "No scrobbles found")
+		return err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "ID\tSTATUS\tPLAYED\tARTIST\tTRACK\tATTEMPTS\tOWED BY\tREASON")
+	for _, s := range scrobbles {
+		fmt.Fprintf(&buf, "%d\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			s.ID,
+			s.Status(),
+			s.Timestamp.Local().Format("2006-01-02 15:04"),
+			runewidth.Truncate(s.Artist, 24, "..."),
+			runewidth.Truncate(s.TrackName, 32, "..."),
+			s.Attempts,
+			queueOwedBy(s),
+			runewidth.Truncate(queueReason(s), 48, "..."),
+		)
+	}
+
+	return writeTable(w, buf.Bytes())
+}
+
+// writeQueueDetail writes every field of a single scrobble
+func writeQueueDetail(w io.Writer, s scrobbler.QueuedScrobble) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "ID:\t%d\n", s.ID)
+	fmt.Fprintf(&buf, "Status:\t%s\n", s.Status())
+	fmt.Fprintf(&buf, "Artist:\t%s\n", s.Artist)
+	fmt.Fprintf(&buf, "Track:\t%s\n", s.TrackName)
+	fmt.Fprintf(&buf, "Album:\t%s\n", s.Album)
+	fmt.Fprintf(&buf, "Duration:\t%s\n", s.Duration)
+	fmt.Fprintf(&buf, "Played:\t%s\n", s.Timestamp.Local().Format(time.RFC1123))
+	if s.Source != "" {
+		fmt.Fprintf(&buf, "Source:\t%s\n", s.Source)
+	}
+	fmt.Fprintf(&buf, "Attempts:\t%d\n", s.Attempts)
+	if s.Status() == scrobbler.StatusPending && !s.NextAttemptAt.IsZero() {
+		fmt.Fprintf(&buf, "Next attempt:\t%s\n", s.NextAttemptAt.Local().Format(time.RFC1123))
+	}
+	if s.Error != "" {
+		fmt.Fprintf(&buf, "Error:\t%s\n", s.Error)
+	}
+	if s.Ignored {
+		fmt.Fprintf(&buf, "Ignored:\t%s (code %d)\n", s.IgnoredMessage, s.IgnoredCode)
+	}
+	for i, d := range s.Deliveries {
+		label := ""
+		if i == 0 {
+			label = "Deliveries:"
+		}
+		fmt.Fprintf(&buf, "%s\t%s\t%s\t%s\n", label, d.Backend, d.Status, deliveryDetail(d))
+	}
+
+	return writeTable(w, buf.Bytes())
+}
+
+// writeTable aligns tab-separated rows into columns
+func writeTable(w io.Writer, rows []byte) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := tw.Write(rows); err != nil {
+		return err
+	}
+	return tw.Flush()
+}
+
+// queueOwedBy lists the backends still owed a scrobble, or "-" if none are
+func queueOwedBy(s scrobbler.QueuedScrobble) string {
+	owed := s.Owed()
+	if len(owed) == 0 {
+		return "-"
+	}
+	return strings.Join(owed, ",")
+}
+
+// deliveryDetail summarises a delivery's attempts and outcome
+func deliveryDetail(d scrobbler.Delivery) string {
+	var parts []string
+	if d.Attempts > 0 {
+		parts = append(parts, fmt.Sprintf("%d attempts", d.Attempts))
+	}
+	if d.Status == scrobbler.StatusPending && !d.NextAttemptAt.IsZero() {
+		parts = append(parts, "next "+d.NextAttemptAt.Local().Format("2006-01-02 15:04"))
+	}
+	if d.Status == scrobbler.StatusIgnored {
+		parts = append(parts, fmt.Sprintf("ignored (%d): %s", d.IgnoredCode, d.IgnoredMessage))
+	} else if d.Error != "" {
+		parts = append(parts, d.Error)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// queueReason summarises why a scrobble is not (yet) scrobbled
+func queueReason(s scrobbler.QueuedScrobble) string {
+	if s.Ignored {
+		return fmt.Sprintf("ignored (%d): %s", s.IgnoredCode, s.IgnoredMessage)
+	}
+	return s.Error
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/scrobbler"
+)
+
+func testQueuedScrobbles() []scrobbler.QueuedScrobble {
+	played := time.Date(2026, 3, 1, 18, 30, 0, 0, time.UTC)
+	return []scrobbler.QueuedScrobble{
+		{
+			ID:            2,
+			TrackName:     "Believe",
+			Artist:        "Cher",
+			Album:         "Believe",
+			Duration:      239 * time.Second,
+			Timestamp:     played,
+			Source:        "navidrome",
+			Error:         "failed to scrobble batch: timeout",
+			Attempts:      2,
+			NextAttemptAt: played.Add(4 * time.Minute),
+			Deliveries: []scrobbler.Delivery{
+				{Backend: "lastfm", Status: scrobbler.StatusScrobbled},
+				{
+					Backend:       "listenbrainz",
+					Status:        scrobbler.StatusPending,
+					Attempts:      2,
+					NextAttemptAt: played.Add(4 * time.Minute),
+					Error:         "failed to scrobble batch: timeout",
+				},
+			},
+		},
+		{
+			ID:             1,
+			TrackName:      "Yesterday",
+			Artist:         "The Beatles",
+			Duration:       125 * time.Second,
+			Timestamp:      played.Add(-time.Hour),
+			Ignored:        true,
+			IgnoredCode:    3,
+			IgnoredMessage: "Timestamp too old",
+			Deliveries: []scrobbler.Delivery{
+				{Backend: "lastfm", Status: scrobbler.StatusIgnored, IgnoredCode: 3, IgnoredMessage: "Timestamp too old"},
+			},
+		},
+	}
+}
+
+func TestWriteQueueTable(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeQueueTable(&buf, testQueuedScrobbles()); err != nil {
+		t.Fatalf("writeQueueTable: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d lines:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[0], "REASON") {
+		t.Errorf("unexpected header: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "pending") || !strings.Contains(lines[1], "timeout") {
+		t.Errorf("expected pending row with its error, got %q", lines[1])
+	}
+	if fields := strings.Fields(lines[1]); !slices.Contains(fields, "listenbrainz") || slices.Contains(fields, "lastfm") {
+		t.Errorf("expected only listenbrainz to be owed, got %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "ignored") || !strings.Contains(lines[2], "ignored (3): Timestamp too old") {
+		t.Errorf("expected ignored row with its reason, got %q", lines[2])
+	}
+}
+
+func TestWriteQueueTable_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeQueueTable(&buf, nil); err != nil {
+		t.Fatalf("writeQueueTable: %v", err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "No scrobbles found" {
+		t.Errorf("unexpected output for empty queue: %q", got)
+	}
+}
+
+func TestWriteQueueJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeQueueJSON(&buf, testQueuedScrobbles()); err != nil {
+		t.Fatalf("writeQueueJSON: %v", err)
+	}
+
+	var entries []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	first := entries[0]
+	if first["status"] != "pending" || first["track"] != "Believe" || first["duration_seconds"] != float64(239) ||
+		first["source"] != "navidrome" {
+		t.Errorf("unexpected first entry: %v", first)
+	}
+	if _, ok := first["next_attempt_at"]; !ok {
+		t.Error("expected next_attempt_at for a pending retry")
+	}
+	deliveries, _ := first["deliveries"].([]any)
+	if len(deliveries) != 2 {
+		t.Fatalf("expected 2 deliveries, got %v", first["deliveries"])
+	}
+	if lb, _ := deliveries[1].(map[string]any); lb["backend"] != "listenbrainz" || lb["status"] != "pending" || lb["attempts"] != float64(2) {
+		t.Errorf("unexpected listenbrainz delivery: %v", deliveries[1])
+	}
+
+	second := entries[1]
+	if second["status"] != "ignored" || second["ignored_code"] != float64(3) {
+		t.Errorf("unexpected second entry: %v", second)
+	}
+	if _, ok := second["album"]; ok {
+		t.Error("expected empty album to be omitted")
+	}
+	if _, ok := second["source"]; ok {
+		t.Error("expected an unknown source to be omitted")
+	}
+}
+
+func TestWriteQueueDetail_Deliveries(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeQueueDetail(&buf, testQueuedScrobbles()[0]); err != nil {
+		t.Fatalf("writeQueueDetail: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"Deliveries:", "lastfm", "scrobbled", "listenbrainz", "2 attempts; next"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in detail:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/daemon"
+	"github.com/jfmyers9/scribbles/internal/music"
+	"github.com/mattn/go-runewidth"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scrobblingDataDir string
+	scrobblingOutput  string
+)
+
+// scrobblingCmd represents the scrobbling command
+var scrobblingCmd = &cobra.Command{
+	Use:   "scrobbling",
+	Short: "Inspect and control the running daemon",
+	Long: `Inspect and control the running daemon through its control socket
+(control.sock in the data directory).
+
+Without a subcommand, shows the daemon's status: the current track, how long
+it has played and when it will be scrobbled, the number of pending scrobbles
+and the latest plays.`,
+	Args: cobra.NoArgs,
+	RunE: runScrobblingStatus,
+}
+
+var scrobblingSkipCmd = &cobra.Command{
+	Use:   "skip",
+	Short: "Don't scrobble the current track",
+	Long:  `Don't scrobble the current play of the current track. The next track is scrobbled as usual.`,
+	Args:  cobra.NoArgs,
+	RunE:  runScrobblingCommand(daemon.MethodSkipScrobble, "The current track will not be scrobbled"),
+}
+
+var scrobblingForceCmd = &cobra.Command{
+	Use:   "force",
+	Short: "Scrobble the current track now",
+	Long:  `Queue the current track for scrobbling now, however long it has played.`,
+	Args:  cobra.NoArgs,
+	RunE:  runScrobblingCommand(daemon.MethodForceScrobble, "Scrobbled the current track"),
+}
+
+var scrobblingLoveCmd = &cobra.Command{
+	Use:   "love",
+	Short: "Love the current track",
+	Long:  `Love the current track on Last.fm and every Audioscrobbler-compatible profile, and star it on a Subsonic source playing it.`,
+	Args:  cobra.NoArgs,
+	RunE:  runScrobblingCommand(daemon.MethodLove, "Loved the current track"),
+}
+
+var scrobblingPauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pause scrobbling",
+	Long: `Stop queueing scrobbles until 'scribbles scrobbling resume' or the daemon
+restarts. Plays are still recorded in the listening history.`,
+	Args: cobra.NoArgs,
+	RunE: runScrobblingCommand(daemon.MethodPause, "Scrobbling paused"),
+}
+
+var scrobblingResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume scrobbling",
+	Args:  cobra.NoArgs,
+	RunE:  runScrobblingCommand(daemon.MethodResume, "Scrobbling resumed"),
+}
+
+func init() {
+	rootCmd.AddCommand(scrobblingCmd)
+	scrobblingCmd.AddCommand(scrobblingSkipCmd, scrobblingForceCmd, scrobblingLoveCmd, scrobblingPauseCmd, scrobblingResumeCmd)
+
+	scrobblingCmd.PersistentFlags().StringVar(&scrobblingDataDir, "data-dir", "", "Data directory of the daemon (default: ~/.local/share/scribbles)")
+	scrobblingCmd.Flags().StringVarP(&scrobblingOutput, "output", "o", "text", "Output format (text, json)")
+}
+
+// controlSocketPath returns the daemon's control socket in the data directory
+func controlSocketPath(dataDir string) string {
+	return filepath.Join(resolveDataDir(dataDir), daemon.ControlSocketFile)
+}
+
+// dialDaemon connects to the running daemon's control socket
+func dialDaemon(dataDir string) (*daemon.ControlClient, error) {
+	client, err := daemon.DialControl(controlSocketPath(dataDir))
+	if err != nil {
+		return nil, fmt.Errorf("is the daemon running? %w", err)
+	}
+	return client, nil
+}
+
+// currentTrack asks the daemon listening on socketPath for the current
+// track, and polls the music player directly if no daemon answers
+func currentTrack(ctx context.Context, socketPath string, client music.Client) (*music.Track, error) {
+	if dc, err := daemon.DialControl(socketPath); err == nil {
+		defer func() { _ = dc.Close() }()
+		if track, err := dc.CurrentTrack(ctx); err == nil {
+			return track, nil
+		}
+	}
+	return client.GetCurrentTrack(ctx)
+}
+
+func runScrobblingStatus(cmd *cobra.Command, args []string) error {
+	if scrobblingOutput != "text" && scrobblingOutput != "json" {
+		return fmt.Errorf("invalid output format %q (expected text or json)", scrobblingOutput)
+	}
+
+	client, err := dialDaemon(scrobblingDataDir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	status, err := client.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get daemon status: %w", err)
+	}
+
+	if scrobblingOutput == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
+	}
+	return writeDaemonStatus(os.Stdout, status)
+}
+
+// runScrobblingCommand returns a RunE that calls a control method and
+// prints done on success
+func runScrobblingCommand(method, done string) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		client, err := dialDaemon(scrobblingDataDir)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = client.Close() }()
+
+		// Loving a track waits on the scrobbling services
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := client.Call(ctx, method, nil); err != nil {
+			return err
+		}
+
+		fmt.Println(done)
+		return nil
+	}
+}
+
+// writeDaemonStatus writes the daemon's status as text
+func writeDaemonStatus(w io.Writer, s *daemon.Status) error {
+	var buf bytes.Buffer
+	if track := s.State.Track; track != nil {
+		fmt.Fprintf(&buf, "Now playing:\t%s - %s\n", track.Artist, track.Name)
+		if track.Album != "" {
+			fmt.Fprintf(&buf, "Album:\t%s\n", track.Album)
+		}
+		fmt.Fprintf(&buf, "State:\t%s, played %s/%s\n", track.State, tuiFormatDuration(s.Played), tuiFormatDuration(track.Duration))
+		fmt.Fprintf(&buf, "Scrobble:\t%s\n", scrobbleSummary(s))
+	} else {
+		fmt.Fprintln(&buf, "Now playing:\tnothing")
+	}
+	if s.ScrobblingPaused {
+		fmt.Fprintln(&buf, "Scrobbling:\tpaused")
+	} else {
+		fmt.Fprintln(&buf, "Scrobbling:\tactive")
+	}
+	fmt.Fprintf(&buf, "Pending:\t%d\n", s.Pending)
+
+	if len(s.Recent) > 0 {
+		fmt.Fprintln(&buf, "\nSTARTED\tARTIST\tTRACK\tPLAYED\t")
+		for _, p := range s.Recent {
+			var status string
+			switch {
+			case p.Scrobbled:
+				status = "scrobbled"
+			case p.Skipped:
+				status = "skipped"
+			}
+			fmt.Fprintf(&buf, "%s\t%s\t%s\t%s\t%s\n",
+				p.StartedAt.Local().Format("2006-01-02 15:04"),
+				runewidth.Truncate(p.Artist, 24, "..."),
+				runewidth.Truncate(p.Track, 32, "..."),
+				tuiFormatDuration(p.Played),
+				status,
+			)
+		}
+	}
+
+	return writeTable(w, buf.Bytes())
+}
+
+// scrobbleSummary describes when, or whether, the current play is scrobbled
+func scrobbleSummary(s *daemon.Status) string {
+	switch {
+	case s.State.Scrobbled:
+		return "scrobbled"
+	case s.State.ScrobbleSkipped:
+		return "skipped"
+	case s.ScrobblingPaused:
+		return "paused"
+	case !s.WillScrobble:
+		return "too short to scrobble"
+	case s.ScrobbleIn == 0:
+		return "now"
+	default:
+		return "in " + tuiFormatDuration(s.ScrobbleIn)
+	}
+}
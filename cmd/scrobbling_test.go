@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/daemon"
+	"github.com/jfmyers9/scribbles/internal/music"
+)
+
+// fakePlayer is a music client that only answers GetCurrentTrack
+type fakePlayer struct {
+	music.Client
+	track *music.Track
+}
+
+func (f *fakePlayer) GetCurrentTrack(ctx context.Context) (*music.Track, error) {
+	return f.track, nil
+}
+
+func TestCurrentTrack_PrefersDaemon(t *testing.T) {
+	path := filepath.Join(t.TempDir(), daemon.ControlSocketFile)
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	// A daemon answering a single status request
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		if _, err := bufio.NewReader(conn).ReadBytes('\n'); err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"state":{"track":{"Name":"From Daemon","State":1}}}}` + "\n"))
+	}()
+
+	player := &fakePlayer{track: &music.Track{Name: "From Player", State: music.StatePlaying}}
+	track, err := currentTrack(context.Background(), path, player)
+	if err != nil {
+		t.Fatalf("currentTrack: %v", err)
+	}
+	if track == nil || track.Name != "From Daemon" || track.State != music.StatePlaying {
+		t.Errorf("expected the daemon's track, got %+v", track)
+	}
+}
+
+func TestCurrentTrack_FallsBackToPlayer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), daemon.ControlSocketFile)
+	player := &fakePlayer{track: &music.Track{Name: "From Player", State: music.StatePlaying}}
+
+	track, err := currentTrack(context.Background(), path, player)
+	if err != nil {
+		t.Fatalf("currentTrack: %v", err)
+	}
+	if track == nil || track.Name != "From Player" {
+		t.Errorf("expected the player's track without a daemon, got %+v", track)
+	}
+}
+
+func TestCurrentTrack_DaemonOnlyPlayer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), daemon.ControlSocketFile)
+	player := daemonOnlyClient{Client: music.NewWebhookClient("127.0.0.1:0", "", ""), player: "webhook"}
+
+	track, err := currentTrack(context.Background(), path, player)
+	if err == nil || !strings.Contains(err.Error(), "daemon running") {
+		t.Errorf("expected an error asking for the daemon, got %+v, %v", track, err)
+	}
+}
+
+func TestWriteDaemonStatus(t *testing.T) {
+	status := &daemon.Status{
+		State: daemon.TrackState{
+			Track: &music.Track{
+				Name:     "Song",
+				Artist:   "Artist",
+				Album:    "Album",
+				Duration: 4 * time.Minute,
+				State:    music.StatePlaying,
+			},
+		},
+		Played:       90 * time.Second,
+		WillScrobble: true,
+		ScrobbleIn:   30 * time.Second,
+		Pending:      2,
+		Recent: []daemon.RecentPlay{
+			{Track: "Earlier", Artist: "Someone", StartedAt: time.Now(), Played: time.Minute, Skipped: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeDaemonStatus(&buf, status); err != nil {
+		t.Fatalf("writeDaemonStatus: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"Artist - Song", "playing, played 01:30/04:00", "in 00:30", "Scrobbling:   active", "Pending:      2", "Earlier", "skipped"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := writeDaemonStatus(&buf, &daemon.Status{ScrobblingPaused: true}); err != nil {
+		t.Fatalf("writeDaemonStatus: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "nothing") || !strings.Contains(out, "paused") {
+		t.Errorf("expected an idle, paused status, got:\n%s", out)
+	}
+}
+
+func TestScrobbleSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		status daemon.Status
+		want   string
+	}{
+		{"scrobbled", daemon.Status{State: daemon.TrackState{Scrobbled: true}}, "scrobbled"},
+		{"skipped", daemon.Status{State: daemon.TrackState{ScrobbleSkipped: true}}, "skipped"},
+		{"paused", daemon.Status{ScrobblingPaused: true}, "paused"},
+		{"too short", daemon.Status{}, "too short to scrobble"},
+		{"due", daemon.Status{WillScrobble: true}, "now"},
+		{"later", daemon.Status{WillScrobble: true, ScrobbleIn: 75 * time.Second}, "in 01:15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scrobbleSummary(&tt.status); got != tt.want {
+				t.Errorf("scrobbleSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/history"
+	"github.com/mattn/go-runewidth"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsDataDir string
+	statsOutput  string
+	statsSince   string
+	statsUntil   string
+	statsYear    int
+	statsTop     int
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show listening statistics from your local history",
+	Long: `Show listening statistics computed from the local listening history.
+
+Includes top artists, albums and tracks, listening time per day and week,
+listening streaks, an hour-of-day heatmap and newly discovered artists.
+Everything is computed locally: Last.fm is never contacted.
+
+Skipped plays count towards listening time but not towards play counts,
+top lists or the heatmap.
+
+Time ranges accept a date (2026-03-01), a date and time ("2026-03-01 18:30"),
+or a duration ago (90m, 24h, 7d, 2w).`,
+	Example: `  scribbles stats                 # last 30 days
+  scribbles stats --since 7d
+  scribbles stats --year 2025     # year in review
+  scribbles stats --since 2026-01-01 -o json`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().StringVar(&statsDataDir, "data-dir", "", "Data directory for state and history (default: ~/.local/share/scribbles)")
+	statsCmd.Flags().StringVarP(&statsOutput, "output", "o", "text", "Output format (text, json)")
+	statsCmd.Flags().StringVar(&statsSince, "since", "30d", "Start of the period (\"\" for all history)")
+	statsCmd.Flags().StringVar(&statsUntil, "until", "", "End of the period (default: now)")
+	statsCmd.Flags().IntVar(&statsYear, "year", 0, "Calendar year to report on (overrides --since/--until)")
+	statsCmd.Flags().IntVarP(&statsTop, "top", "n", 10, "Entries per top list")
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	opts := history.StatsOptions{Top: statsTop, Now: time.Now()}
+
+	if statsYear != 0 {
+		opts.Since = time.Date(statsYear, time.January, 1, 0, 0, 0, 0, time.Local)
+		opts.Until = opts.Since.AddDate(1, 0, 0)
+		if opts.Until.After(opts.Now) {
+			opts.Until = time.Time{}
+		}
+	} else {
+		var err error
+		if opts.Since, err = parseTimeFlag(statsSince, opts.Now); err != nil {
+			return fmt.Errorf("--since: %w", err)
+		}
+		if opts.Until, err = parseTimeFlag(statsUntil, opts.Now); err != nil {
+			return fmt.Errorf("--until: %w", err)
+		}
+	}
+
+	store, err := openHistory(statsDataDir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	st, err := store.Stats(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+
+	switch statsOutput {
+	case "text":
+		return writeStatsText(cmd.OutOrStdout(), st)
+	case "json":
+		return writeStatsJSON(cmd.OutOrStdout(), st)
+	default:
+		return fmt.Errorf("invalid output format %q (must be text or json)", statsOutput)
+	}
+}
+
+// statsReport is the JSON representation of history.Stats. Durations are
+// in seconds.
+type statsReport struct {
+	Since         time.Time          `json:"since"`
+	Until         time.Time          `json:"until"`
+	Plays         int                `json:"plays"`
+	Skips         int                `json:"skips"`
+	ListeningTime int                `json:"listening_seconds"`
+	Artists       int                `json:"artists"`
+	TopArtists    []statsRanked      `json:"top_artists"`
+	TopAlbums     []statsRanked      `json:"top_albums"`
+	TopTracks     []statsRanked      `json:"top_tracks"`
+	Days          []statsBucket      `json:"days"`
+	Weeks         []statsBucket      `json:"weeks"`
+	CurrentStreak int                `json:"current_streak_days"`
+	LongestStreak int                `json:"longest_streak_days"`
+	Heatmap       map[string][24]int `json:"heatmap"`
+	NewArtists    []statsDiscovery   `json:"new_artists"`
+}
+
+type statsRanked struct {
+	Artist string `json:"artist"`
+	Album  string `json:"album,omitempty"`
+	Track  string `json:"track,omitempty"`
+	Plays  int    `json:"plays"`
+	Time   int    `json:"listening_seconds"`
+}
+
+type statsBucket struct {
+	Start time.Time `json:"start"`
+	Plays int       `json:"plays"`
+	Time  int       `json:"listening_seconds"`
+}
+
+type statsDiscovery struct {
+	Artist      string    `json:"artist"`
+	FirstPlayed time.Time `json:"first_played"`
+}
+
+// writeStatsJSON writes stats as a JSON object
+func writeStatsJSON(w io.Writer, st *history.Stats) error {
+	report := statsReport{
+		Since:         st.Since,
+		Until:         st.Until,
+		Plays:         st.Plays,
+		Skips:         st.Skips,
+		ListeningTime: int(st.ListeningTime.Seconds()),
+		Artists:       st.Artists,
+		TopArtists:    newStatsRanked(st.TopArtists),
+		TopAlbums:     newStatsRanked(st.TopAlbums),
+		TopTracks:     newStatsRanked(st.TopTracks),
+		Days:          newStatsBuckets(st.Days),
+		Weeks:         newStatsBuckets(st.Weeks),
+		CurrentStreak: st.CurrentStreak,
+		LongestStreak: st.LongestStreak,
+		Heatmap:       make(map[string][24]int, 7),
+		NewArtists:    make([]statsDiscovery, len(st.NewArtists)),
+	}
+	for day, hours := range st.Heatmap {
+		report.Heatmap[strings.ToLower(time.Weekday(day).String())] = hours
+	}
+	for i, d := range st.NewArtists {
+		report.NewArtists[i] = statsDiscovery(d)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func newStatsRanked(entries []history.Ranked) []statsRanked {
+	result := make([]statsRanked, len(entries))
+	for i, e := range entries {
+		result[i] = statsRanked{
+			Artist: e.Artist,
+			Album:  e.Album,
+			Track:  e.Track,
+			Plays:  e.Plays,
+			Time:   int(e.Time.Seconds()),
+		}
+	}
+	return result
+}
+
+func newStatsBuckets(buckets []history.Bucket) []statsBucket {
+	result := make([]statsBucket, len(buckets))
+	for i, b := range buckets {
+		result[i] = statsBucket{Start: b.Start, Plays: b.Plays, Time: int(b.Time.Seconds())}
+	}
+	return result
+}
+
+// sparklineWidth is the maximum number of characters in a sparkline;
+// longer series are merged into fewer, wider buckets
+const sparklineWidth = 60
+
+// writeStatsText writes stats as terminal tables and sparklines
+func writeStatsText(w io.Writer, st *history.Stats) error {
+	var buf bytes.Buffer
+
+	if st.Plays == 0 && st.Skips == 0 {
+		_, err := fmt.Fprintln(w, "No plays found")
+		return err
+	}
+
+	fmt.Fprintf(&buf, "Listening stats %s – %s\n\n",
+		st.Since.Local().Format("2006-01-02"), st.Until.Add(-time.Second).Local().Format("2006-01-02"))
+
+	summary := fmt.Sprintf("Plays\t%d (%d skipped)\n", st.Plays, st.Skips) +
+		fmt.Sprintf("Listening time\t%s\n", formatListeningTime(st.ListeningTime)) +
+		fmt.Sprintf("Artists\t%d (%d new)\n", st.Artists, len(st.NewArtists)) +
+		fmt.Sprintf("Streak\t%d days (longest %d)\n", st.CurrentStreak, st.LongestStreak)
+	if err := writeTable(&buf, []byte(summary)); err != nil {
+		return err
+	}
+
+	for _, series := range []struct {
+		title   string
+		buckets []history.Bucket
+	}{
+		{"day", st.Days},
+		{"week", st.Weeks},
+	} {
+		values := make([]float64, len(series.buckets))
+		var peak time.Duration
+		for i, b := range series.buckets {
+			values[i] = b.Time.Seconds()
+			peak = max(peak, b.Time)
+		}
+		fmt.Fprintf(&buf, "\nListening time per %s (peak %s)\n%s\n",
+			series.title, formatListeningTime(peak), sparkline(values, sparklineWidth))
+	}
+
+	for _, list := range []struct {
+		title   string
+		entries []history.Ranked
+		name    func(history.Ranked) string
+	}{
+		{"Top artists", st.TopArtists, func(r history.Ranked) string { return r.Artist }},
+		{"Top albums", st.TopAlbums, func(r history.Ranked) string { return r.Artist + " - " + r.Album }},
+		{"Top tracks", st.TopTracks, func(r history.Ranked) string { return r.Artist + " - " + r.Track }},
+	} {
+		if len(list.entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "\n%s\n", list.title)
+
+		var rows bytes.Buffer
+		for i, r := range list.entries {
+			fmt.Fprintf(&rows, "%3d\t%s\t%d plays\t%s\n",
+				i+1, runewidth.Truncate(list.name(r), 56, "..."), r.Plays, formatListeningTime(r.Time))
+		}
+		if err := writeTable(&buf, rows.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(&buf, "\nPlays by hour\n%s", heatmap(st.Heatmap))
+
+	if len(st.NewArtists) > 0 {
+		names := make([]string, 0, 10)
+		for _, d := range st.NewArtists[:min(len(st.NewArtists), 10)] {
+			names = append(names, d.Artist)
+		}
+		more := ""
+		if n := len(st.NewArtists) - len(names); n > 0 {
+			more = fmt.Sprintf(" and %d more", n)
+		}
+		fmt.Fprintf(&buf, "\nNew artists: %s%s\n", strings.Join(names, ", "), more)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// sparkBlocks are the sparkline levels from lowest to highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders values as a bar chart of block characters, merging
+// adjacent values so the result is at most width characters. Zero values
+// are shown as spaces.
+func sparkline(values []float64, width int) string {
+	if len(values) > width {
+		per := (len(values) + width - 1) / width
+		merged := make([]float64, 0, width)
+		for i := 0; i < len(values); i += per {
+			var sum float64
+			for _, v := range values[i:min(i+per, len(values))] {
+				sum += v
+			}
+			merged = append(merged, sum)
+		}
+		values = merged
+	}
+
+	var peak float64
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		if v <= 0 {
+			sb.WriteRune(' ')
+			continue
+		}
+		level := int(v / peak * float64(len(sparkBlocks)-1))
+		sb.WriteRune(sparkBlocks[level])
+	}
+	return sb.String()
+}
+
+// heatShades are the heatmap levels from none to most plays
+var heatShades = []rune(" ░▒▓█")
+
+// heatmap renders plays by weekday and hour as a grid of shaded cells,
+// Monday first
+func heatmap(counts [7][24]int) string {
+	var peak int
+	for _, hours := range counts {
+		for _, n := range hours {
+			peak = max(peak, n)
+		}
+	}
+
+	var sb strings.Builder
+	header := "     "
+	for h := 0; h < 24; h += 3 {
+		header += fmt.Sprintf("%-6s", strconv.Itoa(h))
+	}
+	sb.WriteString(strings.TrimRight(header, " ") + "\n")
+
+	for i := range 7 {
+		day := time.Weekday((i + 1) % 7)
+		sb.WriteString(day.String()[:3] + "  ")
+		for _, n := range counts[day] {
+			level := 0
+			if n > 0 {
+				level = 1 + (n-1)*(len(heatShades)-2)/max(peak-1, 1)
+			}
+			sb.WriteRune(heatShades[level])
+			sb.WriteRune(heatShades[level])
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// formatListeningTime formats a duration as hours and minutes, e.g. "27h 14m"
+func formatListeningTime(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
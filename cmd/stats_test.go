@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/history"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		want   string
+	}{
+		{name: "empty", values: nil, width: 10, want: ""},
+		{name: "scaled to peak", values: []float64{0, 1, 4, 8}, width: 10, want: " ▁▄█"},
+		{name: "merged to width", values: []float64{1, 1, 0, 0, 2, 2}, width: 3, want: "▄ █"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sparkline(tt.values, tt.width); got != tt.want {
+				t.Errorf("sparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	var counts [7][24]int
+	counts[time.Monday][0] = 4
+	counts[time.Sunday][23] = 1
+
+	lines := strings.Split(strings.TrimRight(heatmap(counts), "\n"), "\n")
+	if len(lines) != 8 {
+		t.Fatalf("expected header and 7 days, got %d lines", len(lines))
+	}
+	if !strings.HasPrefix(lines[1], "Mon  ██") {
+		t.Errorf("expected Monday first with a full cell at midnight, got %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[7], "Sun") || !strings.HasSuffix(lines[7], "░░") {
+		t.Errorf("expected Sunday last with a light cell at 23h, got %q", lines[7])
+	}
+}
+
+func TestFormatListeningTime(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                             "0m",
+		59 * time.Second:              "1m",
+		42 * time.Minute:              "42m",
+		27*time.Hour + 14*time.Minute: "27h 14m",
+	}
+	for d, want := range tests {
+		if got := formatListeningTime(d); got != want {
+			t.Errorf("formatListeningTime(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func testStats() *history.Stats {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	st := &history.Stats{
+		Since:         day,
+		Until:         day.AddDate(0, 0, 2),
+		Plays:         3,
+		Skips:         1,
+		ListeningTime: 14 * time.Minute,
+		Artists:       2,
+		TopArtists:    []history.Ranked{{Artist: "Cher", Plays: 2, Time: 8 * time.Minute}},
+		TopTracks:     []history.Ranked{{Artist: "Cher", Album: "Believe", Track: "Believe", Plays: 2, Time: 8 * time.Minute}},
+		Days: []history.Bucket{
+			{Start: day, Plays: 3, Time: 14 * time.Minute},
+			{Start: day.AddDate(0, 0, 1)},
+		},
+		Weeks:         []history.Bucket{{Start: day, Plays: 3, Time: 14 * time.Minute}},
+		CurrentStreak: 1,
+		LongestStreak: 1,
+		NewArtists:    []history.Discovery{{Artist: "Cher", FirstPlayed: day.Add(8 * time.Hour)}},
+	}
+	st.Heatmap[time.Monday][8] = 3
+	return st
+}
+
+func TestWriteStatsText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeStatsText(&buf, testStats()); err != nil {
+		t.Fatalf("writeStatsText: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"3 (1 skipped)",
+		"14m",
+		"2 (1 new)",
+		"Listening time per day (peak 14m)\n█ \n",
+		"Top artists",
+		"Cher - Believe",
+		"Plays by hour",
+		"New artists: Cher\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Top albums") {
+		t.Error("expected empty top lists to be omitted")
+	}
+}
+
+func TestWriteStatsText_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeStatsText(&buf, &history.Stats{}); err != nil {
+		t.Fatalf("writeStatsText: %v", err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "No plays found" {
+		t.Errorf("unexpected output for empty stats: %q", got)
+	}
+}
+
+func TestWriteStatsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeStatsJSON(&buf, testStats()); err != nil {
+		t.Fatalf("writeStatsJSON: %v", err)
+	}
+
+	var report statsReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if report.Plays != 3 || report.ListeningTime != 840 || len(report.Days) != 2 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if report.TopTracks[0].Track != "Believe" || report.TopTracks[0].Time != 480 {
+		t.Errorf("unexpected top track: %+v", report.TopTracks[0])
+	}
+	if report.Heatmap["monday"][8] != 3 {
+		t.Errorf("expected heatmap keyed by weekday, got %v", report.Heatmap)
+	}
+}
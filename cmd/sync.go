@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/config"
+	"github.com/jfmyers9/scribbles/internal/history"
+	"github.com/jfmyers9/scribbles/internal/scrobbler"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncDataDir  string
+	syncUser     string
+	syncFull     bool
+	syncInterval time.Duration
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Backfill the listening history from Last.fm",
+	Long: `Copy your Last.fm scrobbles into the local listening history, so that
+"scribbles history" and "scribbles stats" cover your whole account rather
+than only the plays since scribbles was installed.
+
+The first sync pages through the entire account, newest first; later runs
+only fetch scrobbles made since the last one. Progress is saved after every
+page, so an interrupted sync (Ctrl-C, network failure) picks up where it
+stopped when run again. Requests are spaced out by --interval to stay well
+within Last.fm's rate limits.
+
+Scrobbles that match a play already in the history, such as one the daemon
+recorded, are skipped. Last.fm does not report how long a track was played,
+so synced plays count towards play counts but not listening time.
+
+The account is the one you authorized with "scribbles auth"; use --user to
+sync a different one.`,
+	Example: `  scribbles sync
+  scribbles sync --user rj --interval 2s
+  scribbles sync --full`,
+	Args: cobra.NoArgs,
+	RunE: runSync,
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().StringVar(&syncDataDir, "data-dir", "", "Data directory for state and history (default: ~/.local/share/scribbles)")
+	syncCmd.Flags().StringVar(&syncUser, "user", "", "Last.fm user to sync (default: the authenticated user)")
+	syncCmd.Flags().BoolVar(&syncFull, "full", false, "Scan the whole account again instead of only new scrobbles")
+	syncCmd.Flags().DurationVar(&syncInterval, "interval", time.Second, "Minimum time between Last.fm requests")
+}
+
+// resolveSyncUser returns the Last.fm user to sync: the --user flag, or
+// the user saved by "scribbles auth"
+func resolveSyncUser(flag string, cfg *config.Config) (string, error) {
+	if flag != "" {
+		return flag, nil
+	}
+	if cfg.LastFM.Username != "" {
+		return cfg.LastFM.Username, nil
+	}
+	return "", fmt.Errorf("last.fm username not configured\n\nRun 'scribbles auth' again to save it, or pass --user")
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if err := cfg.ValidateLastFM(); err != nil {
+		return err
+	}
+
+	user, err := resolveSyncUser(syncUser, cfg)
+	if err != nil {
+		return err
+	}
+
+	store, err := openHistory(syncDataDir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client := scrobbler.NewWithSession(cfg.LastFM.APIKey, cfg.LastFM.APISecret, cfg.LastFM.SessionKey)
+
+	fmt.Printf("Syncing Last.fm scrobbles for %s...\n", user)
+
+	result, err := store.Sync(ctx, client.Users(), history.SyncOptions{
+		User:     user,
+		Full:     syncFull,
+		Interval: syncInterval,
+		Progress: func(p history.SyncProgress) {
+			fmt.Printf("Page %d/%d: %d scrobbles fetched, %d new\n", p.Page, p.TotalPages, p.Fetched, p.Added)
+		},
+	})
+	if errors.Is(err, context.Canceled) {
+		fmt.Printf("\nSync interrupted after adding %d plays. Run 'scribbles sync' again to resume.\n", result.Added)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("sync stopped after adding %d plays (run again to resume): %w", result.Added, err)
+	}
+
+	if result.Resumed {
+		fmt.Println("Resumed the previous sync.")
+	}
+	fmt.Printf("✓ Added %d plays (%d already in history)\n", result.Added, result.Duplicates)
+	if !result.SyncedUntil.IsZero() {
+		fmt.Printf("✓ History is up to date with Last.fm as of %s\n", result.SyncedUntil.Local().Format("2006-01-02 15:04"))
+	}
+
+	return nil
+}
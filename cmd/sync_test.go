@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jfmyers9/scribbles/internal/config"
+)
+
+func TestResolveSyncUser(t *testing.T) {
+	cfg := &config.Config{LastFM: config.LastFMConfig{Username: "rj"}}
+
+	user, err := resolveSyncUser("", cfg)
+	if err != nil || user != "rj" {
+		t.Errorf("expected configured user rj, got %q (%v)", user, err)
+	}
+
+	user, err = resolveSyncUser("cher", cfg)
+	if err != nil || user != "cher" {
+		t.Errorf("expected --user to win, got %q (%v)", user, err)
+	}
+
+	if _, err := resolveSyncUser("", &config.Config{}); err == nil {
+		t.Error("expected an error without a configured user")
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeLayouts are the absolute time formats accepted by parseTimeFlag,
+// interpreted in local time unless they carry an offset
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseTimeFlag parses a --since/--until style flag value. It accepts an
+// absolute date or time (2026-03-01, 2026-03-01 18:30, RFC 3339) or a
+// duration before now (90m, 24h, 7d, 2w).
+func parseTimeFlag(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	if d, err := parseAgo(value); err == nil {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q (use a date like 2026-03-01, a time like \"2026-03-01 18:30\", or a duration ago like 24h or 7d)", value)
+}
+
+// parseAgo parses a duration, additionally accepting d (days) and w (weeks)
+func parseAgo(value string) (time.Duration, error) {
+	unit := value[len(value)-1]
+	if unit == 'd' || unit == 'w' {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		day := 24 * time.Hour
+		if unit == 'w' {
+			return time.Duration(n) * 7 * day, nil
+		}
+		return time.Duration(n) * day, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
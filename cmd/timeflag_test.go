@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeFlag(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "empty", value: "", want: time.Time{}},
+		{name: "date", value: "2026-03-01", want: time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)},
+		{name: "date and time", value: "2026-03-01 18:30", want: time.Date(2026, 3, 1, 18, 30, 0, 0, time.Local)},
+		{name: "rfc3339", value: "2026-03-01T18:30:00Z", want: time.Date(2026, 3, 1, 18, 30, 0, 0, time.UTC)},
+		{name: "hours ago", value: "24h", want: now.Add(-24 * time.Hour)},
+		{name: "minutes ago", value: "90m", want: now.Add(-90 * time.Minute)},
+		{name: "days ago", value: "7d", want: now.AddDate(0, 0, -7)},
+		{name: "weeks ago", value: "2w", want: now.AddDate(0, 0, -14)},
+		{name: "garbage", value: "yesterday", wantErr: true},
+		{name: "bad days", value: "xd", wantErr: true},
+		{name: "negative", value: "-5h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTimeFlag(tt.value, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseTimeFlag(%q) expected error, got %v", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTimeFlag(%q) unexpected error: %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseTimeFlag(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var tuiDataDir string
+
 // tuiCmd represents the tui command
 var tuiCmd = &cobra.Command{
 	Use:   "tui",
@@ -20,7 +22,8 @@ var tuiCmd = &cobra.Command{
 	Long: `Display a terminal-based user interface showing the currently playing track
 from Apple Music with real-time updates.
 
-This is a standalone TUI that polls Apple Music directly. For a TUI that
+This is a standalone TUI that asks the running daemon for the current track,
+or polls Apple Music directly if no daemon is running. For a TUI that
 integrates with scrobbling, use 'scribbles daemon --tui' instead.
 
 The TUI includes:
@@ -34,6 +37,8 @@ Press 'q' to quit.`,
 
 func init() {
 	rootCmd.AddCommand(tuiCmd)
+
+	tuiCmd.Flags().StringVar(&tuiDataDir, "data-dir", "", "Data directory of the daemon (default: ~/.local/share/scribbles)")
 }
 
 func runTUI(cmd *cobra.Command, args []string) error {
@@ -42,10 +47,19 @@ func runTUI(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	_ = cfg // Will use for configuration later
-
-	// Create music client
-	client := music.NewAppleScriptClient()
+	// Create music client, used when no daemon is running
+	client := newMusicClient(cfg)
+	socketPath := controlSocketPath(tuiDataDir)
+
+	// A player that only reports to the daemon has nothing to show without it
+	if _, ok := client.(daemonOnlyClient); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_, err := currentTrack(ctx, socketPath, client)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to get current track: %w", err)
+		}
+	}
 
 	// Create tview application
 	app := tview.NewApplication()
@@ -161,7 +175,7 @@ func runTUI(cmd *cobra.Command, args []string) error {
 		defer ticker.Stop()
 
 		// Initial fetch
-		track, _ := client.GetCurrentTrack(ctx)
+		track, _ := currentTrack(ctx, socketPath, client)
 		updateDisplay(track)
 
 		for {
@@ -169,7 +183,7 @@ func runTUI(cmd *cobra.Command, args []string) error {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				track, err := client.GetCurrentTrack(ctx)
+				track, err := currentTrack(ctx, socketPath, client)
 				if err != nil {
 					updateDisplay(nil)
 					// Exponential backoff on error
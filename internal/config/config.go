@@ -1,9 +1,13 @@
 package config
 
 import (
+	"cmp"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -12,13 +16,80 @@ type Config struct {
 	OutputFormat     string
 	OutputWidth      int
 	PollInterval     int
+	Player           string // Music player to follow: PlayerAppleMusic, PlayerMPD, PlayerWebhook or PlayerSubsonic
+	MPD              MPDConfig
+	WebhookSource    WebhookSourceConfig
+	Subsonic         SubsonicConfig
+	Sources          map[string]SourceConfig // Music players the daemon follows, by name (default: Player alone)
+	Arbitration      string                  // How the daemon picks between sources: ArbitrationPriority or ArbitrationRecent
 	MarqueeEnabled   bool
 	MarqueeSpeed     int
 	MarqueeSeparator string
 	LastFM           LastFMConfig
+	Profiles         map[string]ProfileConfig // Other Audioscrobbler-compatible accounts, by name
+	ListenBrainz     ListenBrainzConfig
+	Webhooks         map[string]WebhookConfig // Webhook scrobble sinks, by name
 	Logging          LoggingConfig
 	TUI              TUIConfig
 	Discord          DiscordConfig
+	HTTP             HTTPConfig
+}
+
+// Music players scribbles can follow
+const (
+	PlayerAppleMusic = "apple-music"
+	PlayerMPD        = "mpd"
+	PlayerWebhook    = "webhook"  // Plex, Jellyfin or custom players POSTing events
+	PlayerSubsonic   = "subsonic" // Now playing on a Subsonic-compatible server, e.g. Navidrome
+)
+
+// players lists the valid values of player
+var players = []string{PlayerAppleMusic, PlayerMPD, PlayerWebhook, PlayerSubsonic}
+
+// How the daemon picks which source is now playing when several are
+const (
+	ArbitrationPriority = "priority" // The playing source with the highest priority
+	ArbitrationRecent   = "recent"   // The source that most recently started playing
+)
+
+// SourceConfig is a music player the daemon follows. Its name is recorded
+// with each play and scrobble.
+type SourceConfig struct {
+	Name          string // Set from the key in Sources
+	Player        string // PlayerAppleMusic, PlayerMPD, PlayerWebhook or PlayerSubsonic
+	Priority      int    // Higher priorities win under ArbitrationPriority
+	MPD           MPDConfig
+	WebhookSource WebhookSourceConfig
+	Subsonic      SubsonicConfig
+}
+
+// MPDConfig configures the connection to Music Player Daemon or Mopidy
+type MPDConfig struct {
+	Address  string // host:port or the path of a Unix socket (default localhost:6600)
+	Password string
+}
+
+// WebhookSourceConfig configures the endpoint that Plex, Jellyfin and
+// custom players POST playback events to
+type WebhookSourceConfig struct {
+	Address string // Address to listen on (default 127.0.0.1:7416)
+	Token   string // Required as ?token= or a bearer token, if set
+	User    string // Only follow this Plex or Jellyfin user, if set
+}
+
+// SubsonicConfig configures the connection to a Subsonic-compatible server,
+// such as Navidrome, whose now playing entry for Username is followed
+type SubsonicConfig struct {
+	URL      string // Base URL of the server, e.g. https://music.example.com
+	Username string
+	Password string // Sent as a salted token, never in plain text
+}
+
+// HTTPConfig configures the daemon's local HTTP API
+type HTTPConfig struct {
+	Enabled     bool
+	Address     string // Loopback address to listen on (default 127.0.0.1:7415)
+	AllowOrigin string // Access-Control-Allow-Origin for browser overlays (default: none)
 }
 
 type DiscordConfig struct {
@@ -37,10 +108,68 @@ type LoggingConfig struct {
 	File  string
 }
 
+type ListenBrainzConfig struct {
+	Token string // User token from https://listenbrainz.org/settings/
+	URL   string // API root, for self-hosted servers (default https://api.listenbrainz.org)
+}
+
+type WebhookConfig struct {
+	URL        string            // Endpoint each scrobble is POSTed to
+	Secret     string            // Optional key for the HMAC-SHA256 signature header
+	Template   string            // Optional text/template for the request body (default: JSON event)
+	Headers    map[string]string // Extra request headers, e.g. Authorization
+	NowPlaying bool              // Also POST now playing events
+}
+
 type LastFMConfig struct {
 	APIKey     string
 	APISecret  string
 	SessionKey string
+	Username   string // Account the session belongs to, used by sync
+}
+
+// ProfileConfig is a named account on an Audioscrobbler 2.0 compatible
+// server, such as Libre.fm, a self-hosted GNU FM or Maloja
+type ProfileConfig struct {
+	APIURL     string // API root, e.g. https://libre.fm/2.0/
+	AuthURL    string // Page where users authorize tokens, e.g. https://libre.fm/api/auth/
+	APIKey     string
+	APISecret  string
+	SessionKey string
+	Username   string
+}
+
+// ProfilePresets holds the server URLs of well-known profile names, so a
+// profile named after one only needs its credentials
+var ProfilePresets = map[string]ProfileConfig{
+	"librefm": {
+		APIURL:  "https://libre.fm/2.0/",
+		AuthURL: "https://libre.fm/api/auth/",
+	},
+}
+
+// reservedProfileNames are taken by the built-in scrobbling backends
+var reservedProfileNames = map[string]bool{
+	"lastfm":       true,
+	"listenbrainz": true,
+}
+
+// withPreset fills in unset URLs from the preset of the same name, if any
+func (p ProfileConfig) withPreset(name string) ProfileConfig {
+	preset := ProfilePresets[name]
+	if p.APIURL == "" {
+		p.APIURL = preset.APIURL
+	}
+	if p.AuthURL == "" {
+		p.AuthURL = preset.AuthURL
+	}
+	return p
+}
+
+// Profile returns the named profile, or a new one with the preset URLs of
+// that name if it is not configured yet
+func (c *Config) Profile(name string) ProfileConfig {
+	return c.Profiles[name].withPreset(name)
 }
 
 func Load() (*Config, error) {
@@ -56,6 +185,16 @@ func Load() (*Config, error) {
 	v.SetDefault("output_format", "{{.Artist}} - {{.Name}}")
 	v.SetDefault("output_width", 0)
 	v.SetDefault("poll_interval", 3)
+	v.SetDefault("player", PlayerAppleMusic)
+	v.SetDefault("mpd.address", "localhost:6600")
+	v.SetDefault("mpd.password", "")
+	v.SetDefault("webhook_source.address", "127.0.0.1:7416")
+	v.SetDefault("webhook_source.token", "")
+	v.SetDefault("webhook_source.user", "")
+	v.SetDefault("subsonic.url", "")
+	v.SetDefault("subsonic.username", "")
+	v.SetDefault("subsonic.password", "")
+	v.SetDefault("arbitration", ArbitrationPriority)
 	v.SetDefault("marquee_enabled", false)
 	v.SetDefault("marquee_speed", 2)
 	v.SetDefault("marquee_separator", " • ")
@@ -64,8 +203,13 @@ func Load() (*Config, error) {
 	v.SetDefault("tui.enabled", false)
 	v.SetDefault("tui.refresh_rate", 500)
 	v.SetDefault("tui.theme", "default")
+	v.SetDefault("listenbrainz.token", "")
+	v.SetDefault("listenbrainz.url", "")
 	v.SetDefault("discord.enabled", false)
 	v.SetDefault("discord.app_id", "")
+	v.SetDefault("http.enabled", false)
+	v.SetDefault("http.address", "127.0.0.1:7415")
+	v.SetDefault("http.allow_origin", "")
 
 	_ = v.ReadInConfig()
 
@@ -76,13 +220,37 @@ func Load() (*Config, error) {
 		OutputFormat:     v.GetString("output_format"),
 		OutputWidth:      v.GetInt("output_width"),
 		PollInterval:     v.GetInt("poll_interval"),
+		Player:           v.GetString("player"),
+		Arbitration:      v.GetString("arbitration"),
 		MarqueeEnabled:   v.GetBool("marquee_enabled"),
 		MarqueeSpeed:     v.GetInt("marquee_speed"),
 		MarqueeSeparator: v.GetString("marquee_separator"),
+		MPD: MPDConfig{
+			Address:  v.GetString("mpd.address"),
+			Password: v.GetString("mpd.password"),
+		},
+		WebhookSource: WebhookSourceConfig{
+			Address: v.GetString("webhook_source.address"),
+			Token:   v.GetString("webhook_source.token"),
+			User:    v.GetString("webhook_source.user"),
+		},
+		Subsonic: SubsonicConfig{
+			URL:      v.GetString("subsonic.url"),
+			Username: v.GetString("subsonic.username"),
+			Password: v.GetString("subsonic.password"),
+		},
 		LastFM: LastFMConfig{
 			APIKey:     v.GetString("lastfm.api_key"),
 			APISecret:  v.GetString("lastfm.api_secret"),
 			SessionKey: v.GetString("lastfm.session_key"),
+			Username:   v.GetString("lastfm.username"),
+		},
+		Sources:  make(map[string]SourceConfig),
+		Profiles: make(map[string]ProfileConfig),
+		Webhooks: make(map[string]WebhookConfig),
+		ListenBrainz: ListenBrainzConfig{
+			Token: v.GetString("listenbrainz.token"),
+			URL:   v.GetString("listenbrainz.url"),
 		},
 		Logging: LoggingConfig{
 			Level: v.GetString("logging.level"),
@@ -97,6 +265,57 @@ func Load() (*Config, error) {
 			Enabled: v.GetBool("discord.enabled"),
 			AppID:   v.GetString("discord.app_id"),
 		},
+		HTTP: HTTPConfig{
+			Enabled:     v.GetBool("http.enabled"),
+			Address:     v.GetString("http.address"),
+			AllowOrigin: v.GetString("http.allow_origin"),
+		},
+	}
+
+	for name := range v.GetStringMap("profiles") {
+		key := "profiles." + name + "."
+		cfg.Profiles[name] = ProfileConfig{
+			APIURL:     v.GetString(key + "api_url"),
+			AuthURL:    v.GetString(key + "auth_url"),
+			APIKey:     v.GetString(key + "api_key"),
+			APISecret:  v.GetString(key + "api_secret"),
+			SessionKey: v.GetString(key + "session_key"),
+			Username:   v.GetString(key + "username"),
+		}.withPreset(name)
+	}
+
+	for name := range v.GetStringMap("sources") {
+		key := "sources." + name + "."
+		cfg.Sources[name] = SourceConfig{
+			Name:     name,
+			Player:   v.GetString(key + "player"),
+			Priority: v.GetInt(key + "priority"),
+			MPD: MPDConfig{
+				Address:  v.GetString(key + "mpd.address"),
+				Password: v.GetString(key + "mpd.password"),
+			},
+			WebhookSource: WebhookSourceConfig{
+				Address: v.GetString(key + "webhook_source.address"),
+				Token:   v.GetString(key + "webhook_source.token"),
+				User:    v.GetString(key + "webhook_source.user"),
+			},
+			Subsonic: SubsonicConfig{
+				URL:      v.GetString(key + "subsonic.url"),
+				Username: v.GetString(key + "subsonic.username"),
+				Password: v.GetString(key + "subsonic.password"),
+			},
+		}
+	}
+
+	for name := range v.GetStringMap("webhooks") {
+		key := "webhooks." + name + "."
+		cfg.Webhooks[name] = WebhookConfig{
+			URL:        v.GetString(key + "url"),
+			Secret:     v.GetString(key + "secret"),
+			Template:   v.GetString(key + "template"),
+			Headers:    v.GetStringMapString(key + "headers"),
+			NowPlaying: v.GetBool(key + "now_playing"),
+		}
 	}
 
 	return cfg, nil
@@ -156,6 +375,71 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid log level %q (must be one of: debug, info, warn, error)", c.Logging.Level)
 	}
 
+	if !slices.Contains(players, c.Player) {
+		return fmt.Errorf("invalid player %q (must be one of: %s)", c.Player, strings.Join(players, ", "))
+	}
+	for name, s := range c.Sources {
+		if !slices.Contains(players, s.Player) {
+			return fmt.Errorf("invalid player %q for source %q (must be one of: %s)", s.Player, name, strings.Join(players, ", "))
+		}
+	}
+	for _, s := range c.MusicSources() {
+		if s.Player == PlayerSubsonic && (s.Subsonic.URL == "" || s.Subsonic.Username == "") {
+			return fmt.Errorf("subsonic source %q requires subsonic.url and subsonic.username", s.Name)
+		}
+	}
+	if c.Arbitration != ArbitrationPriority && c.Arbitration != ArbitrationRecent {
+		return fmt.Errorf("invalid arbitration %q (must be one of: %s, %s)", c.Arbitration, ArbitrationPriority, ArbitrationRecent)
+	}
+
+	if err := validateHTTPAddress(c.HTTP.Address); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MusicSources returns the music players the daemon follows, highest
+// priority first. Without configured sources, that's the player set by
+// player, named after it.
+func (c *Config) MusicSources() []SourceConfig {
+	if len(c.Sources) == 0 {
+		return []SourceConfig{{
+			Name:          c.Player,
+			Player:        c.Player,
+			MPD:           c.MPD,
+			WebhookSource: c.WebhookSource,
+			Subsonic:      c.Subsonic,
+		}}
+	}
+
+	sources := make([]SourceConfig, 0, len(c.Sources))
+	for name, s := range c.Sources {
+		s.Name = name
+		sources = append(sources, s)
+	}
+	slices.SortFunc(sources, func(a, b SourceConfig) int {
+		return cmp.Or(cmp.Compare(b.Priority, a.Priority), strings.Compare(a.Name, b.Name))
+	})
+	return sources
+}
+
+// validateHTTPAddress checks that the HTTP API only listens on a loopback
+// address: it has no authentication
+func validateHTTPAddress(addr string) error {
+	if addr == "" {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid http.address %q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("http.address must be a loopback address such as 127.0.0.1:7415 (got %q)", addr)
+	}
 	return nil
 }
 
@@ -172,6 +456,43 @@ func (c *Config) ValidateLastFM() error {
 	return nil
 }
 
+// ValidateProfileName checks that a profile name can identify a scrobbling
+// backend
+func ValidateProfileName(name string) error {
+	if name == "" {
+		return fmt.Errorf("profile name cannot be empty")
+	}
+	if reservedProfileNames[name] {
+		return fmt.Errorf("profile name %q is reserved for the built-in backend", name)
+	}
+	if name != strings.ToLower(name) || strings.ContainsAny(name, ". ") {
+		return fmt.Errorf("invalid profile name %q (use lowercase letters, digits, - or _)", name)
+	}
+	return nil
+}
+
+// ValidateProfile checks that a profile is complete enough to scrobble
+func (c *Config) ValidateProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+
+	p, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("profile %q not configured\n\nTo add it:\n  Run: scribbles auth --profile %s", name, name)
+	}
+	if p.APIURL == "" {
+		return fmt.Errorf("profile %q has no api_url", name)
+	}
+	if p.APIKey == "" || p.APISecret == "" {
+		return fmt.Errorf("profile %q has no API key or secret\n\nTo configure it:\n  Run: scribbles auth --profile %s", name, name)
+	}
+	if p.SessionKey == "" {
+		return fmt.Errorf("profile %q has no session key\n\nTo authenticate:\n  Run: scribbles auth --profile %s", name, name)
+	}
+	return nil
+}
+
 func (c *Config) Save() error {
 	v := viper.New()
 
@@ -181,12 +502,55 @@ func (c *Config) Save() error {
 	v.Set("output_format", c.OutputFormat)
 	v.Set("output_width", c.OutputWidth)
 	v.Set("poll_interval", c.PollInterval)
+	v.Set("player", c.Player)
+	v.Set("mpd.address", c.MPD.Address)
+	v.Set("mpd.password", c.MPD.Password)
+	v.Set("webhook_source.address", c.WebhookSource.Address)
+	v.Set("webhook_source.token", c.WebhookSource.Token)
+	v.Set("webhook_source.user", c.WebhookSource.User)
+	v.Set("subsonic.url", c.Subsonic.URL)
+	v.Set("subsonic.username", c.Subsonic.Username)
+	v.Set("subsonic.password", c.Subsonic.Password)
+	v.Set("arbitration", c.Arbitration)
+	for name, s := range c.Sources {
+		key := "sources." + name + "."
+		v.Set(key+"player", s.Player)
+		v.Set(key+"priority", s.Priority)
+		v.Set(key+"mpd.address", s.MPD.Address)
+		v.Set(key+"mpd.password", s.MPD.Password)
+		v.Set(key+"webhook_source.address", s.WebhookSource.Address)
+		v.Set(key+"webhook_source.token", s.WebhookSource.Token)
+		v.Set(key+"webhook_source.user", s.WebhookSource.User)
+		v.Set(key+"subsonic.url", s.Subsonic.URL)
+		v.Set(key+"subsonic.username", s.Subsonic.Username)
+		v.Set(key+"subsonic.password", s.Subsonic.Password)
+	}
 	v.Set("marquee_enabled", c.MarqueeEnabled)
 	v.Set("marquee_speed", c.MarqueeSpeed)
 	v.Set("marquee_separator", c.MarqueeSeparator)
 	v.Set("lastfm.api_key", c.LastFM.APIKey)
 	v.Set("lastfm.api_secret", c.LastFM.APISecret)
 	v.Set("lastfm.session_key", c.LastFM.SessionKey)
+	v.Set("lastfm.username", c.LastFM.Username)
+	for name, p := range c.Profiles {
+		key := "profiles." + name + "."
+		v.Set(key+"api_url", p.APIURL)
+		v.Set(key+"auth_url", p.AuthURL)
+		v.Set(key+"api_key", p.APIKey)
+		v.Set(key+"api_secret", p.APISecret)
+		v.Set(key+"session_key", p.SessionKey)
+		v.Set(key+"username", p.Username)
+	}
+	for name, w := range c.Webhooks {
+		key := "webhooks." + name + "."
+		v.Set(key+"url", w.URL)
+		v.Set(key+"secret", w.Secret)
+		v.Set(key+"template", w.Template)
+		v.Set(key+"headers", w.Headers)
+		v.Set(key+"now_playing", w.NowPlaying)
+	}
+	v.Set("listenbrainz.token", c.ListenBrainz.Token)
+	v.Set("listenbrainz.url", c.ListenBrainz.URL)
 	v.Set("logging.level", c.Logging.Level)
 	v.Set("logging.file", c.Logging.File)
 	v.Set("tui.enabled", c.TUI.Enabled)
@@ -194,6 +558,9 @@ func (c *Config) Save() error {
 	v.Set("tui.theme", c.TUI.Theme)
 	v.Set("discord.enabled", c.Discord.Enabled)
 	v.Set("discord.app_id", c.Discord.AppID)
+	v.Set("http.enabled", c.HTTP.Enabled)
+	v.Set("http.address", c.HTTP.Address)
+	v.Set("http.allow_origin", c.HTTP.AllowOrigin)
 
 	return v.WriteConfigAs(configFile)
 }
@@ -0,0 +1,108 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/music"
+)
+
+// Source is a music player the daemon follows
+type Source struct {
+	Name     string // Recorded with each play and scrobble, e.g. "apple-music"
+	Client   music.Client
+	Priority int // Sources with higher priorities win arbitration, unless PreferRecent is set
+}
+
+// arbiter decides which of the daemon's sources is now playing. Only that
+// source's track is tracked and scrobbled, so players running at the same
+// time cannot scrobble twice or add to each other's play time.
+type arbiter struct {
+	sources      []Source // In order of preference when priorities tie
+	preferRecent bool
+	tracks       map[string]sourceTrack // Latest track by source name
+	active       string                 // Source now playing ("" if none)
+}
+
+// sourceTrack is a source's latest track
+type sourceTrack struct {
+	track     *music.Track // nil if stopped
+	startedAt time.Time    // When the source last started or resumed playing
+}
+
+func newArbiter(sources []Source, preferRecent bool) *arbiter {
+	return &arbiter{
+		sources:      sources,
+		preferRecent: preferRecent,
+		tracks:       make(map[string]sourceTrack),
+	}
+}
+
+// update records a source's latest track, and returns the source that is
+// now playing and its track, or "" and nil if no source has a track.
+//
+// A playing source wins over paused ones: the one with the highest
+// priority, or the one that most recently started playing if preferRecent
+// is set. When every source is paused or stopped, the active source stays
+// active while it still has a track, so pausing doesn't hand over to
+// another paused player.
+func (a *arbiter) update(source string, track *music.Track, now time.Time) (string, *music.Track) {
+	if track != nil && track.State == music.StateStopped {
+		track = nil
+	}
+
+	prev := a.tracks[source]
+	next := sourceTrack{track: track, startedAt: prev.startedAt}
+	if isPlaying(track) && (!isPlaying(prev.track) || !isSameTrack(prev.track, track)) {
+		next.startedAt = now
+	}
+	a.tracks[source] = next
+
+	if best := a.best(true); best != "" {
+		a.active = best
+	} else if a.tracks[a.active].track == nil {
+		a.active = a.best(false)
+	}
+	return a.active, a.tracks[a.active].track
+}
+
+// best returns the preferred source with a track, only considering
+// playing sources if playing is set, or "" if there is none
+func (a *arbiter) best(playing bool) string {
+	var best *Source
+	for i := range a.sources {
+		s := &a.sources[i]
+		t := a.tracks[s.Name]
+		if t.track == nil || (playing && !isPlaying(t.track)) {
+			continue
+		}
+		if best == nil || a.prefer(s, best) {
+			best = s
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.Name
+}
+
+// prefer reports whether source s wins over source than
+func (a *arbiter) prefer(s, than *Source) bool {
+	if a.preferRecent {
+		return a.tracks[s.Name].startedAt.After(a.tracks[than.Name].startedAt)
+	}
+	return s.Priority > than.Priority
+}
+
+// client returns the client of the named source, or nil if there is none
+func (a *arbiter) client(source string) music.Client {
+	for _, s := range a.sources {
+		if s.Name == source {
+			return s.Client
+		}
+	}
+	return nil
+}
+
+func isPlaying(track *music.Track) bool {
+	return track != nil && track.State == music.StatePlaying
+}
@@ -0,0 +1,243 @@
+package daemon
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/history"
+	"github.com/jfmyers9/scribbles/internal/music"
+)
+
+func TestArbiter(t *testing.T) {
+	desk := &music.Track{Name: "Desk Song", Artist: "Artist", State: music.StatePlaying}
+	deskPaused := &music.Track{Name: "Desk Song", Artist: "Artist", State: music.StatePaused}
+	lounge := &music.Track{Name: "Lounge Song", Artist: "Artist", State: music.StatePlaying}
+	loungePaused := &music.Track{Name: "Lounge Song", Artist: "Artist", State: music.StatePaused}
+
+	type step struct {
+		source     string
+		track      *music.Track
+		wantSource string
+	}
+	tests := []struct {
+		name         string
+		preferRecent bool
+		steps        []step
+	}{
+		{
+			name: "priority",
+			steps: []step{
+				{"lounge", lounge, "lounge"},
+				{"desk", desk, "desk"},     // Higher priority takes over
+				{"lounge", lounge, "desk"}, // Lower priority can't take it back
+				{"desk", nil, "lounge"},    // Falls back when desk stops
+			},
+		},
+		{
+			name:         "most recently started",
+			preferRecent: true,
+			steps: []step{
+				{"desk", desk, "desk"},
+				{"lounge", lounge, "lounge"},
+				{"desk", desk, "lounge"}, // Still the same play on desk
+				{"lounge", loungePaused, "desk"},
+				{"lounge", lounge, "lounge"}, // Resuming counts as starting
+			},
+		},
+		{
+			name: "playing wins over paused",
+			steps: []step{
+				{"desk", deskPaused, "desk"},
+				{"lounge", lounge, "lounge"},
+			},
+		},
+		{
+			name: "paused source stays active",
+			steps: []step{
+				{"lounge", lounge, "lounge"},
+				{"desk", deskPaused, "lounge"},
+				{"lounge", loungePaused, "lounge"}, // Not handed to paused desk
+				{"lounge", nil, "desk"},
+				{"desk", nil, ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newArbiter([]Source{{Name: "desk", Priority: 10}, {Name: "lounge"}}, tt.preferRecent)
+			now := time.Now()
+			for i, s := range tt.steps {
+				now = now.Add(time.Second)
+				source, track := a.update(s.source, s.track, now)
+				if source != s.wantSource {
+					t.Fatalf("step %d: expected source %q, got %q", i, s.wantSource, source)
+				}
+				if want := a.tracks[s.wantSource].track; track != want {
+					t.Errorf("step %d: expected %s's track %+v, got %+v", i, s.wantSource, want, track)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleUpdates_TracksOnlyTheActiveSource(t *testing.T) {
+	d := newControlTestDaemon(t)
+	d.arbiter = newArbiter([]Source{{Name: "desk", Priority: 10}, {Name: "lounge"}}, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan TrackUpdate)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.handleUpdates(ctx, updates)
+	}()
+
+	desk := &music.Track{Name: "Desk Song", Artist: "Artist", Duration: 3 * time.Minute, State: music.StatePlaying}
+	lounge := &music.Track{Name: "Lounge Song", Artist: "Artist", Duration: 3 * time.Minute, State: music.StatePlaying}
+
+	// The second send waits for the first update to be handled
+	updates <- TrackUpdate{Source: "desk", Track: desk}
+	updates <- TrackUpdate{Source: "desk", Track: desk}
+	started := d.state.GetState().PlayStartedAt
+
+	// The lounge player polling in between doesn't restart desk's play
+	for range 3 {
+		updates <- TrackUpdate{Source: "lounge", Track: lounge}
+		updates <- TrackUpdate{Source: "desk", Track: desk}
+	}
+	cancel()
+	<-done
+
+	state := d.state.GetState()
+	if state.Track.Name != "Desk Song" || state.Source != "desk" || !state.PlayStartedAt.Equal(started) {
+		t.Errorf("expected desk's play to continue, got %+v", state)
+	}
+
+	if err := d.queueScrobble(state); err != nil {
+		t.Fatalf("queueScrobble: %v", err)
+	}
+	pending, err := d.queue.GetPending(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("GetPending: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Source != "desk" {
+		t.Errorf("expected one scrobble from desk, got %+v", pending)
+	}
+}
+
+// startHandleUpdates runs d.handleUpdates, returning a function that sends
+// an update and waits for it to be handled, and one that stops the loop
+func startHandleUpdates(t *testing.T, d *Daemon) (send func(string, *music.Track), stop func()) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan TrackUpdate)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.handleUpdates(ctx, updates)
+	}()
+	t.Cleanup(cancel)
+
+	send = func(source string, track *music.Track) {
+		// The second send waits for the first update to be handled
+		updates <- TrackUpdate{Source: source, Track: track}
+		updates <- TrackUpdate{Source: source, Track: track}
+	}
+	stop = func() {
+		cancel()
+		<-done
+	}
+	return send, stop
+}
+
+func TestHandleUpdates_ResumesPreemptedPlay(t *testing.T) {
+	d := newControlTestDaemon(t)
+	d.arbiter = newArbiter([]Source{{Name: "desk", Priority: 10}, {Name: "lounge"}}, false)
+	store, err := history.NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	d.history = store
+	send, stop := startHandleUpdates(t, d)
+
+	desk := &music.Track{Name: "Desk Song", Artist: "Artist", Duration: 3 * time.Minute, State: music.StatePlaying}
+	lounge := &music.Track{Name: "Lounge Song", Artist: "Artist", Duration: 3 * time.Minute, State: music.StatePlaying}
+
+	send("lounge", lounge)
+	started := d.state.GetState().PlayStartedAt
+	if err := d.queueScrobble(d.state.GetState()); err != nil {
+		t.Fatalf("queueScrobble: %v", err)
+	}
+
+	// Desk takes over, then stops, handing back to lounge
+	send("desk", desk)
+	if state := d.state.GetState(); state.Source != "desk" || state.Scrobbled {
+		t.Fatalf("expected a new play on desk, got %+v", state)
+	}
+	send("desk", nil)
+	send("lounge", lounge)
+
+	state := d.state.GetState()
+	if state.Source != "lounge" || state.Track.Name != "Lounge Song" ||
+		!state.Scrobbled || !state.PlayStartedAt.Equal(started) {
+		t.Errorf("expected lounge's scrobbled play to continue, got %+v", state)
+	}
+	if err := d.checkAndScrobble(); err != nil {
+		t.Fatalf("checkAndScrobble: %v", err)
+	}
+	if n, err := d.queue.Count(context.Background(), true); err != nil || n != 1 {
+		t.Errorf("expected lounge's play to be queued once, got %d, %v", n, err)
+	}
+
+	send("lounge", nil)
+	stop()
+
+	plays, err := store.List(context.Background(), history.Filter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var tracks []string
+	for _, p := range plays {
+		tracks = append(tracks, p.Source+": "+p.Track)
+	}
+	slices.Sort(tracks)
+	if want := []string{"desk: Desk Song", "lounge: Lounge Song"}; !slices.Equal(tracks, want) {
+		t.Errorf("expected each play recorded once, got %v", tracks)
+	}
+}
+
+func TestHandleUpdates_KeepsPausedSourcesPlayTime(t *testing.T) {
+	d := newControlTestDaemon(t)
+	d.arbiter = newArbiter([]Source{{Name: "desk", Priority: 10}, {Name: "lounge"}}, false)
+	send, stop := startHandleUpdates(t, d)
+	defer stop()
+
+	desk := &music.Track{Name: "Desk Song", Artist: "Artist", Duration: 3 * time.Minute, State: music.StatePlaying}
+	deskPaused := &music.Track{Name: "Desk Song", Artist: "Artist", Duration: 3 * time.Minute, State: music.StatePaused}
+	lounge := &music.Track{Name: "Lounge Song", Artist: "Artist", Duration: 3 * time.Minute, State: music.StatePlaying}
+
+	send("desk", desk)
+	time.Sleep(50 * time.Millisecond)
+	started := d.state.GetState().PlayStartedAt
+
+	// Pausing desk hands over to the playing lounge until desk resumes
+	send("desk", deskPaused)
+	send("lounge", lounge)
+	if state := d.state.GetState(); state.Source != "lounge" {
+		t.Fatalf("expected lounge to take over, got %+v", state)
+	}
+	send("desk", desk)
+
+	state := d.state.GetState()
+	if state.Source != "desk" || !state.PlayStartedAt.Equal(started) {
+		t.Errorf("expected desk's play to continue, got %+v", state)
+	}
+	if played := d.state.GetPlayedDuration(); played < 50*time.Millisecond {
+		t.Errorf("expected desk's play time to be kept, got %v", played)
+	}
+}
@@ -0,0 +1,457 @@
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/events"
+	"github.com/jfmyers9/scribbles/internal/history"
+	"github.com/jfmyers9/scribbles/internal/music"
+	"github.com/jfmyers9/scribbles/internal/scrobbler"
+)
+
+// ControlSocketFile is the name of the control socket in the data directory
+const ControlSocketFile = "control.sock"
+
+// Control socket methods. The socket speaks JSON-RPC 2.0, one request or
+// response per line. None of the methods take params.
+const (
+	MethodStatus        = "status"         // Returns a Status
+	MethodSkipScrobble  = "skip-scrobble"  // Excludes the current play from scrobbling
+	MethodForceScrobble = "force-scrobble" // Queues the current play now, regardless of play time
+	MethodLove          = "love"           // Loves the current track on backends that support it
+	MethodPause         = "pause"          // Stops queueing scrobbles until resumed
+	MethodResume        = "resume"         // Resumes queueing scrobbles
+)
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeServerError    = -32000
+)
+
+// recentPlaysLimit is how many plays from the history a Status includes
+const recentPlaysLimit = 10
+
+var (
+	// ErrNoTrack is returned by control commands when nothing is playing
+	ErrNoTrack = errors.New("no track is playing")
+
+	// ErrAlreadyScrobbled is returned when the current play is already queued
+	ErrAlreadyScrobbled = errors.New("the current track has already been scrobbled")
+)
+
+// Status is a snapshot of the daemon, as served by the control socket
+type Status struct {
+	State            TrackState    `json:"state"`
+	Played           time.Duration `json:"played"`            // Play time of the current track, excluding pauses
+	WillScrobble     bool          `json:"will_scrobble"`     // The current play is scrobbled once it has played ScrobbleIn more
+	ScrobbleIn       time.Duration `json:"scrobble_in"`       // Play time left until the current track is scrobbled
+	ScrobblingPaused bool          `json:"scrobbling_paused"` // No scrobbles are queued until resumed
+	Pending          int           `json:"pending"`           // Scrobbles waiting to be delivered
+	Recent           []RecentPlay  `json:"recent"`            // Latest plays from the history, newest first
+
+	Subscribers []events.SubscriberStats `json:"subscribers"` // Event subscribers and the events they dropped
+}
+
+// RecentPlay is a finished play from the listening history
+type RecentPlay struct {
+	Track     string        `json:"track"`
+	Artist    string        `json:"artist"`
+	Album     string        `json:"album,omitempty"`
+	StartedAt time.Time     `json:"started_at"`
+	Played    time.Duration `json:"played"`
+	Skipped   bool          `json:"skipped"`
+	Scrobbled bool          `json:"scrobbled"`
+}
+
+// RPCError is a JSON-RPC error returned over the control socket
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error returns the error message
+func (e *RPCError) Error() string {
+	return e.Message
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // Absent for notifications
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// Status returns a snapshot of the current track, the queue and the
+// latest plays
+func (d *Daemon) Status(ctx context.Context) (Status, error) {
+	state := d.state.GetState()
+	status := Status{
+		State:            state,
+		Played:           d.state.GetPlayedDuration(),
+		ScrobblingPaused: d.scrobblingPaused.Load(),
+		Pending:          d.GetPendingCount(),
+		Recent:           []RecentPlay{},
+		Subscribers:      d.EventStats(),
+	}
+
+	if state.Track != nil && !state.Scrobbled && !state.ScrobbleSkipped && !status.ScrobblingPaused &&
+		state.Track.Duration >= scrobbler.MinimumTrackDuration {
+		status.WillScrobble = true
+		status.ScrobbleIn = max(scrobbler.ScrobbleThreshold(state.Track.Duration)-status.Played, 0)
+	}
+
+	if d.history != nil {
+		plays, err := d.history.List(ctx, history.Filter{Limit: recentPlaysLimit})
+		if err != nil {
+			return Status{}, err
+		}
+		for _, p := range plays {
+			status.Recent = append(status.Recent, RecentPlay{
+				Track:     p.Track,
+				Artist:    p.Artist,
+				Album:     p.Album,
+				StartedAt: p.StartedAt,
+				Played:    p.Played,
+				Skipped:   p.Skipped,
+				Scrobbled: p.Scrobbled,
+			})
+		}
+	}
+
+	return status, nil
+}
+
+// SkipScrobble keeps the current play from being scrobbled
+func (d *Daemon) SkipScrobble() error {
+	d.scrobbleMu.Lock()
+	defer d.scrobbleMu.Unlock()
+
+	state := d.state.GetState()
+	if state.Track == nil {
+		return ErrNoTrack
+	}
+	if state.Scrobbled {
+		return ErrAlreadyScrobbled
+	}
+
+	d.logger.Info().Str("track", state.Track.Name).Msg("Skipping scrobble")
+	return d.state.SkipScrobble()
+}
+
+// ForceScrobble queues the current play immediately, even if it has not
+// played long enough, was skipped, or scrobbling is paused
+func (d *Daemon) ForceScrobble() error {
+	d.scrobbleMu.Lock()
+	defer d.scrobbleMu.Unlock()
+
+	state := d.state.GetState()
+	if state.Track == nil {
+		return ErrNoTrack
+	}
+	if state.Scrobbled {
+		return ErrAlreadyScrobbled
+	}
+
+	d.logger.Info().
+		Str("track", state.Track.Name).
+		Str("artist", state.Track.Artist).
+		Msg("Forcing scrobble")
+
+	return d.queueScrobble(state)
+}
+
+// Love marks the current track as loved on every backend that supports it,
+// and on the source playing it if it can, such as a Subsonic server
+func (d *Daemon) Love(ctx context.Context) error {
+	state := d.state.GetState()
+	if state.Track == nil {
+		return ErrNoTrack
+	}
+
+	var errs []error
+	loved := false
+	for _, b := range d.backends {
+		lover, ok := b.(scrobbler.Lover)
+		if !ok {
+			continue
+		}
+		loved = true
+		if err := lover.LoveTrack(ctx, state.Track.Artist, state.Track.Name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", b.Name(), err))
+			continue
+		}
+		d.logger.Info().Str("track", state.Track.Name).Str("backend", b.Name()).Msg("Loved track")
+	}
+
+	if lover, ok := d.arbiter.client(state.Source).(scrobbler.Lover); ok {
+		loved = true
+		if err := lover.LoveTrack(ctx, state.Track.Artist, state.Track.Name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", state.Source, err))
+		} else {
+			d.logger.Info().Str("track", state.Track.Name).Str("source", state.Source).Msg("Loved track")
+		}
+	}
+
+	if !loved {
+		return errors.New("none of the scrobbling backends or the source support loving tracks")
+	}
+	return errors.Join(errs...)
+}
+
+// PauseScrobbling stops the daemon from queueing scrobbles. Plays are
+// still tracked and recorded in the history.
+func (d *Daemon) PauseScrobbling() {
+	d.scrobblingPaused.Store(true)
+	d.logger.Info().Msg("Scrobbling paused")
+}
+
+// ResumeScrobbling undoes PauseScrobbling
+func (d *Daemon) ResumeScrobbling() {
+	d.scrobblingPaused.Store(false)
+	d.logger.Info().Msg("Scrobbling resumed")
+}
+
+// serveControl listens on the control socket until ctx is cancelled
+func (d *Daemon) serveControl(ctx context.Context, path string) error {
+	ln, err := listenControl(path)
+	if err != nil {
+		return err
+	}
+	d.logger.Info().Str("socket", path).Msg("Control socket listening")
+
+	stop := context.AfterFunc(ctx, func() { _ = ln.Close() })
+	defer stop()
+
+	var conns sync.WaitGroup
+	defer conns.Wait()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to accept control connection: %w", err)
+		}
+
+		conns.Add(1)
+		go func() {
+			defer conns.Done()
+			d.serveControlConn(ctx, conn)
+		}()
+	}
+}
+
+// listenControl creates the control socket. A socket left behind by a
+// daemon that did not shut down cleanly is replaced, but not one that
+// another daemon is still listening on.
+func listenControl(path string) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("another daemon is already listening on %s", path)
+		}
+		_ = os.Remove(path)
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on control socket: %w", err)
+	}
+
+	// Only the user running the daemon may control it
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("failed to set control socket permissions: %w", err)
+	}
+
+	return ln, nil
+}
+
+// serveControlConn answers requests on one connection until the client
+// disconnects or ctx is cancelled
+func (d *Daemon) serveControlConn(ctx context.Context, conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	scanner := bufio.NewScanner(conn)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		resp, ok := d.handleControl(ctx, line)
+		if !ok {
+			continue // Notifications get no response
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// handleControl answers a single JSON-RPC request. It reports false for
+// notifications, which are run but not answered.
+func (d *Daemon) handleControl(ctx context.Context, data []byte) (rpcResponse, bool) {
+	var req rpcRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return errorResponse(nil, &RPCError{Code: codeParseError, Message: "parse error"}), true
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, &RPCError{Code: codeInvalidRequest, Message: "invalid request"}), true
+	}
+
+	result, err := d.callControl(ctx, req.Method)
+	if req.ID == nil {
+		return rpcResponse{}, false
+	}
+	if err != nil {
+		var rpcErr *RPCError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &RPCError{Code: codeServerError, Message: err.Error()}
+		}
+		return errorResponse(req.ID, rpcErr), true
+	}
+
+	data, err = json.Marshal(result)
+	if err != nil {
+		return errorResponse(req.ID, &RPCError{Code: codeServerError, Message: err.Error()}), true
+	}
+	return rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: data}, true
+}
+
+// callControl runs a control method and returns its result
+func (d *Daemon) callControl(ctx context.Context, method string) (any, error) {
+	switch method {
+	case MethodStatus:
+		return d.Status(ctx)
+	case MethodSkipScrobble:
+		return nil, d.SkipScrobble()
+	case MethodForceScrobble:
+		return nil, d.ForceScrobble()
+	case MethodLove:
+		return nil, d.Love(ctx)
+	case MethodPause:
+		d.PauseScrobbling()
+		return nil, nil
+	case MethodResume:
+		d.ResumeScrobbling()
+		return nil, nil
+	default:
+		return nil, &RPCError{Code: codeMethodNotFound, Message: "method not found: " + method}
+	}
+}
+
+func errorResponse(id json.RawMessage, err *RPCError) rpcResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return rpcResponse{JSONRPC: "2.0", ID: id, Error: err}
+}
+
+// ControlClient calls a running daemon over its control socket. It is safe
+// for concurrent use; calls are sent one at a time.
+type ControlClient struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	dec    *json.Decoder
+	nextID int64
+}
+
+// DialControl connects to the control socket of a running daemon
+func DialControl(path string) (*ControlClient, error) {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	return &ControlClient{conn: conn, dec: json.NewDecoder(conn)}, nil
+}
+
+// Close closes the connection
+func (c *ControlClient) Close() error {
+	return c.conn.Close()
+}
+
+// Call runs a control method and decodes its result into result, unless
+// result is nil. Errors returned by the daemon are *RPCError.
+func (c *ControlClient) Call(ctx context.Context, method string, result any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	deadline, _ := ctx.Deadline()
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	// Unblock the call if ctx is cancelled before its deadline
+	stop := context.AfterFunc(ctx, func() { _ = c.conn.SetDeadline(time.Unix(1, 0)) })
+	defer stop()
+
+	c.nextID++
+	req, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage(strconv.FormatInt(c.nextID, 10)),
+		Method:  method,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := c.conn.Write(append(req, '\n')); err != nil {
+		return fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+
+	var resp rpcResponse
+	if err := c.dec.Decode(&resp); err != nil {
+		return fmt.Errorf("failed to read %s response: %w", method, err)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result != nil {
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// Status returns the daemon's status
+func (c *ControlClient) Status(ctx context.Context) (*Status, error) {
+	var status Status
+	if err := c.Call(ctx, MethodStatus, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// CurrentTrack returns the track the daemon is tracking, or nil if nothing
+// is playing
+func (c *ControlClient) CurrentTrack(ctx context.Context) (*music.Track, error) {
+	status, err := c.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return status.State.Track, nil
+}
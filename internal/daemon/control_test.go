@@ -0,0 +1,292 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/music"
+	"github.com/jfmyers9/scribbles/internal/scrobbler"
+)
+
+// fakeLover is a backend that records loved tracks
+type fakeLover struct {
+	fakeBackend
+	loved []string
+}
+
+func (f *fakeLover) LoveTrack(ctx context.Context, artist, track string) error {
+	f.loved = append(f.loved, artist+" - "+track)
+	return nil
+}
+
+// startControl serves a test daemon's control socket and connects to it
+func startControl(t *testing.T, d *Daemon) *ControlClient {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ControlSocketFile)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- d.serveControl(ctx, path) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("serveControl: %v", err)
+		}
+	})
+
+	var client *ControlClient
+	var err error
+	for range 100 {
+		if client, err = DialControl(path); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("DialControl: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func newControlTestDaemon(t *testing.T, backends ...scrobbler.Backend) *Daemon {
+	t.Helper()
+	d := newTestDaemon(t)
+	state, err := NewState("")
+	if err != nil {
+		t.Fatalf("NewState: %v", err)
+	}
+	d.state = state
+	d.backends = backends
+	d.arbiter = newArbiter(nil, false)
+	return d
+}
+
+func TestControl_Status(t *testing.T) {
+	d := newControlTestDaemon(t)
+	client := startControl(t, d)
+	ctx := context.Background()
+
+	status, err := client.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.State.Track != nil || status.WillScrobble || status.Pending != 0 {
+		t.Errorf("expected an idle status, got %+v", status)
+	}
+
+	track := &music.Track{Name: "Song", Artist: "Artist", Duration: 4 * time.Minute, State: music.StatePlaying}
+	if err := d.state.SetTrack("apple-music", track); err != nil {
+		t.Fatalf("SetTrack: %v", err)
+	}
+
+	status, err = client.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.State.Track == nil || status.State.Track.Name != "Song" {
+		t.Fatalf("expected the current track, got %+v", status.State.Track)
+	}
+	if !status.WillScrobble || status.ScrobbleIn <= 0 || status.ScrobbleIn > 2*time.Minute {
+		t.Errorf("expected the track to scrobble within 2 minutes, got will_scrobble=%v in %v", status.WillScrobble, status.ScrobbleIn)
+	}
+
+	current, err := client.CurrentTrack(ctx)
+	if err != nil {
+		t.Fatalf("CurrentTrack: %v", err)
+	}
+	if current == nil || current.Artist != "Artist" {
+		t.Errorf("expected CurrentTrack to return the daemon's track, got %+v", current)
+	}
+}
+
+func TestControl_Commands(t *testing.T) {
+	lover := &fakeLover{fakeBackend: fakeBackend{name: "lastfm"}}
+	d := newControlTestDaemon(t, lover, &fakeBackend{name: "listenbrainz"})
+	client := startControl(t, d)
+	ctx := context.Background()
+
+	if err := client.Call(ctx, MethodForceScrobble, nil); err == nil || err.Error() != ErrNoTrack.Error() {
+		t.Errorf("expected %q with nothing playing, got %v", ErrNoTrack, err)
+	}
+
+	track := &music.Track{Name: "Song", Artist: "Artist", Duration: 4 * time.Minute, State: music.StatePlaying}
+	if err := d.state.SetTrack("apple-music", track); err != nil {
+		t.Fatalf("SetTrack: %v", err)
+	}
+
+	// A skipped play is not scrobbled once it is eligible
+	if err := client.Call(ctx, MethodSkipScrobble, nil); err != nil {
+		t.Fatalf("skip-scrobble: %v", err)
+	}
+	status, err := client.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if !status.State.ScrobbleSkipped || status.WillScrobble {
+		t.Errorf("expected the play to be skipped, got %+v", status)
+	}
+
+	// Forcing queues it anyway, once
+	if err := client.Call(ctx, MethodForceScrobble, nil); err != nil {
+		t.Fatalf("force-scrobble: %v", err)
+	}
+	if status, err = client.Status(ctx); err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if !status.State.Scrobbled || status.Pending != 1 {
+		t.Errorf("expected the play to be queued, got scrobbled=%v pending=%d", status.State.Scrobbled, status.Pending)
+	}
+	if err := client.Call(ctx, MethodForceScrobble, nil); err == nil || err.Error() != ErrAlreadyScrobbled.Error() {
+		t.Errorf("expected %q, got %v", ErrAlreadyScrobbled, err)
+	}
+
+	if err := client.Call(ctx, MethodLove, nil); err != nil {
+		t.Fatalf("love: %v", err)
+	}
+	if len(lover.loved) != 1 || lover.loved[0] != "Artist - Song" {
+		t.Errorf("expected the track to be loved once, got %v", lover.loved)
+	}
+
+	if err := client.Call(ctx, MethodPause, nil); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if status, err = client.Status(ctx); err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if !status.ScrobblingPaused {
+		t.Error("expected scrobbling to be paused")
+	}
+
+	// Nothing new is queued while paused
+	if err := d.state.SetTrack("apple-music", &music.Track{Name: "Short", Artist: "Artist", Duration: 40 * time.Second, State: music.StatePlaying}); err != nil {
+		t.Fatalf("SetTrack: %v", err)
+	}
+	if status, err = client.Status(ctx); err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.WillScrobble {
+		t.Error("expected no scrobble while paused")
+	}
+
+	if err := client.Call(ctx, MethodResume, nil); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if status, err = client.Status(ctx); err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.ScrobblingPaused || !status.WillScrobble {
+		t.Errorf("expected scrobbling to resume, got %+v", status)
+	}
+
+	var rpcErr *RPCError
+	if err := client.Call(ctx, "rewind", nil); !errors.As(err, &rpcErr) || rpcErr.Code != codeMethodNotFound {
+		t.Errorf("expected method not found, got %v", err)
+	}
+}
+
+// fakeLoverSource is a music source that records loved tracks
+type fakeLoverSource struct {
+	music.Client
+	loved []string
+}
+
+func (f *fakeLoverSource) LoveTrack(ctx context.Context, artist, track string) error {
+	f.loved = append(f.loved, artist+" - "+track)
+	return nil
+}
+
+func TestControl_LoveMirroredToSource(t *testing.T) {
+	lover := &fakeLover{fakeBackend: fakeBackend{name: "lastfm"}}
+	d := newControlTestDaemon(t, lover)
+	navidrome := &fakeLoverSource{}
+	d.arbiter = newArbiter([]Source{{Name: "desk", Client: &fakeLoverSource{}}, {Name: "navidrome", Client: navidrome}}, false)
+	if err := d.state.SetTrack("navidrome", &music.Track{Name: "Song", Artist: "Artist", State: music.StatePlaying}); err != nil {
+		t.Fatalf("SetTrack: %v", err)
+	}
+
+	if err := d.Love(context.Background()); err != nil {
+		t.Fatalf("Love: %v", err)
+	}
+	if len(lover.loved) != 1 || len(navidrome.loved) != 1 || navidrome.loved[0] != "Artist - Song" {
+		t.Errorf("expected the backend and the playing source to love the track, got %v and %v", lover.loved, navidrome.loved)
+	}
+}
+
+func TestControl_LoveUnsupported(t *testing.T) {
+	d := newControlTestDaemon(t, &fakeBackend{name: "listenbrainz"})
+	if err := d.state.SetTrack("apple-music", &music.Track{Name: "Song", Artist: "Artist", State: music.StatePlaying}); err != nil {
+		t.Fatalf("SetTrack: %v", err)
+	}
+
+	err := d.Love(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "support loving") {
+		t.Errorf("expected an error without a backend that can love tracks, got %v", err)
+	}
+}
+
+func TestHandleControl(t *testing.T) {
+	d := newControlTestDaemon(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		request string
+		code    int  // Expected error code, 0 for success
+		reply   bool // Whether a response is expected
+	}{
+		{"parse error", `{"jsonrpc":`, codeParseError, true},
+		{"wrong version", `{"jsonrpc":"1.0","id":1,"method":"status"}`, codeInvalidRequest, true},
+		{"missing method", `{"jsonrpc":"2.0","id":1}`, codeInvalidRequest, true},
+		{"unknown method", `{"jsonrpc":"2.0","id":1,"method":"rewind"}`, codeMethodNotFound, true},
+		{"status", `{"jsonrpc":"2.0","id":"a","method":"status"}`, 0, true},
+		{"notification", `{"jsonrpc":"2.0","method":"pause"}`, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, ok := d.handleControl(ctx, []byte(tt.request))
+			if ok != tt.reply {
+				t.Fatalf("expected reply=%v, got %v", tt.reply, ok)
+			}
+			if !ok {
+				return
+			}
+			if resp.JSONRPC != "2.0" || resp.ID == nil {
+				t.Errorf("expected a JSON-RPC 2.0 response with an id, got %+v", resp)
+			}
+			switch {
+			case tt.code == 0 && resp.Error != nil:
+				t.Errorf("unexpected error: %v", resp.Error)
+			case tt.code == 0 && !json.Valid(resp.Result):
+				t.Errorf("expected a result, got %q", resp.Result)
+			case tt.code != 0 && (resp.Error == nil || resp.Error.Code != tt.code):
+				t.Errorf("expected error code %d, got %+v", tt.code, resp.Error)
+			}
+		})
+	}
+
+	// The notification was still run
+	if !d.scrobblingPaused.Load() {
+		t.Error("expected the pause notification to pause scrobbling")
+	}
+}
+
+func TestListenControl_RefusesRunningDaemon(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ControlSocketFile)
+
+	ln, err := listenControl(path)
+	if err != nil {
+		t.Fatalf("listenControl: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	if _, err := listenControl(path); err == nil || !strings.Contains(err.Error(), "already listening") {
+		t.Errorf("expected a second daemon to be refused, got %v", err)
+	}
+}
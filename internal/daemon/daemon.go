@@ -6,43 +6,70 @@ import (
 	"os"
 	"os/signal"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"github.com/jfmyers9/scribbles/internal/discord"
+	"github.com/jfmyers9/scribbles/internal/events"
+	"github.com/jfmyers9/scribbles/internal/history"
 	"github.com/jfmyers9/scribbles/internal/music"
 	"github.com/jfmyers9/scribbles/internal/scrobbler"
 	"github.com/rs/zerolog"
 )
 
+// Deadlines for requests to scrobbling backends, so a slow or unreachable
+// service can't stall the queue or hold up tracking the next track
+const (
+	submitTimeout     = 30 * time.Second
+	nowPlayingTimeout = 10 * time.Second
+)
+
 // Config holds daemon configuration
 type Config struct {
 	PollInterval      time.Duration // How often to poll Music app
 	StateFile         string        // Path to state persistence file
 	QueueDB           string        // Path to scrobble queue database
+	HistoryDB         string        // Path to listening history database (empty disables history)
+	PreferRecent      bool          // Arbitrate between sources by most recently started rather than priority
 	ProcessInterval   time.Duration // How often to process scrobble queue
 	ScrobbleThreshold float64       // Percentage threshold (0.0-1.0) for scrobbling
+	ControlSocket     string        // Path to the control socket (empty disables it)
+	HTTPAddr          string        // Address of the HTTP API, e.g. 127.0.0.1:7415 (empty disables it)
+	HTTPAllowOrigin   string        // Access-Control-Allow-Origin for the HTTP API (empty sends none)
 }
 
-// Daemon coordinates the music poller, state tracking, and scrobbling
+// Daemon coordinates the music pollers, state tracking, and scrobbling
 type Daemon struct {
 	config   Config
-	client   music.Client
-	scrobble *scrobbler.Client
+	backends []scrobbler.Backend
 	queue    *scrobbler.Queue
+	history  *history.Store // nil when history is disabled
 	state    *State
-	poller   *Poller
+	pollers  []*Poller // One per source
+	arbiter  *arbiter
 	logger   zerolog.Logger
 
-	// TUI support
-	tuiUpdates chan TrackUpdate // Channel for TUI to receive updates
+	// scrobblingPaused stops tracks from being queued until resumed
+	scrobblingPaused atomic.Bool
+	scrobbleMu       sync.Mutex // Serializes queueing or skipping the current play
+
+	// bus carries track and scrobble events to subscribers such as the
+	// TUI, Discord Rich Presence and the HTTP API
+	bus events.Bus
 
-	// Discord Rich Presence support
-	discordUpdates chan discord.TrackUpdate
+	metrics *daemonMetrics
 }
 
-// New creates a new Daemon instance
-func New(cfg Config, musicClient music.Client, scrobbleClient *scrobbler.Client, logger zerolog.Logger) (*Daemon, error) {
+// New creates a new Daemon instance that follows the given music sources
+// and delivers every scrobble to each of the given backends
+func New(cfg Config, sources []Source, backends []scrobbler.Backend, logger zerolog.Logger) (*Daemon, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("at least one music source is required")
+	}
+	if len(backends) == 0 {
+		return nil, fmt.Errorf("at least one scrobbling backend is required")
+	}
+
 	// Create state
 	state, err := NewState(cfg.StateFile)
 	if err != nil {
@@ -55,18 +82,43 @@ func New(cfg Config, musicClient music.Client, scrobbleClient *scrobbler.Client,
 		return nil, fmt.Errorf("failed to create queue: %w", err)
 	}
 
-	// Create poller
-	poller := NewPoller(musicClient, cfg.PollInterval, logger)
+	names := make([]string, len(backends))
+	for i, b := range backends {
+		names[i] = b.Name()
+	}
+	if err := queue.SetBackends(context.Background(), names); err != nil {
+		_ = queue.Close()
+		return nil, fmt.Errorf("failed to set queue backends: %w", err)
+	}
+
+	// Open listening history
+	var plays *history.Store
+	if cfg.HistoryDB != "" {
+		plays, err = history.NewStore(cfg.HistoryDB)
+		if err != nil {
+			_ = queue.Close()
+			return nil, fmt.Errorf("failed to open history: %w", err)
+		}
+	}
+
+	// Create a poller for each source
+	pollers := make([]*Poller, len(sources))
+	for i, s := range sources {
+		pollers[i] = NewPoller(s.Name, s.Client, cfg.PollInterval, logger)
+	}
 
-	return &Daemon{
+	d := &Daemon{
 		config:   cfg,
-		client:   musicClient,
-		scrobble: scrobbleClient,
+		backends: backends,
 		queue:    queue,
+		history:  plays,
 		state:    state,
-		poller:   poller,
+		pollers:  pollers,
+		arbiter:  newArbiter(sources, cfg.PreferRecent),
 		logger:   logger.With().Str("component", "daemon").Logger(),
-	}, nil
+	}
+	d.metrics = newDaemonMetrics(d)
+	return d, nil
 }
 
 // Run starts the daemon and blocks until shutdown signal received
@@ -105,14 +157,16 @@ func (d *Daemon) run(ctx context.Context) error {
 	var wg sync.WaitGroup
 	updates := make(chan TrackUpdate, 10)
 
-	// Start poller
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := d.poller.Run(ctx, updates); err != nil && err != context.Canceled {
-			d.logger.Error().Err(err).Msg("Poller error")
-		}
-	}()
+	// Start pollers
+	for _, poller := range d.pollers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := poller.Run(ctx, updates); err != nil && err != context.Canceled {
+				d.logger.Error().Err(err).Msg("Poller error")
+			}
+		}()
+	}
 
 	// Start queue processor
 	wg.Add(1)
@@ -130,6 +184,28 @@ func (d *Daemon) run(ctx context.Context) error {
 		d.checkScrobbleEligibility(ctx)
 	}()
 
+	// Start control socket
+	if d.config.ControlSocket != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.serveControl(ctx, d.config.ControlSocket); err != nil {
+				d.logger.Error().Err(err).Msg("Control socket error")
+			}
+		}()
+	}
+
+	// Start HTTP API
+	if d.config.HTTPAddr != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.serveHTTP(ctx, d.config.HTTPAddr); err != nil {
+				d.logger.Error().Err(err).Msg("HTTP API error")
+			}
+		}()
+	}
+
 	// Main loop: handle track updates
 	wg.Add(1)
 	go func() {
@@ -144,83 +220,185 @@ func (d *Daemon) run(ctx context.Context) error {
 	return nil
 }
 
-// handleUpdates processes track updates from the poller
+// handleUpdates processes track updates from the pollers, following the
+// source the arbiter picks
 func (d *Daemon) handleUpdates(ctx context.Context, updates <-chan TrackUpdate) {
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case update := <-updates:
-			// Forward to TUI if enabled (non-blocking)
-			if d.tuiUpdates != nil {
-				select {
-				case d.tuiUpdates <- update:
-				default:
-				}
-			}
-
-			// Forward to Discord if enabled (non-blocking)
-			if d.discordUpdates != nil {
-				du := discord.TrackUpdate{Track: update.Track, Err: update.Err}
-				select {
-				case d.discordUpdates <- du:
-				default:
-				}
-			}
+			d.metrics.observePoll(update)
 
 			if update.Err != nil {
-				// Log error but continue
-				d.logger.Debug().Err(update.Err).Msg("Track update error")
+				// Log error but continue, keeping the source's last track
+				d.logger.Debug().Err(update.Err).Str("source", update.Source).Msg("Track update error")
 				continue
 			}
 
-			if err := d.handleTrackUpdate(update.Track); err != nil {
+			source, track := d.arbiter.update(update.Source, update.Track, time.Now())
+			if err := d.handleTrackUpdate(source, track); err != nil {
 				d.logger.Error().Err(err).Msg("Failed to handle track update")
+				d.bus.Publish(events.Error{Message: err.Error()})
+			}
+
+			// A source that was taken over may have moved on in the meantime
+			play, played, finished, err := d.state.FinishSuspended(update.Source, update.Track)
+			if err != nil {
+				d.logger.Error().Err(err).Msg("Failed to finish suspended play")
+			}
+			if finished {
+				d.finishPlay(play, played)
 			}
 		}
 	}
 }
 
-// handleTrackUpdate processes a single track update
-func (d *Daemon) handleTrackUpdate(track *music.Track) error {
+// handleTrackUpdate processes a single track update from source
+func (d *Daemon) handleTrackUpdate(source string, track *music.Track) error {
 	currentState := d.state.GetState()
 
 	// No track playing - reset state if needed
 	if track == nil || track.State == music.StateStopped {
 		if currentState.Track != nil {
 			d.logger.Info().Msg("Music stopped")
+			d.finishPlay(currentState, d.state.GetPlayedDuration())
+			d.bus.Publish(events.TrackStopped{})
 			return d.state.Reset()
 		}
 		return nil
 	}
 
+	// Another source took over. Its play is set aside rather than finished,
+	// so it continues if that source becomes active again with the same
+	// track. State saved before sources were recorded has no source, and
+	// matches any.
+	if currentState.Track != nil && currentState.Source != "" && currentState.Source != source {
+		if err := d.state.Suspend(); err != nil {
+			return fmt.Errorf("failed to suspend play: %w", err)
+		}
+		currentState = TrackState{}
+	}
+
+	if currentState.Track == nil {
+		resumed, err := d.state.Resume(source, track)
+		if err != nil {
+			return fmt.Errorf("failed to resume play: %w", err)
+		}
+		if resumed {
+			d.logger.Info().
+				Str("track", track.Name).
+				Str("artist", track.Artist).
+				Str("source", source).
+				Msg("Source resumed")
+
+			d.bus.Publish(events.TrackStarted{Track: *track, Source: source, Resumed: true})
+			d.updateNowPlaying(track)
+			return nil
+		}
+	}
+
 	// Check if track changed
-	trackChanged := currentState.Track == nil ||
-		!isSameTrack(currentState.Track, track)
+	if currentState.Track == nil || !isSameTrack(currentState.Track, track) {
+		if currentState.Track != nil {
+			d.finishPlay(currentState, d.state.GetPlayedDuration())
+		}
 
-	if trackChanged {
 		d.logger.Info().
 			Str("track", track.Name).
 			Str("artist", track.Artist).
+			Str("source", source).
 			Msg("Track changed")
 
 		// Update state with new track
-		if err := d.state.SetTrack(track); err != nil {
+		if err := d.state.SetTrack(source, track); err != nil {
 			return fmt.Errorf("failed to set track: %w", err)
 		}
+		d.metrics.trackChanges.Inc()
+		d.bus.Publish(events.TrackStarted{Track: *track, Source: source})
 
-		// Update Now Playing on Last.fm
-		ctx := context.Background()
-		if err := d.scrobble.UpdateNowPlaying(ctx, track.Artist, track.Name, track.Album, track.Duration); err != nil {
-			d.logger.Warn().Err(err).Msg("Failed to update Now Playing")
+		d.updateNowPlaying(track)
+		return nil
+	}
+
+	// Same track - update position
+	wasPaused := !currentState.PausedAt.IsZero()
+	if err := d.state.UpdatePosition(track); err != nil {
+		return err
+	}
+
+	switch paused := !d.state.GetState().PausedAt.IsZero(); {
+	case paused && !wasPaused:
+		d.bus.Publish(events.TrackPaused{Track: *track})
+	case !paused && wasPaused:
+		d.bus.Publish(events.TrackResumed{Track: *track})
+	}
+	return nil
+}
+
+// updateNowPlaying updates Now Playing on each scrobbling backend
+func (d *Daemon) updateNowPlaying(track *music.Track) {
+	for _, b := range d.backends {
+		update := events.NowPlayingUpdated{Track: *track, Backend: b.Name()}
+		start := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), nowPlayingTimeout)
+		err := b.UpdateNowPlaying(ctx, track.Artist, track.Name, track.Album, track.Duration)
+		cancel()
+		d.metrics.observeBackend(b.Name(), start)
+		if err != nil {
+			d.logger.Warn().Err(err).Str("backend", b.Name()).Msg("Failed to update Now Playing")
 			// Not a fatal error, continue
+			update.Error = err.Error()
 		}
+		d.bus.Publish(update)
+	}
+}
 
-		return nil
+// finishPlay records a play that ended because the track changed or music
+// stopped, and reports it as skipped if it ended before the scrobble point
+func (d *Daemon) finishPlay(state TrackState, played time.Duration) {
+	d.recordPlay(state, played)
+
+	if state.Track != nil && played > 0 && !state.Scrobbled && isSkipped(state.Track.Duration, played) {
+		d.bus.Publish(events.TrackSkipped{Track: *state.Track, Played: played})
 	}
+}
 
-	// Same track - update position
-	return d.state.UpdatePosition(track)
+// recordPlay adds a finished play to the listening history. Plays are
+// recorded when the track changes or music stops, not on shutdown: the
+// state file carries an unfinished play over to the next daemon run.
+func (d *Daemon) recordPlay(state TrackState, played time.Duration) {
+	if d.history == nil || state.Track == nil || played <= 0 {
+		return
+	}
+
+	startedAt := state.PlayStartedAt
+	if startedAt.IsZero() {
+		// State persisted before PlayStartedAt existed
+		startedAt = state.StartTime
+	}
+
+	play := history.Play{
+		Track:     state.Track.Name,
+		Artist:    state.Track.Artist,
+		Album:     state.Track.Album,
+		Duration:  state.Track.Duration,
+		Played:    played,
+		StartedAt: startedAt,
+		Skipped:   isSkipped(state.Track.Duration, played),
+		Scrobbled: state.Scrobbled,
+		Source:    state.Source,
+	}
+	if _, err := d.history.Add(context.Background(), play); err != nil {
+		d.logger.Warn().Err(err).Str("track", play.Track).Msg("Failed to record play in history")
+	}
+}
+
+// isSkipped reports whether a play ended before the scrobble point (half
+// the track or 4 minutes). Tracks too short to scrobble use the same rule.
+func isSkipped(duration, played time.Duration) bool {
+	threshold := min(time.Duration(float64(duration)*scrobbler.ScrobblePercentage), scrobbler.MaxScrobbleThreshold)
+	return played < threshold
 }
 
 // checkScrobbleEligibility periodically checks if current track is ready to scrobble
@@ -235,6 +413,7 @@ func (d *Daemon) checkScrobbleEligibility(ctx context.Context) {
 		case <-ticker.C:
 			if err := d.checkAndScrobble(); err != nil {
 				d.logger.Error().Err(err).Msg("Failed to check scrobble eligibility")
+				d.bus.Publish(events.Error{Message: err.Error()})
 			}
 		}
 	}
@@ -242,10 +421,13 @@ func (d *Daemon) checkScrobbleEligibility(ctx context.Context) {
 
 // checkAndScrobble checks if current track should be scrobbled and adds to queue
 func (d *Daemon) checkAndScrobble() error {
+	d.scrobbleMu.Lock()
+	defer d.scrobbleMu.Unlock()
+
 	state := d.state.GetState()
 
-	// No track or already scrobbled
-	if state.Track == nil || state.Scrobbled {
+	// No track, already scrobbled, or excluded from scrobbling
+	if state.Track == nil || state.Scrobbled || state.ScrobbleSkipped || d.scrobblingPaused.Load() {
 		return nil
 	}
 
@@ -262,19 +444,33 @@ func (d *Daemon) checkAndScrobble() error {
 		Dur("played", playedDuration).
 		Msg("Scrobbling track")
 
-	// Add to queue
-	timestamp := time.Now()
-	ctx := context.Background()
+	return d.queueScrobble(state)
+}
+
+// queueScrobble adds the current play in state to the queue and marks it
+// as scrobbled
+func (d *Daemon) queueScrobble(state TrackState) error {
 	scrobble := scrobbler.Scrobble{
 		Track:     state.Track.Name,
 		Artist:    state.Track.Artist,
 		Album:     state.Track.Album,
 		Duration:  state.Track.Duration,
-		Timestamp: timestamp,
+		Timestamp: time.Now(),
+		Source:    state.Source,
 	}
-	if _, err := d.queue.Add(ctx, scrobble); err != nil {
+	id, err := d.queue.Add(context.Background(), scrobble)
+	if err != nil {
 		return fmt.Errorf("failed to add to queue: %w", err)
 	}
+	d.metrics.scrobblesQueued.Inc()
+	d.bus.Publish(events.ScrobbleQueued{Scrobble: events.Scrobble{
+		ID:        id,
+		Artist:    scrobble.Artist,
+		Track:     scrobble.Track,
+		Album:     scrobble.Album,
+		Timestamp: scrobble.Timestamp,
+		Source:    scrobble.Source,
+	}})
 
 	// Mark as scrobbled in state
 	if err := d.state.MarkScrobbled(); err != nil {
@@ -305,12 +501,24 @@ func (d *Daemon) processQueue(ctx context.Context) error {
 	}
 }
 
-// processPendingScrobbles submits pending scrobbles to Last.fm
+// processPendingScrobbles submits pending scrobbles to each backend. Every
+// backend works through its own deliveries, so one that is down or
+// rejecting scrobbles does not hold up the others.
 func (d *Daemon) processPendingScrobbles() {
+	for _, b := range d.backends {
+		d.processBackend(b)
+	}
+}
+
+// processBackend submits the scrobbles a backend is owed
+func (d *Daemon) processBackend(backend scrobbler.Backend) {
 	ctx := context.Background()
-	pending, err := d.queue.GetPending(ctx, 50) // Last.fm allows batch of 50
+	deliveries := d.queue.For(backend.Name())
+	logger := d.logger.With().Str("backend", backend.Name()).Logger()
+
+	pending, err := deliveries.GetPending(ctx, scrobbler.MaxBatchSize)
 	if err != nil {
-		d.logger.Error().Err(err).Msg("Failed to get pending scrobbles")
+		logger.Error().Err(err).Msg("Failed to get pending scrobbles")
 		return
 	}
 
@@ -318,78 +526,185 @@ func (d *Daemon) processPendingScrobbles() {
 		return
 	}
 
-	d.logger.Info().Int("count", len(pending)).Msg("Processing pending scrobbles")
+	logger.Info().Int("count", len(pending)).Msg("Processing pending scrobbles")
+
+	// Convert QueuedScrobble to Scrobble
+	scrobbles := make([]scrobbler.Scrobble, len(pending))
+	for i, qs := range pending {
+		scrobbles[i] = scrobbler.Scrobble{
+			Artist:    qs.Artist,
+			Track:     qs.TrackName,
+			Album:     qs.Album,
+			Timestamp: qs.Timestamp,
+			Duration:  qs.Duration,
+		}
+	}
 
-	// Submit in batch if more than one
-	if len(pending) == 1 {
-		queuedScrobble := pending[0]
-		err := d.scrobble.ScrobbleTrack(
-			ctx,
-			queuedScrobble.Artist,
-			queuedScrobble.TrackName,
-			queuedScrobble.Album,
-			queuedScrobble.Timestamp,
-			queuedScrobble.Duration,
-		)
+	results, err := d.submit(ctx, backend, scrobbles)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Int("count", len(pending)).
+			Msg("Batch scrobble failed")
+
+		// Unless the service is to blame, one bad scrobble may have failed
+		// the batch. Resubmit one at a time so it can't hold back the others.
+		if !scrobbler.IsTransientError(err) && len(pending) > 1 {
+			d.submitIndividually(ctx, backend, deliveries, pending, scrobbles)
+			return
+		}
 
+		for _, s := range pending {
+			d.recordError(ctx, deliveries, s, err)
+		}
+		return
+	}
+
+	if len(results) != len(pending) {
+		err := fmt.Errorf("%s returned %d results for %d scrobbles", backend.Name(), len(results), len(pending))
+		logger.Warn().Err(err).Msg("Batch scrobble failed")
+		for _, s := range pending {
+			d.recordError(ctx, deliveries, s, err)
+		}
+		return
+	}
+
+	d.recordResults(ctx, deliveries, pending, results)
+}
+
+// submitIndividually submits each scrobble in its own request, isolating
+// scrobbles that fail on their own from the rest of the batch
+func (d *Daemon) submitIndividually(ctx context.Context, backend scrobbler.Backend, deliveries *scrobbler.Deliveries, pending []scrobbler.QueuedScrobble, scrobbles []scrobbler.Scrobble) {
+	for i, s := range pending {
+		results, err := d.submit(ctx, backend, scrobbles[i:i+1])
+		if err == nil && len(results) != 1 {
+			err = fmt.Errorf("%s returned %d results for 1 scrobble", backend.Name(), len(results))
+		}
 		if err != nil {
-			d.logger.Warn().
-				Err(err).
-				Int64("id", queuedScrobble.ID).
-				Str("track", queuedScrobble.TrackName).
-				Msg("Failed to scrobble")
-			if markErr := d.queue.MarkError(ctx, queuedScrobble.ID, err.Error()); markErr != nil {
-				d.logger.Error().Err(markErr).Msg("Failed to mark scrobble error")
-			}
-		} else {
-			d.logger.Info().
-				Str("track", queuedScrobble.TrackName).
-				Str("artist", queuedScrobble.Artist).
-				Msg("Scrobbled successfully")
-			if markErr := d.queue.MarkScrobbled(ctx, queuedScrobble.ID); markErr != nil {
-				d.logger.Error().Err(markErr).Msg("Failed to mark scrobble as completed")
-			}
+			d.recordError(ctx, deliveries, s, err)
+			continue
 		}
-	} else {
-		// Batch scrobble - convert QueuedScrobble to Scrobble
-		scrobbles := make([]scrobbler.Scrobble, len(pending))
-		for i, qs := range pending {
-			scrobbles[i] = scrobbler.Scrobble{
-				Artist:    qs.Artist,
-				Track:     qs.TrackName,
-				Album:     qs.Album,
-				Timestamp: qs.Timestamp,
-				Duration:  qs.Duration,
-			}
+		d.recordResults(ctx, deliveries, pending[i:i+1], results)
+	}
+}
+
+// submit sends scrobbles to a backend in one request, giving up after
+// submitTimeout
+func (d *Daemon) submit(ctx context.Context, backend scrobbler.Backend, scrobbles []scrobbler.Scrobble) ([]scrobbler.ScrobbleResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, submitTimeout)
+	defer cancel()
+
+	start := time.Now()
+	results, err := backend.ScrobbleBatch(ctx, scrobbles)
+	d.metrics.observeBackend(backend.Name(), start)
+	return results, err
+}
+
+// recordError stores a failed submission to the backend of deliveries.
+// Permanent errors move the delivery straight to the failed state; others
+// are retried with backoff, and only those that aren't transient count
+// toward the retry limit.
+func (d *Daemon) recordError(ctx context.Context, deliveries *scrobbler.Deliveries, s scrobbler.QueuedScrobble, err error) {
+	if scrobbler.IsPermanentError(err) {
+		d.logger.Warn().
+			Str("backend", deliveries.Backend()).
+			Err(err).
+			Int64("id", s.ID).
+			Str("track", s.TrackName).
+			Str("artist", s.Artist).
+			Msg("Scrobble rejected permanently")
+		if markErr := deliveries.MarkFailed(ctx, s.ID, err.Error()); markErr != nil {
+			d.logger.Error().Err(markErr).Int64("id", s.ID).Msg("Failed to mark scrobble as failed")
 		}
+		d.metrics.scrobblesFailed.With(deliveries.Backend()).Inc()
+		d.bus.Publish(events.ScrobbleFailed{Scrobble: queuedEvent(s), Backend: deliveries.Backend(), Error: err.Error()})
+		return
+	}
 
-		if err := d.scrobble.ScrobbleBatch(ctx, scrobbles); err != nil {
-			d.logger.Warn().
-				Err(err).
-				Int("count", len(pending)).
-				Msg("Batch scrobble failed")
-
-			// Mark all as error
-			for _, s := range pending {
-				if markErr := d.queue.MarkError(ctx, s.ID, err.Error()); markErr != nil {
-					d.logger.Error().Err(markErr).Int64("id", s.ID).Msg("Failed to mark scrobble error")
-				}
-			}
-		} else {
-			d.logger.Info().
-				Int("count", len(pending)).
-				Msg("Batch scrobbled successfully")
+	mark := deliveries.MarkError
+	if scrobbler.IsTransientError(err) {
+		mark = deliveries.MarkTransientError
+	}
+	if markErr := mark(ctx, s.ID, err.Error()); markErr != nil {
+		d.logger.Error().Err(markErr).Int64("id", s.ID).Msg("Failed to mark scrobble error")
+	}
+	d.metrics.scrobbleRetries.With(deliveries.Backend()).Inc()
+	d.bus.Publish(events.ScrobbleFailed{Scrobble: queuedEvent(s), Backend: deliveries.Backend(), Error: err.Error(), Retry: true})
+}
 
-			// Mark all as scrobbled
-			ids := make([]int64, len(pending))
-			for i, s := range pending {
-				ids[i] = s.ID
+// recordResults stores the backend's per-scrobble verdicts in its
+// deliveries. Accepted scrobbles are marked scrobbled, ignored ones are
+// stored with their ignore code, and transient rejections and scrobbles
+// that failed on their own are recorded as errors.
+func (d *Daemon) recordResults(ctx context.Context, deliveries *scrobbler.Deliveries, pending []scrobbler.QueuedScrobble, results []scrobbler.ScrobbleResult) {
+	var accepted []scrobbler.QueuedScrobble
+	for i, s := range pending {
+		result := results[i]
+
+		switch {
+		case result.Err != nil:
+			d.recordError(ctx, deliveries, s, result.Err)
+		case result.Accepted:
+			accepted = append(accepted, s)
+		case result.Retryable():
+			d.logger.Warn().
+				Str("backend", deliveries.Backend()).
+				Int64("id", s.ID).
+				Str("track", s.TrackName).
+				Int("code", result.IgnoredCode).
+				Str("reason", result.IgnoredMessage).
+				Msg("Scrobble deferred by backend")
+			// The daily limit is the account's, not the scrobble's
+			if markErr := deliveries.MarkTransientError(ctx, s.ID, result.IgnoredMessage); markErr != nil {
+				d.logger.Error().Err(markErr).Int64("id", s.ID).Msg("Failed to mark scrobble error")
 			}
-			if markErr := d.queue.MarkScrobbledBatch(ctx, ids); markErr != nil {
-				d.logger.Error().Err(markErr).Msg("Failed to mark batch as scrobbled")
+			d.metrics.scrobbleRetries.With(deliveries.Backend()).Inc()
+			d.bus.Publish(events.ScrobbleFailed{Scrobble: queuedEvent(s), Backend: deliveries.Backend(), Error: result.IgnoredMessage, Retry: true})
+		default:
+			d.logger.Warn().
+				Str("backend", deliveries.Backend()).
+				Int64("id", s.ID).
+				Str("track", s.TrackName).
+				Str("artist", s.Artist).
+				Int("code", result.IgnoredCode).
+				Str("reason", result.IgnoredMessage).
+				Msg("Scrobble ignored by backend")
+			if markErr := deliveries.MarkIgnored(ctx, s.ID, result.IgnoredCode, result.IgnoredMessage); markErr != nil {
+				d.logger.Error().Err(markErr).Int64("id", s.ID).Msg("Failed to mark scrobble as ignored")
 			}
+			d.metrics.scrobblesIgnored.With(deliveries.Backend()).Inc()
+			d.bus.Publish(events.ScrobbleIgnored{
+				Scrobble: queuedEvent(s),
+				Backend:  deliveries.Backend(),
+				Code:     result.IgnoredCode,
+				Message:  result.IgnoredMessage,
+			})
 		}
 	}
+
+	if len(accepted) == 0 {
+		return
+	}
+
+	d.logger.Info().
+		Str("backend", deliveries.Backend()).
+		Int("accepted", len(accepted)).
+		Int("total", len(pending)).
+		Msg("Scrobbled successfully")
+
+	ids := make([]int64, len(accepted))
+	for i, s := range accepted {
+		ids[i] = s.ID
+	}
+	if markErr := deliveries.MarkScrobbledBatch(ctx, ids); markErr != nil {
+		d.logger.Error().Err(markErr).Msg("Failed to mark batch as scrobbled")
+		return
+	}
+
+	for _, s := range accepted {
+		d.metrics.scrobblesSent.With(deliveries.Backend()).Inc()
+		d.bus.Publish(events.ScrobbleSubmitted{Scrobble: queuedEvent(s), Backend: deliveries.Backend()})
+	}
 }
 
 // Shutdown gracefully shuts down the daemon
@@ -408,6 +723,13 @@ func (d *Daemon) Shutdown() error {
 		d.logger.Warn().Err(err).Msg("Failed to cleanup queue")
 	}
 
+	// Close history
+	if d.history != nil {
+		if err := d.history.Close(); err != nil {
+			d.logger.Warn().Err(err).Msg("Failed to close history")
+		}
+	}
+
 	// Close queue
 	if err := d.queue.Close(); err != nil {
 		return fmt.Errorf("failed to close queue: %w", err)
@@ -416,18 +738,6 @@ func (d *Daemon) Shutdown() error {
 	return nil
 }
 
-// EnableTUI creates and returns a channel for the TUI to receive track updates
-func (d *Daemon) EnableTUI() <-chan TrackUpdate {
-	d.tuiUpdates = make(chan TrackUpdate, 10)
-	return d.tuiUpdates
-}
-
-// EnableDiscord creates and returns a channel for Discord Rich Presence updates
-func (d *Daemon) EnableDiscord() <-chan discord.TrackUpdate {
-	d.discordUpdates = make(chan discord.TrackUpdate, 10)
-	return d.discordUpdates
-}
-
 // GetState returns the current track state
 func (d *Daemon) GetState() TrackState {
 	return d.state.GetState()
@@ -438,12 +748,13 @@ func (d *Daemon) GetPlayedDuration() time.Duration {
 	return d.state.GetPlayedDuration()
 }
 
-// GetPendingCount returns the number of pending scrobbles
+// GetPendingCount returns the number of pending scrobbles, including those
+// waiting to be retried
 func (d *Daemon) GetPendingCount() int {
 	ctx := context.Background()
-	pending, err := d.queue.GetPending(ctx, 1000)
+	count, err := d.queue.Count(ctx, false)
 	if err != nil {
 		return 0
 	}
-	return len(pending)
+	return count
 }
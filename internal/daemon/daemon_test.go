@@ -0,0 +1,538 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/events"
+	"github.com/jfmyers9/scribbles/internal/history"
+	"github.com/jfmyers9/scribbles/internal/music"
+	"github.com/jfmyers9/scribbles/internal/scrobbler"
+	"github.com/jfmyers9/scribbles/pkg/lastfm"
+	"github.com/rs/zerolog"
+)
+
+func newTestDaemon(t *testing.T) *Daemon {
+	t.Helper()
+	queue, err := scrobbler.NewQueue(":memory:")
+	if err != nil {
+		t.Fatalf("NewQueue: %v", err)
+	}
+	t.Cleanup(func() { _ = queue.Close() })
+	d := &Daemon{
+		queue:  queue,
+		logger: zerolog.Nop(),
+	}
+	d.metrics = newDaemonMetrics(d)
+	return d
+}
+
+func TestRecordResults_PerScrobbleOutcome(t *testing.T) {
+	d := newTestDaemon(t)
+	ctx := context.Background()
+
+	for i, track := range []string{"Accepted", "Too Old", "Rate Limited"} {
+		_, err := d.queue.Add(ctx, scrobbler.Scrobble{
+			Artist:    "Artist",
+			Track:     track,
+			Duration:  3 * time.Minute,
+			Timestamp: time.Now().Add(time.Duration(i) * time.Minute),
+		})
+		if err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	pending, err := d.queue.GetPending(ctx, 50)
+	if err != nil {
+		t.Fatalf("GetPending: %v", err)
+	}
+
+	d.recordResults(ctx, d.queue.For(scrobbler.DefaultBackend), pending, []scrobbler.ScrobbleResult{
+		{Accepted: true},
+		{IgnoredCode: lastfm.IgnoredTimestampTooOld, IgnoredMessage: "Timestamp too old"},
+		{IgnoredCode: lastfm.IgnoredDailyLimitExceeded, IgnoredMessage: "Daily scrobble limit exceeded"},
+	})
+
+	all, err := d.queue.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	byTrack := make(map[string]scrobbler.QueuedScrobble)
+	for _, s := range all {
+		byTrack[s.TrackName] = s
+	}
+
+	if s := byTrack["Accepted"]; !s.Scrobbled || s.Ignored {
+		t.Errorf("expected accepted scrobble to be marked scrobbled, got %+v", s)
+	}
+	if s := byTrack["Too Old"]; s.Scrobbled || !s.Ignored || s.IgnoredCode != lastfm.IgnoredTimestampTooOld {
+		t.Errorf("expected too-old scrobble to be stored as ignored, got %+v", s)
+	}
+	if s := byTrack["Rate Limited"]; s.Scrobbled || s.Ignored || s.Error == "" {
+		t.Errorf("expected rate-limited scrobble to stay pending with an error, got %+v", s)
+	}
+
+	count, err := d.queue.Count(ctx, false)
+	if err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected only the rate-limited scrobble to remain pending, got %d", count)
+	}
+}
+
+func TestRecordResults_PerScrobbleErrors(t *testing.T) {
+	d := newTestDaemon(t)
+	ctx := context.Background()
+
+	for i, track := range []string{"Delivered", "Rejected", "Unsent"} {
+		if _, err := d.queue.Add(ctx, scrobbler.Scrobble{
+			Artist:    "Artist",
+			Track:     track,
+			Duration:  3 * time.Minute,
+			Timestamp: time.Now().Add(time.Duration(i) * time.Minute),
+		}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	pending, err := d.queue.GetPending(ctx, 50)
+	if err != nil {
+		t.Fatalf("GetPending: %v", err)
+	}
+
+	d.recordResults(ctx, d.queue.For(scrobbler.DefaultBackend), pending, []scrobbler.ScrobbleResult{
+		{Accepted: true},
+		{Err: &scrobbler.WebhookError{StatusCode: 422}},
+		{Err: &scrobbler.WebhookError{StatusCode: 503}},
+	})
+
+	all, err := d.queue.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	want := map[string]scrobbler.Status{
+		"Delivered": scrobbler.StatusScrobbled,
+		"Rejected":  scrobbler.StatusFailed,
+		"Unsent":    scrobbler.StatusPending,
+	}
+	for _, s := range all {
+		if s.Status() != want[s.TrackName] {
+			t.Errorf("%s: expected %s, got %s", s.TrackName, want[s.TrackName], s.Status())
+		}
+	}
+}
+
+func TestRecordError_PermanentSkipsRetries(t *testing.T) {
+	d := newTestDaemon(t)
+	ctx := context.Background()
+
+	for _, track := range []string{"Offline", "Invalid"} {
+		if _, err := d.queue.Add(ctx, scrobbler.Scrobble{
+			Artist:    "Artist",
+			Track:     track,
+			Duration:  3 * time.Minute,
+			Timestamp: time.Now(),
+		}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	pending, err := d.queue.GetPending(ctx, 50)
+	if err != nil {
+		t.Fatalf("GetPending: %v", err)
+	}
+	for _, s := range pending {
+		code := lastfm.ErrCodeServiceOffline
+		if s.TrackName == "Invalid" {
+			code = lastfm.ErrCodeInvalidParameters
+		}
+		d.recordError(ctx, d.queue.For(scrobbler.DefaultBackend), s, fmt.Errorf("failed to scrobble batch: %w", &lastfm.Error{Code: code}))
+	}
+
+	all, err := d.queue.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	for _, s := range all {
+		switch s.TrackName {
+		case "Offline":
+			if s.Failed || s.Attempts != 1 || s.NextAttemptAt.IsZero() {
+				t.Errorf("expected temporary error to be retried later, got %+v", s)
+			}
+		case "Invalid":
+			if !s.Failed {
+				t.Errorf("expected permanent error to move scrobble to failed, got %+v", s)
+			}
+		}
+	}
+}
+
+// fakeBackend is a scrobbling backend that rejects one track permanently
+// and accepts everything else, unless it is down
+type fakeBackend struct {
+	name    string
+	reject  string // Track name that fails with invalid parameters
+	down    error  // Returned for every batch when set
+	short   bool   // Drops the last result of every batch
+	batches [][]scrobbler.Scrobble
+}
+
+func (f *fakeBackend) Name() string { return f.name }
+
+func (f *fakeBackend) UpdateNowPlaying(ctx context.Context, artist, track, album string, duration time.Duration) error {
+	return nil
+}
+
+func (f *fakeBackend) ScrobbleTrack(ctx context.Context, artist, track, album string, timestamp time.Time, duration time.Duration) error {
+	return nil
+}
+
+func (f *fakeBackend) ScrobbleBatch(ctx context.Context, scrobbles []scrobbler.Scrobble) ([]scrobbler.ScrobbleResult, error) {
+	f.batches = append(f.batches, scrobbles)
+	if f.down != nil {
+		return nil, f.down
+	}
+	results := make([]scrobbler.ScrobbleResult, len(scrobbles))
+	for i, s := range scrobbles {
+		if s.Track == f.reject {
+			return nil, &lastfm.Error{Code: lastfm.ErrCodeInvalidParameters, Message: "Invalid parameters"}
+		}
+		results[i].Accepted = true
+	}
+	if f.short {
+		results = results[:len(results)-1]
+	}
+	return results, nil
+}
+
+func TestProcessPendingScrobbles_IsolatesPermanentFailures(t *testing.T) {
+	d := newTestDaemon(t)
+	backend := &fakeBackend{name: "fake", reject: "Broken"}
+	d.backends = []scrobbler.Backend{backend}
+	ctx := context.Background()
+	if err := d.queue.SetBackends(ctx, []string{backend.name}); err != nil {
+		t.Fatalf("SetBackends: %v", err)
+	}
+
+	for i, track := range []string{"First", "Broken", "Third"} {
+		if _, err := d.queue.Add(ctx, scrobbler.Scrobble{
+			Artist:    "Artist",
+			Track:     track,
+			Duration:  3 * time.Minute,
+			Timestamp: time.Now().Add(time.Duration(i) * time.Minute),
+		}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	d.processPendingScrobbles()
+
+	// One batch, then each scrobble on its own
+	if len(backend.batches) != 4 {
+		t.Fatalf("expected 4 submissions, got %d", len(backend.batches))
+	}
+
+	all, err := d.queue.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	for _, s := range all {
+		wantStatus := scrobbler.StatusScrobbled
+		if s.TrackName == "Broken" {
+			wantStatus = scrobbler.StatusFailed
+		}
+		if s.Status() != wantStatus {
+			t.Errorf("%s: expected %s, got %s", s.TrackName, wantStatus, s.Status())
+		}
+	}
+}
+
+func TestProcessPendingScrobbles_OutageDoesNotFailQueue(t *testing.T) {
+	d := newTestDaemon(t)
+	backend := &fakeBackend{name: "lastfm", down: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	d.backends = []scrobbler.Backend{backend}
+	ctx := context.Background()
+	if err := d.queue.SetBackends(ctx, []string{backend.name}); err != nil {
+		t.Fatalf("SetBackends: %v", err)
+	}
+	// Retry at once, so each round is another attempt
+	d.queue.SetRetryPolicy(scrobbler.RetryPolicy{MaxAttempts: 3})
+
+	for i, track := range []string{"First", "Second"} {
+		if _, err := d.queue.Add(ctx, scrobbler.Scrobble{
+			Artist:    "Artist",
+			Track:     track,
+			Duration:  3 * time.Minute,
+			Timestamp: time.Now().Add(time.Duration(i) * time.Minute),
+		}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	for range 10 {
+		d.processPendingScrobbles()
+	}
+	if len(backend.batches) != 10 {
+		t.Fatalf("expected every round to retry, got %d submissions", len(backend.batches))
+	}
+
+	all, err := d.queue.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	for _, s := range all {
+		if s.Status() != scrobbler.StatusPending || s.Attempts != 10 {
+			t.Errorf("%s: expected pending after 10 attempts, got %s after %d", s.TrackName, s.Status(), s.Attempts)
+		}
+	}
+}
+
+func TestProcessPendingScrobbles_UnknownErrorsFailScrobbles(t *testing.T) {
+	// A Last.fm server answering with a bare client error
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name    string
+		backend scrobbler.Backend
+	}{
+		{"untyped error", &fakeBackend{name: "lastfm", down: errors.New("unexpected response")}},
+		{"client error", scrobbler.NewForEndpoint(scrobbler.Endpoint{APIURL: srv.URL}, "key", "secret", "session")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDaemon(t)
+			d.backends = []scrobbler.Backend{tt.backend}
+			ctx := context.Background()
+			if err := d.queue.SetBackends(ctx, []string{"lastfm"}); err != nil {
+				t.Fatalf("SetBackends: %v", err)
+			}
+			// Retry at once, so each round is another attempt
+			d.queue.SetRetryPolicy(scrobbler.RetryPolicy{MaxAttempts: 3})
+
+			if _, err := d.queue.Add(ctx, scrobbler.Scrobble{
+				Artist:    "Artist",
+				Track:     "Track",
+				Duration:  3 * time.Minute,
+				Timestamp: time.Now(),
+			}); err != nil {
+				t.Fatalf("Add: %v", err)
+			}
+
+			for range 3 {
+				d.processPendingScrobbles()
+			}
+
+			all, err := d.queue.GetAll(ctx)
+			if err != nil {
+				t.Fatalf("GetAll: %v", err)
+			}
+			if s := all[0]; s.Status() != scrobbler.StatusFailed || s.Attempts != 3 {
+				t.Errorf("expected failed after 3 attempts, got %s after %d", s.Status(), s.Attempts)
+			}
+		})
+	}
+}
+
+func TestProcessPendingScrobbles_MissingResults(t *testing.T) {
+	d := newTestDaemon(t)
+	backend := &fakeBackend{name: "fake", short: true}
+	d.backends = []scrobbler.Backend{backend}
+	ctx := context.Background()
+	if err := d.queue.SetBackends(ctx, []string{backend.name}); err != nil {
+		t.Fatalf("SetBackends: %v", err)
+	}
+
+	for i, track := range []string{"First", "Second"} {
+		if _, err := d.queue.Add(ctx, scrobbler.Scrobble{
+			Artist:    "Artist",
+			Track:     track,
+			Duration:  3 * time.Minute,
+			Timestamp: time.Now().Add(time.Duration(i) * time.Minute),
+		}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	d.processPendingScrobbles()
+
+	all, err := d.queue.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	for _, s := range all {
+		if s.Status() != scrobbler.StatusPending || s.Attempts != 1 || s.Error == "" {
+			t.Errorf("%s: expected a counted error, got %s after %d attempts (%q)", s.TrackName, s.Status(), s.Attempts, s.Error)
+		}
+	}
+}
+
+func TestProcessPendingScrobbles_DeliversToEachBackend(t *testing.T) {
+	d := newTestDaemon(t)
+	lastFM := &fakeBackend{name: "lastfm"}
+	listenBrainz := &fakeBackend{
+		name: "listenbrainz",
+		down: &scrobbler.ListenBrainzError{StatusCode: 503, Message: "Service Unavailable"},
+	}
+	d.backends = []scrobbler.Backend{lastFM, listenBrainz}
+	ctx := context.Background()
+	if err := d.queue.SetBackends(ctx, []string{"lastfm", "listenbrainz"}); err != nil {
+		t.Fatalf("SetBackends: %v", err)
+	}
+
+	id, err := d.queue.Add(ctx, scrobbler.Scrobble{
+		Artist:    "Artist",
+		Track:     "Track",
+		Duration:  3 * time.Minute,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	d.processPendingScrobbles()
+
+	s, err := d.queue.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if s.Status() != scrobbler.StatusPending {
+		t.Errorf("expected scrobble to stay pending, got %s", s.Status())
+	}
+	if owed := s.Owed(); len(owed) != 1 || owed[0] != "listenbrainz" {
+		t.Errorf("expected only listenbrainz to be owed, got %v", owed)
+	}
+
+	// Last.fm is not sent the scrobble again while ListenBrainz backs off
+	listenBrainz.down = nil
+	d.processPendingScrobbles()
+	if len(lastFM.batches) != 1 {
+		t.Errorf("expected 1 submission to lastfm, got %d", len(lastFM.batches))
+	}
+	if len(listenBrainz.batches) != 1 {
+		t.Errorf("expected listenbrainz to wait out its backoff, got %d submissions", len(listenBrainz.batches))
+	}
+
+	// Once due, only ListenBrainz is retried
+	if err := d.queue.Retry(ctx, id); err != nil {
+		t.Fatalf("Retry: %v", err)
+	}
+	d.processPendingScrobbles()
+	if len(lastFM.batches) != 1 || len(listenBrainz.batches) != 2 {
+		t.Errorf("expected only listenbrainz to be retried, got %d and %d submissions",
+			len(lastFM.batches), len(listenBrainz.batches))
+	}
+
+	s, err = d.queue.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !s.Scrobbled {
+		t.Errorf("expected scrobble to be delivered everywhere, got %+v", s)
+	}
+}
+
+func TestRecordPlay_AddsToHistory(t *testing.T) {
+	d := newTestDaemon(t)
+	store, err := history.NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	d.history = store
+
+	started := time.Now().Add(-time.Minute).Truncate(time.Second)
+	d.recordPlay(TrackState{
+		Track:         &music.Track{Name: "Believe", Artist: "Cher", Album: "Believe", Duration: 4 * time.Minute},
+		Source:        "apple-music",
+		StartTime:     started.Add(30 * time.Second),
+		PlayStartedAt: started,
+	}, 45*time.Second)
+	d.recordPlay(TrackState{Track: &music.Track{Name: "Nothing", Artist: "Nobody"}}, 0)
+
+	plays, err := store.List(context.Background(), history.Filter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(plays) != 1 {
+		t.Fatalf("expected 1 play (zero-length plays are dropped), got %d", len(plays))
+	}
+
+	p := plays[0]
+	if p.Track != "Believe" || p.Played != 45*time.Second || p.Source != "apple-music" {
+		t.Errorf("unexpected play: %+v", p)
+	}
+	if !p.StartedAt.Equal(started) {
+		t.Errorf("expected play to start at %v (not the resume time), got %v", started, p.StartedAt)
+	}
+	if !p.Skipped || p.Scrobbled {
+		t.Errorf("expected an unscrobbled skip, got %+v", p)
+	}
+}
+
+func TestHandleTrackUpdate_PublishesTrackEvents(t *testing.T) {
+	d := newControlTestDaemon(t)
+	sub := d.Subscribe("test", 10)
+	defer sub.Close()
+
+	track := music.Track{Name: "Song", Artist: "Artist", Duration: 3 * time.Minute, State: music.StatePlaying}
+	paused := track
+	paused.State = music.StatePaused
+
+	for _, update := range []*music.Track{&track, &track, &paused, &paused, &track, nil} {
+		if err := d.handleTrackUpdate("apple-music", update); err != nil {
+			t.Fatalf("handleTrackUpdate: %v", err)
+		}
+	}
+	sub.Close()
+
+	var kinds []string
+	for e := range sub.Events() {
+		kinds = append(kinds, e.Kind())
+	}
+	want := []string{
+		events.KindTrackStarted,
+		events.KindTrackPaused,
+		events.KindTrackResumed,
+		events.KindTrackSkipped,
+		events.KindTrackStopped,
+	}
+	if fmt.Sprint(kinds) != fmt.Sprint(want) {
+		t.Errorf("expected events %v, got %v", want, kinds)
+	}
+}
+
+func TestIsSkipped(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		played   time.Duration
+		want     bool
+	}{
+		{"skipped early", 4 * time.Minute, 30 * time.Second, true},
+		{"played past half", 4 * time.Minute, 2 * time.Minute, false},
+		{"long track past 4 minutes", 20 * time.Minute, 5 * time.Minute, false},
+		{"short track played through", 20 * time.Second, 20 * time.Second, false},
+		{"short track skipped", 20 * time.Second, 3 * time.Second, true},
+		{"unknown duration", 0, 10 * time.Second, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSkipped(tt.duration, tt.played); got != tt.want {
+				t.Errorf("isSkipped(%v, %v) = %v, want %v", tt.duration, tt.played, got, tt.want)
+			}
+		})
+	}
+}
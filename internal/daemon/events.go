@@ -0,0 +1,31 @@
+package daemon
+
+import (
+	"github.com/jfmyers9/scribbles/internal/events"
+	"github.com/jfmyers9/scribbles/internal/scrobbler"
+)
+
+// Subscribe registers a subscriber to the daemon's events. It receives
+// every event published after it subscribes, and misses events while
+// buffer events behind. Close the subscription when done.
+func (d *Daemon) Subscribe(name string, buffer int) *events.Subscription {
+	return d.bus.Subscribe(name, buffer)
+}
+
+// EventStats returns the subscribers to the daemon's events and how many
+// events each has dropped
+func (d *Daemon) EventStats() []events.SubscriberStats {
+	return d.bus.Stats()
+}
+
+// queuedEvent identifies a queued scrobble in events
+func queuedEvent(s scrobbler.QueuedScrobble) events.Scrobble {
+	return events.Scrobble{
+		ID:        s.ID,
+		Artist:    s.Artist,
+		Track:     s.TrackName,
+		Album:     s.Album,
+		Timestamp: s.Timestamp,
+		Source:    s.Source,
+	}
+}
@@ -0,0 +1,235 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/events"
+	"github.com/jfmyers9/scribbles/internal/history"
+	"github.com/jfmyers9/scribbles/internal/scrobbler"
+)
+
+// defaultListLimit is how many entries /queue and /history return without
+// a limit parameter
+const defaultListLimit = 50
+
+// sseKeepAlive is how often an idle /events stream sends a comment, so
+// proxies and clients don't time it out
+const sseKeepAlive = 30 * time.Second
+
+// sseBufferSize is how many events an /events client can fall behind
+// before it starts missing them
+const sseBufferSize = 32
+
+// serveHTTP serves the HTTP API on addr until ctx is cancelled
+func (d *Daemon) serveHTTP(ctx context.Context, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	srv := &http.Server{
+		Handler:           d.httpHandler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		// Requests, including /events streams, end with the daemon
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	stop := context.AfterFunc(ctx, func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	})
+	defer stop()
+
+	d.logger.Info().Str("address", ln.Addr().String()).Msg("HTTP API listening")
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// httpHandler routes the HTTP API:
+//
+//	GET /now      the daemon's Status, as served by the control socket
+//	GET /queue    queued scrobbles, newest first (status, artist, since, until, limit)
+//	GET /history  plays from the history, newest first (artist, album, since, until, limit)
+//	GET /events   a Server-Sent Events stream of the daemon's events
+//	GET /metrics  health metrics in the Prometheus text format
+func (d *Daemon) httpHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /now", d.handleNow)
+	mux.HandleFunc("GET /queue", d.handleQueue)
+	mux.HandleFunc("GET /history", d.handleHistory)
+	mux.HandleFunc("GET /events", d.handleEvents)
+	mux.Handle("GET /metrics", &d.metrics.registry)
+
+	origin := d.config.HTTPAllowOrigin
+	if origin == "" {
+		return mux
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (d *Daemon) handleNow(w http.ResponseWriter, r *http.Request) {
+	status, err := d.Status(r.Context())
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeHTTPJSON(w, status)
+}
+
+func (d *Daemon) handleQueue(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := scrobbler.Filter{Artist: query.Get("artist")}
+
+	var err error
+	if s := query.Get("status"); s != "" {
+		if filter.Status, err = scrobbler.ParseStatus(s); err != nil {
+			writeHTTPError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if filter.Since, filter.Until, filter.Limit, err = parseListParams(r); err != nil {
+		writeHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	scrobbles, err := d.queue.List(r.Context(), filter)
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if scrobbles == nil {
+		scrobbles = []scrobbler.QueuedScrobble{}
+	}
+	writeHTTPJSON(w, scrobbles)
+}
+
+func (d *Daemon) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if d.history == nil {
+		writeHTTPError(w, http.StatusNotFound, errors.New("listening history is disabled"))
+		return
+	}
+
+	query := r.URL.Query()
+	filter := history.Filter{
+		Artist: query.Get("artist"),
+		Album:  query.Get("album"),
+	}
+
+	var err error
+	if filter.Since, filter.Until, filter.Limit, err = parseListParams(r); err != nil {
+		writeHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	plays, err := d.history.List(r.Context(), filter)
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if plays == nil {
+		plays = []history.Play{}
+	}
+	writeHTTPJSON(w, plays)
+}
+
+// handleEvents streams events until the client disconnects. The stream
+// starts with a track_started event for the current track, if any.
+func (d *Daemon) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeHTTPError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+
+	sub := d.Subscribe("http", sseBufferSize)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	if track := d.state.GetState().Track; track != nil {
+		if err := writeSSE(w, events.TrackStarted{Track: *track}); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-sub.Events():
+			if err := writeSSE(w, e); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeSSE writes an event in Server-Sent Events format, named by its kind
+func writeSSE(w http.ResponseWriter, e events.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Kind(), data)
+	return err
+}
+
+// parseListParams parses the since, until (RFC 3339) and limit parameters
+// shared by /queue and /history
+func parseListParams(r *http.Request) (since, until time.Time, limit int, err error) {
+	query := r.URL.Query()
+
+	if s := query.Get("since"); s != "" {
+		if since, err = time.Parse(time.RFC3339, s); err != nil {
+			return since, until, limit, fmt.Errorf("invalid since %q (expected RFC 3339)", s)
+		}
+	}
+	if s := query.Get("until"); s != "" {
+		if until, err = time.Parse(time.RFC3339, s); err != nil {
+			return since, until, limit, fmt.Errorf("invalid until %q (expected RFC 3339)", s)
+		}
+	}
+
+	limit = defaultListLimit
+	if s := query.Get("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil || limit < 0 {
+			return since, until, limit, fmt.Errorf("invalid limit %q (0 = all)", s)
+		}
+	}
+
+	return since, until, limit, nil
+}
+
+func writeHTTPJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeHTTPError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,271 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/events"
+	"github.com/jfmyers9/scribbles/internal/history"
+	"github.com/jfmyers9/scribbles/internal/music"
+	"github.com/jfmyers9/scribbles/internal/scrobbler"
+)
+
+func newHTTPTestDaemon(t *testing.T) (*Daemon, *httptest.Server) {
+	t.Helper()
+	d := newControlTestDaemon(t, &fakeBackend{name: scrobbler.DefaultBackend})
+
+	store, err := history.NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	d.history = store
+
+	server := httptest.NewServer(d.httpHandler())
+	t.Cleanup(server.Close)
+	return d, server
+}
+
+// getJSON decodes the JSON response to a GET request and returns its status
+func getJSON(t *testing.T, url string, v any) int {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("GET %s: expected JSON, got %q", url, ct)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("GET %s: invalid JSON: %v", url, err)
+	}
+	return resp.StatusCode
+}
+
+func TestHTTP_Now(t *testing.T) {
+	d, server := newHTTPTestDaemon(t)
+	if err := d.state.SetTrack("apple-music", &music.Track{Name: "Song", Artist: "Artist", Duration: 3 * time.Minute, State: music.StatePlaying}); err != nil {
+		t.Fatalf("SetTrack: %v", err)
+	}
+
+	var status Status
+	if code := getJSON(t, server.URL+"/now", &status); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if status.State.Track == nil || status.State.Track.Name != "Song" || !status.WillScrobble {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+func TestHTTP_Queue(t *testing.T) {
+	d, server := newHTTPTestDaemon(t)
+	ctx := context.Background()
+
+	for _, track := range []string{"First", "Second"} {
+		if _, err := d.queue.Add(ctx, scrobbler.Scrobble{Artist: "Artist", Track: track, Timestamp: time.Now()}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	var entries []map[string]any
+	if code := getJSON(t, server.URL+"/queue?status=pending&limit=1", &entries); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if len(entries) != 1 || entries[0]["status"] != "pending" {
+		t.Errorf("expected one pending entry, got %v", entries)
+	}
+
+	var body map[string]string
+	for _, query := range []string{"status=lost", "limit=-1", "since=yesterday"} {
+		if code := getJSON(t, server.URL+"/queue?"+query, &body); code != http.StatusBadRequest || body["error"] == "" {
+			t.Errorf("%s: expected 400 with an error, got %d %v", query, code, body)
+		}
+	}
+}
+
+func TestHTTP_History(t *testing.T) {
+	d, server := newHTTPTestDaemon(t)
+
+	_, err := d.history.Add(context.Background(), history.Play{
+		Track:     "Believe",
+		Artist:    "Cher",
+		Duration:  4 * time.Minute,
+		Played:    4 * time.Minute,
+		StartedAt: time.Now().Add(-time.Hour),
+		Scrobbled: true,
+	})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	var plays []map[string]any
+	if code := getJSON(t, server.URL+"/history?artist=cher", &plays); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if len(plays) != 1 || plays[0]["track"] != "Believe" || plays[0]["played_seconds"] != float64(240) {
+		t.Errorf("unexpected plays: %v", plays)
+	}
+
+	d.history = nil
+	var body map[string]string
+	if code := getJSON(t, server.URL+"/history", &body); code != http.StatusNotFound {
+		t.Errorf("expected 404 with history disabled, got %d", code)
+	}
+}
+
+func TestHTTP_AllowOrigin(t *testing.T) {
+	d := newControlTestDaemon(t)
+	d.config.HTTPAllowOrigin = "null"
+	server := httptest.NewServer(d.httpHandler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/now")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	_ = resp.Body.Close()
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "null" {
+		t.Errorf("expected the configured origin, got %q", got)
+	}
+}
+
+func TestHTTP_Metrics(t *testing.T) {
+	d, server := newHTTPTestDaemon(t)
+	sub := d.Subscribe("test", 0) // Drops every event
+
+	d.metrics.observePoll(TrackUpdate{Took: 200 * time.Millisecond})
+	d.metrics.observePoll(TrackUpdate{Err: errors.New("Music is not running")})
+	track := &music.Track{Name: "Song", Artist: "Artist", Duration: 3 * time.Minute, State: music.StatePlaying}
+	if err := d.handleTrackUpdate("apple-music", track); err != nil {
+		t.Fatalf("handleTrackUpdate: %v", err)
+	}
+	if err := d.ForceScrobble(); err != nil {
+		t.Fatalf("ForceScrobble: %v", err)
+	}
+	pending, err := d.queue.GetPending(context.Background(), 0)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("GetPending: %v, %v", pending, err)
+	}
+	d.recordError(context.Background(), d.queue.For(scrobbler.DefaultBackend), pending[0], errors.New("connection refused"))
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading metrics: %v", err)
+	}
+
+	for _, want := range []string{
+		"scribbles_polls_total 2",
+		"scribbles_poll_errors_total 1",
+		`scribbles_poll_duration_seconds_bucket{le="0.25"} 2`,
+		"scribbles_track_changes_total 1",
+		"scribbles_scrobbles_queued_total 1",
+		`scribbles_scrobble_retries_total{backend="lastfm"} 1`,
+		`scribbles_backend_request_duration_seconds_count{backend="lastfm"} 1`,
+		"scribbles_queue_pending 1",
+		`scribbles_events_dropped_total{subscriber="test"} 4`,
+	} {
+		if !strings.Contains(string(body), want+"\n") {
+			t.Errorf("expected %q in metrics:\n%s", want, body)
+		}
+	}
+	sub.Close()
+}
+
+// readSSE reads the next event from a Server-Sent Events stream
+func readSSE(t *testing.T, r *bufio.Reader) (string, map[string]any) {
+	t.Helper()
+	var name string
+	var data map[string]any
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading events: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return name, data
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &data); err != nil {
+				t.Fatalf("invalid event data %q: %v", line, err)
+			}
+		}
+	}
+}
+
+func TestHTTP_Events(t *testing.T) {
+	d, server := newHTTPTestDaemon(t)
+	if err := d.state.SetTrack("apple-music", &music.Track{Name: "Earlier", Artist: "Artist", Duration: 3 * time.Minute, State: music.StatePlaying}); err != nil {
+		t.Fatalf("SetTrack: %v", err)
+	}
+
+	resp, err := http.Get(server.URL + "/events")
+	if err != nil {
+		t.Fatalf("GET /events: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %q", ct)
+	}
+	r := bufio.NewReader(resp.Body)
+
+	// The stream opens with the current track
+	if name, data := readSSE(t, r); name != events.KindTrackStarted || trackName(data) != "Earlier" {
+		t.Fatalf("expected the current track first, got %s %v", name, data)
+	}
+
+	track := &music.Track{Name: "Song", Artist: "Artist", Duration: 3 * time.Minute, State: music.StatePlaying}
+	if err := d.handleTrackUpdate("apple-music", track); err != nil {
+		t.Fatalf("handleTrackUpdate: %v", err)
+	}
+	if name, data := readSSE(t, r); name != events.KindTrackSkipped || trackName(data) != "Earlier" {
+		t.Fatalf("expected the earlier track to be skipped, got %s %v", name, data)
+	}
+	if name, data := readSSE(t, r); name != events.KindTrackStarted || trackName(data) != "Song" {
+		t.Fatalf("expected the new track, got %s %v", name, data)
+	}
+	if name, data := readSSE(t, r); name != events.KindNowPlayingUpdated || data["backend"] != scrobbler.DefaultBackend {
+		t.Fatalf("expected a now playing update, got %s %v", name, data)
+	}
+
+	if err := d.ForceScrobble(); err != nil {
+		t.Fatalf("ForceScrobble: %v", err)
+	}
+	if name, data := readSSE(t, r); name != events.KindScrobbleQueued || scrobbleTrack(data) != "Song" {
+		t.Fatalf("expected a queued scrobble, got %s %v", name, data)
+	}
+
+	d.processPendingScrobbles()
+	if name, data := readSSE(t, r); name != events.KindScrobbleSubmitted || data["backend"] != scrobbler.DefaultBackend {
+		t.Fatalf("expected a scrobble submitted to lastfm, got %s %v", name, data)
+	}
+}
+
+// trackName returns the track name of a track event's data
+func trackName(data map[string]any) any {
+	track, _ := data["track"].(map[string]any)
+	return track["Name"]
+}
+
+// scrobbleTrack returns the track of a scrobble event's data
+func scrobbleTrack(data map[string]any) any {
+	scrobble, _ := data["scrobble"].(map[string]any)
+	return scrobble["track"]
+}
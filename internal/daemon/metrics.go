@@ -0,0 +1,111 @@
+package daemon
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/metrics"
+)
+
+// daemonMetrics are the daemon's health metrics, served at /metrics by
+// the HTTP API. Counters are incremented where the daemon decides each
+// outcome rather than from the event bus, which drops events for
+// subscribers that fall behind.
+type daemonMetrics struct {
+	registry metrics.Registry
+
+	polls        *metrics.Counter
+	pollErrors   *metrics.Counter
+	pollDuration *metrics.Histogram
+	pushes       *metrics.Counter
+
+	trackChanges     *metrics.Counter
+	scrobblesQueued  *metrics.Counter
+	scrobblesSent    *metrics.CounterVec
+	scrobblesIgnored *metrics.CounterVec
+	scrobblesFailed  *metrics.CounterVec
+	scrobbleRetries  *metrics.CounterVec
+	backendDuration  *metrics.HistogramVec
+}
+
+// newDaemonMetrics registers the daemon's metrics. Queue and state
+// metrics are read from d when scraped.
+func newDaemonMetrics(d *Daemon) *daemonMetrics {
+	m := &daemonMetrics{}
+	r := &m.registry
+
+	m.polls = r.Counter("scribbles_polls_total", "Polls of the music player.")
+	m.pollErrors = r.Counter("scribbles_poll_errors_total", "Polls of the music player that failed.")
+	m.pollDuration = r.Histogram("scribbles_poll_duration_seconds",
+		"Time the music player took to report the current track (osascript on macOS).", metrics.DefaultBuckets)
+	m.pushes = r.Counter("scribbles_pushed_updates_total", "Track updates pushed by the music player, such as MPD, instead of polled.")
+
+	m.trackChanges = r.Counter("scribbles_track_changes_total", "Tracks that started playing.")
+	m.scrobblesQueued = r.Counter("scribbles_scrobbles_queued_total", "Plays added to the scrobble queue.")
+	m.scrobblesSent = r.CounterVec("scribbles_scrobbles_submitted_total", "Scrobbles accepted by a backend.", "backend")
+	m.scrobblesIgnored = r.CounterVec("scribbles_scrobbles_ignored_total", "Scrobbles a backend ignored for good.", "backend")
+	m.scrobblesFailed = r.CounterVec("scribbles_scrobbles_failed_total", "Scrobbles a backend rejected permanently.", "backend")
+	m.scrobbleRetries = r.CounterVec("scribbles_scrobble_retries_total",
+		"Scrobble submissions that failed and will be retried.", "backend")
+	m.backendDuration = r.HistogramVec("scribbles_backend_request_duration_seconds",
+		"Time taken by requests to scrobbling backends such as Last.fm.", "backend", metrics.DefaultBuckets)
+
+	// Export every backend from the start, so rates work before the first scrobble
+	for _, b := range d.backends {
+		for _, v := range []*metrics.CounterVec{m.scrobblesSent, m.scrobblesIgnored, m.scrobblesFailed, m.scrobbleRetries} {
+			v.With(b.Name())
+		}
+		m.backendDuration.With(b.Name())
+	}
+
+	r.GaugeFunc("scribbles_queue_pending", "Scrobbles waiting to be delivered, including those waiting to be retried.", func() float64 {
+		count, err := d.queue.Count(context.Background(), false)
+		if err != nil {
+			d.logger.Debug().Err(err).Msg("Failed to count pending scrobbles for metrics")
+			return math.NaN()
+		}
+		return float64(count)
+	})
+	r.GaugeFunc("scribbles_queue_oldest_pending_age_seconds", "Time since the oldest pending scrobble was queued, 0 if none.", func() float64 {
+		oldest, err := d.queue.OldestPending(context.Background())
+		if err != nil {
+			d.logger.Debug().Err(err).Msg("Failed to find oldest pending scrobble for metrics")
+			return math.NaN()
+		}
+		if oldest.IsZero() {
+			return 0
+		}
+		return time.Since(oldest).Seconds()
+	})
+	r.CounterFunc("scribbles_state_persists_total", "Writes of the state file.", func() float64 {
+		return float64(d.state.PersistCount())
+	})
+	r.CounterVecFunc("scribbles_events_dropped_total", "Events a subscriber missed because it fell behind.", "subscriber", func() map[string]float64 {
+		dropped := make(map[string]float64)
+		for _, s := range d.EventStats() {
+			dropped[s.Name] = float64(s.Dropped)
+		}
+		return dropped
+	})
+
+	return m
+}
+
+// observePoll records a poll of the music player, or an update it pushed
+func (m *daemonMetrics) observePoll(update TrackUpdate) {
+	if update.Pushed {
+		m.pushes.Inc()
+		return
+	}
+	m.polls.Inc()
+	if update.Err != nil {
+		m.pollErrors.Inc()
+	}
+	m.pollDuration.Observe(update.Took.Seconds())
+}
+
+// observeBackend records how long a request to a backend took
+func (m *daemonMetrics) observeBackend(backend string, start time.Time) {
+	m.backendDuration.With(backend).Observe(time.Since(start).Seconds())
+}
@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/jfmyers9/scribbles/internal/music"
@@ -10,29 +11,53 @@ import (
 
 // TrackUpdate represents an update from the music client
 type TrackUpdate struct {
-	Track *music.Track // Current track (nil if stopped/no track)
-	Err   error        // Error from music client
+	Source string        // Name of the source the update came from
+	Track  *music.Track  // Current track (nil if stopped/no track)
+	Err    error         // Error from music client
+	Took   time.Duration // How long the music client took to answer
+	Pushed bool          // Pushed by a music.Watcher rather than polled
 }
 
-// Poller polls the music client at regular intervals
+const (
+	// defaultResyncInterval is how often a client that pushes changes is
+	// still polled, since players don't push the position as it advances
+	defaultResyncInterval = 30 * time.Second
+
+	// defaultWatchRetry is how long to poll at the normal interval after
+	// watching fails, before watching again
+	defaultWatchRetry = 30 * time.Second
+)
+
+// Poller polls the music client at regular intervals, or follows the
+// changes it pushes if it is a music.Watcher
 type Poller struct {
-	client   music.Client
-	interval time.Duration
-	logger   zerolog.Logger
+	source         string
+	client         music.Client
+	interval       time.Duration
+	resyncInterval time.Duration // Safety poll while watching
+	watchRetry     time.Duration // Polling time after watching fails
+	logger         zerolog.Logger
 }
 
-// NewPoller creates a new Poller instance
-func NewPoller(client music.Client, interval time.Duration, logger zerolog.Logger) *Poller {
+// NewPoller creates a new Poller instance for the named source
+func NewPoller(source string, client music.Client, interval time.Duration, logger zerolog.Logger) *Poller {
 	return &Poller{
-		client:   client,
-		interval: interval,
-		logger:   logger.With().Str("component", "poller").Logger(),
+		source:         source,
+		client:         client,
+		interval:       interval,
+		resyncInterval: defaultResyncInterval,
+		watchRetry:     defaultWatchRetry,
+		logger:         logger.With().Str("component", "poller").Str("source", source).Logger(),
 	}
 }
 
 // Run starts the polling loop and sends updates to the provided channel
 // Blocks until context is cancelled
 func (p *Poller) Run(ctx context.Context, updates chan<- TrackUpdate) error {
+	if watcher, ok := p.client.(music.Watcher); ok {
+		return p.runWatcher(ctx, watcher, updates)
+	}
+
 	p.logger.Info().
 		Dur("interval", p.interval).
 		Msg("Starting poller")
@@ -54,14 +79,104 @@ func (p *Poller) Run(ctx context.Context, updates chan<- TrackUpdate) error {
 	}
 }
 
+// runWatcher sends the updates the client pushes, polling only every
+// resyncInterval to resync the position. While watching has failed, it
+// polls at the normal interval, and tries watching again after watchRetry.
+func (p *Poller) runWatcher(ctx context.Context, watcher music.Watcher, updates chan<- TrackUpdate) error {
+	p.logger.Info().
+		Dur("resync_interval", p.resyncInterval).
+		Msg("Starting watcher")
+
+	for {
+		err := p.watch(ctx, watcher, updates)
+		if ctx.Err() != nil {
+			p.logger.Info().Msg("Poller stopped")
+			return ctx.Err()
+		}
+		p.logger.Warn().
+			Err(err).
+			Dur("retry", p.watchRetry).
+			Msg("Watching music player failed, polling until retry")
+
+		if err := p.pollFor(ctx, p.watchRetry, updates); err != nil {
+			p.logger.Info().Msg("Poller stopped")
+			return err
+		}
+	}
+}
+
+// watch forwards pushed tracks until watching fails or ctx is cancelled
+func (p *Poller) watch(ctx context.Context, watcher music.Watcher, updates chan<- TrackUpdate) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tracks := make(chan *music.Track)
+	watchErr := make(chan error, 1)
+	go func() {
+		err := watcher.Watch(ctx, tracks)
+		if err == nil {
+			err = errors.New("watch ended")
+		}
+		watchErr <- err
+	}()
+
+	ticker := time.NewTicker(p.resyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-watchErr:
+			return err
+		case track := <-tracks:
+			select {
+			case updates <- TrackUpdate{Source: p.source, Track: track, Pushed: true}:
+				if track != nil {
+					p.logger.Debug().
+						Str("track", track.Name).
+						Str("artist", track.Artist).
+						Str("state", track.State.String()).
+						Msg("Watch update")
+				}
+			case <-ctx.Done():
+			}
+		case <-ticker.C:
+			p.poll(ctx, updates)
+		}
+	}
+}
+
+// pollFor polls at the normal interval for d, or until ctx is cancelled
+func (p *Poller) pollFor(ctx context.Context, d time.Duration, updates chan<- TrackUpdate) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.poll(ctx, updates)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		case <-ticker.C:
+			p.poll(ctx, updates)
+		}
+	}
+}
+
 // poll queries the music client and sends an update
 func (p *Poller) poll(ctx context.Context, updates chan<- TrackUpdate) {
+	start := time.Now()
 	track, err := p.client.GetCurrentTrack(ctx)
+	took := time.Since(start)
 	if err != nil {
 		p.logger.Debug().Err(err).Msg("Error getting current track")
 		// Send error update (non-blocking)
 		select {
-		case updates <- TrackUpdate{Err: err}:
+		case updates <- TrackUpdate{Source: p.source, Err: err, Took: took}:
 		case <-ctx.Done():
 		}
 		return
@@ -69,7 +184,7 @@ func (p *Poller) poll(ctx context.Context, updates chan<- TrackUpdate) {
 
 	// Send update (non-blocking)
 	select {
-	case updates <- TrackUpdate{Track: track}:
+	case updates <- TrackUpdate{Source: p.source, Track: track, Took: took}:
 		if track != nil {
 			p.logger.Debug().
 				Str("track", track.Name).
@@ -0,0 +1,154 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/music"
+	"github.com/rs/zerolog"
+)
+
+// fakeClient answers polls with a fixed track
+type fakeClient struct {
+	music.Client
+	track *music.Track
+	polls atomic.Int32
+}
+
+func (c *fakeClient) GetCurrentTrack(ctx context.Context) (*music.Track, error) {
+	c.polls.Add(1)
+	return c.track, nil
+}
+
+// fakeWatcher is a fakeClient that pushes the tracks sent on push. The
+// first failures calls to Watch fail.
+type fakeWatcher struct {
+	fakeClient
+	push     chan *music.Track
+	failures int32
+	watches  atomic.Int32
+}
+
+func (w *fakeWatcher) Watch(ctx context.Context, tracks chan<- *music.Track) error {
+	if w.watches.Add(1) <= w.failures {
+		return errors.New("connection refused")
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case track := <-w.push:
+			select {
+			case tracks <- track:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+func nextUpdate(t *testing.T, updates <-chan TrackUpdate) TrackUpdate {
+	t.Helper()
+	select {
+	case update := <-updates:
+		return update
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for an update")
+		return TrackUpdate{}
+	}
+}
+
+func TestPoller_FollowsWatcher(t *testing.T) {
+	watcher := &fakeWatcher{
+		fakeClient: fakeClient{track: &music.Track{Name: "Polled", State: music.StatePlaying}},
+		push:       make(chan *music.Track),
+	}
+	poller := NewPoller("mpd", watcher, time.Millisecond, zerolog.Nop())
+	poller.resyncInterval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan TrackUpdate)
+	done := make(chan error, 1)
+	go func() { done <- poller.Run(ctx, updates) }()
+
+	watcher.push <- &music.Track{Name: "Pushed", State: music.StatePlaying}
+	if update := nextUpdate(t, updates); !update.Pushed || update.Track.Name != "Pushed" {
+		t.Errorf("expected the pushed track, got %+v", update)
+	}
+	watcher.push <- nil
+	if update := nextUpdate(t, updates); !update.Pushed || update.Track != nil {
+		t.Errorf("expected a pushed stop, got %+v", update)
+	}
+	if polls := watcher.polls.Load(); polls != 0 {
+		t.Errorf("expected no polls while watching, got %d", polls)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestPoller_WatcherResyncPoll(t *testing.T) {
+	watcher := &fakeWatcher{
+		fakeClient: fakeClient{track: &music.Track{Name: "Polled", Position: time.Minute, State: music.StatePlaying}},
+		push:       make(chan *music.Track),
+	}
+	poller := NewPoller("mpd", watcher, time.Hour, zerolog.Nop())
+	poller.resyncInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan TrackUpdate)
+	go func() { _ = poller.Run(ctx, updates) }()
+
+	if update := nextUpdate(t, updates); update.Pushed || update.Track.Position != time.Minute {
+		t.Errorf("expected a resync poll, got %+v", update)
+	}
+}
+
+func TestPoller_FallsBackToPollingWhenWatchFails(t *testing.T) {
+	watcher := &fakeWatcher{
+		fakeClient: fakeClient{track: &music.Track{Name: "Polled", State: music.StatePlaying}},
+		push:       make(chan *music.Track),
+		failures:   1,
+	}
+	poller := NewPoller("mpd", watcher, 5*time.Millisecond, zerolog.Nop())
+	poller.resyncInterval = time.Hour
+	poller.watchRetry = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan TrackUpdate)
+	go func() { _ = poller.Run(ctx, updates) }()
+
+	// Polls at the normal interval after the first watch fails
+	for range 2 {
+		if update := nextUpdate(t, updates); update.Pushed || update.Track.Name != "Polled" {
+			t.Fatalf("expected a polled update, got %+v", update)
+		}
+	}
+
+	// Then watches again once the retry delay has passed
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case watcher.push <- &music.Track{Name: "Pushed", State: music.StatePlaying}:
+			for {
+				if update := nextUpdate(t, updates); update.Pushed {
+					if watches := watcher.watches.Load(); watches != 2 {
+						t.Errorf("expected 2 watches, got %d", watches)
+					}
+					return
+				}
+			}
+		case <-updates:
+		case <-deadline:
+			t.Fatal("timed out waiting to watch again")
+		}
+	}
+}
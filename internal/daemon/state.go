@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jfmyers9/scribbles/internal/music"
@@ -12,11 +13,14 @@ import (
 
 // TrackState represents the daemon's tracking state for the currently playing track
 type TrackState struct {
-	Track         *music.Track  // Currently playing track (nil if stopped)
-	StartTime     time.Time     // When playback started (or resumed)
-	Scrobbled     bool          // Whether this play has been scrobbled
-	PausedAt      time.Time     // When track was paused (zero if not paused)
-	TotalPlayTime time.Duration // Accumulated play time (excludes pauses)
+	Track           *music.Track  `json:"track,omitempty"`            // Currently playing track (nil if stopped)
+	Source          string        `json:"source,omitempty"`           // Music source playing the track
+	StartTime       time.Time     `json:"start_time"`                 // When playback started (or resumed)
+	Scrobbled       bool          `json:"scrobbled"`                  // Whether this play has been scrobbled
+	ScrobbleSkipped bool          `json:"scrobble_skipped,omitempty"` // Whether this play was excluded from scrobbling
+	PausedAt        time.Time     `json:"paused_at,omitempty"`        // When track was paused (zero if not paused)
+	TotalPlayTime   time.Duration `json:"total_play_time"`            // Accumulated play time (excludes pauses)
+	PlayStartedAt   time.Time     `json:"play_started_at,omitempty"`  // When this play began (unlike StartTime, not reset on resume)
 }
 
 // defaultPersistInterval is the minimum time between throttled disk writes.
@@ -30,15 +34,24 @@ type State struct {
 	dirty           bool          // Whether state has changed since last persist
 	lastPersist     time.Time     // Time of last successful persist
 	persistInterval time.Duration // Minimum interval between throttled persists
+	persists        atomic.Uint64 // Successful writes of the state file
+
+	// suspended holds, by source, plays set aside when another source took over
+	suspended map[string]TrackState
 }
 
 // persistedState is the JSON representation of state for disk storage
 type persistedState struct {
-	Track         *music.Track  `json:"track,omitempty"`
-	StartTime     time.Time     `json:"start_time"`
-	Scrobbled     bool          `json:"scrobbled"`
-	PausedAt      time.Time     `json:"paused_at,omitempty"`
-	TotalPlayTime time.Duration `json:"total_play_time"`
+	Track           *music.Track  `json:"track,omitempty"`
+	Source          string        `json:"source,omitempty"`
+	StartTime       time.Time     `json:"start_time"`
+	Scrobbled       bool          `json:"scrobbled"`
+	ScrobbleSkipped bool          `json:"scrobble_skipped,omitempty"`
+	PausedAt        time.Time     `json:"paused_at,omitempty"`
+	TotalPlayTime   time.Duration `json:"total_play_time"`
+	PlayStartedAt   time.Time     `json:"play_started_at,omitempty"`
+
+	Suspended map[string]TrackState `json:"suspended,omitempty"`
 }
 
 // NewState creates a new State instance
@@ -61,51 +74,50 @@ func NewState(filePath string) (*State, error) {
 	return s, nil
 }
 
-// SetTrack updates the current track and resets state
+// SetTrack updates the current track, playing on source, and resets state
 // This should be called when a new track starts playing
-func (s *State) SetTrack(track *music.Track) error {
+func (s *State) SetTrack(source string, track *music.Track) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	now := time.Now()
 	s.current = TrackState{
 		Track:         track,
-		StartTime:     time.Now(),
+		Source:        source,
+		StartTime:     now,
 		Scrobbled:     false,
 		TotalPlayTime: 0,
+		PlayStartedAt: now,
 	}
 
 	return s.persist()
 }
 
 // UpdatePosition updates the playback position based on current track state
-// Handles pause/resume by accumulating play time
+// Handles pause/resume by accumulating play time. New tracks must be set
+// with SetTrack, so a track other than the current one is ignored.
 func (s *State) UpdatePosition(track *music.Track) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// No current track - this is a new track
-	if s.current.Track == nil {
-		s.current = TrackState{
-			Track:         track,
-			StartTime:     time.Now(),
-			Scrobbled:     false,
-			TotalPlayTime: 0,
-		}
-		return s.persist()
+	if !s.updatePosition(track) {
+		return nil
 	}
+	return s.throttledPersist()
+}
 
-	// Track changed - reset state
-	if !isSameTrack(s.current.Track, track) {
-		s.current = TrackState{
-			Track:         track,
-			StartTime:     time.Now(),
-			Scrobbled:     false,
-			TotalPlayTime: 0,
-		}
-		return s.persist()
+// updatePosition applies track to the current play if it is the same track,
+// reporting whether it did. Must be called with lock held.
+func (s *State) updatePosition(track *music.Track) bool {
+	if s.current.Track == nil || !isSameTrack(s.current.Track, track) {
+		return false
 	}
 
-	// Same track - update state based on play state
+	// Same track - keep the latest position and play state, then update
+	// play time based on play state
+	if track.State != music.StateStopped {
+		s.current.Track = track
+	}
 	switch track.State {
 	case music.StatePlaying:
 		// If we were paused, resume and accumulate play time
@@ -125,8 +137,61 @@ func (s *State) UpdatePosition(track *music.Track) error {
 		// Track stopped - reset state
 		s.current = TrackState{}
 	}
+	return true
+}
 
-	return s.throttledPersist()
+// Suspend sets the current play aside when another source takes over. Its
+// play time stops counting until Resume continues it. The current state is
+// cleared either way.
+func (s *State) Suspend() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current.Track != nil && s.current.Source != "" {
+		play := s.current
+		now := time.Now()
+		play.TotalPlayTime = play.playedDuration()
+		play.StartTime = now
+		play.PausedAt = now
+		if s.suspended == nil {
+			s.suspended = make(map[string]TrackState)
+		}
+		s.suspended[play.Source] = play
+	}
+	s.current = TrackState{}
+	return s.persist()
+}
+
+// Resume continues the play suspended for source if it is still playing
+// track, reporting whether it did. The play keeps its play time and whether
+// it was scrobbled.
+func (s *State) Resume(source string, track *music.Track) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	play, ok := s.suspended[source]
+	if !ok || !isSameTrack(play.Track, track) || track.State == music.StateStopped {
+		return false, nil
+	}
+	delete(s.suspended, source)
+	s.current = play
+	s.updatePosition(track)
+	return true, s.persist()
+}
+
+// FinishSuspended ends the play suspended for source unless the source is
+// still playing its track, returning the play and how long it was played.
+// A nil track means the source is not playing anything.
+func (s *State) FinishSuspended(source string, track *music.Track) (TrackState, time.Duration, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	play, ok := s.suspended[source]
+	if !ok || (track != nil && track.State != music.StateStopped && isSameTrack(play.Track, track)) {
+		return TrackState{}, 0, false, nil
+	}
+	delete(s.suspended, source)
+	return play, play.playedDuration(), true, s.persist()
 }
 
 // MarkScrobbled marks the current track as scrobbled
@@ -138,6 +203,16 @@ func (s *State) MarkScrobbled() error {
 	return s.persist()
 }
 
+// SkipScrobble excludes the current play from scrobbling. A new track
+// starts a new play, which is scrobbled as usual.
+func (s *State) SkipScrobble() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.current.ScrobbleSkipped = true
+	return s.persist()
+}
+
 // GetState returns a copy of the current state
 func (s *State) GetState() TrackState {
 	s.mu.RLock()
@@ -153,18 +228,23 @@ func (s *State) GetPlayedDuration() time.Duration {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	return s.current.playedDuration()
+}
+
+// playedDuration returns the total time the track has been played
+func (ts TrackState) playedDuration() time.Duration {
 	// If currently paused, return accumulated time up to pause
-	if !s.current.PausedAt.IsZero() {
-		return s.current.TotalPlayTime + s.current.PausedAt.Sub(s.current.StartTime)
+	if !ts.PausedAt.IsZero() {
+		return ts.TotalPlayTime + ts.PausedAt.Sub(ts.StartTime)
 	}
 
 	// If playing, return accumulated time plus current play session
-	if s.current.Track != nil && s.current.Track.State == music.StatePlaying {
-		return s.current.TotalPlayTime + time.Since(s.current.StartTime)
+	if ts.Track != nil && ts.Track.State == music.StatePlaying {
+		return ts.TotalPlayTime + time.Since(ts.StartTime)
 	}
 
 	// Stopped or no track
-	return s.current.TotalPlayTime
+	return ts.TotalPlayTime
 }
 
 // Reset clears the current state
@@ -184,11 +264,15 @@ func (s *State) persist() error {
 	}
 
 	ps := persistedState{
-		Track:         s.current.Track,
-		StartTime:     s.current.StartTime,
-		Scrobbled:     s.current.Scrobbled,
-		PausedAt:      s.current.PausedAt,
-		TotalPlayTime: s.current.TotalPlayTime,
+		Track:           s.current.Track,
+		Source:          s.current.Source,
+		StartTime:       s.current.StartTime,
+		Scrobbled:       s.current.Scrobbled,
+		ScrobbleSkipped: s.current.ScrobbleSkipped,
+		PausedAt:        s.current.PausedAt,
+		TotalPlayTime:   s.current.TotalPlayTime,
+		PlayStartedAt:   s.current.PlayStartedAt,
+		Suspended:       s.suspended,
 	}
 
 	data, err := json.MarshalIndent(ps, "", "  ")
@@ -214,6 +298,7 @@ func (s *State) persist() error {
 
 	s.dirty = false
 	s.lastPersist = time.Now()
+	s.persists.Add(1)
 	return nil
 }
 
@@ -241,6 +326,11 @@ func (s *State) Flush() error {
 	return s.persist()
 }
 
+// PersistCount returns how many times the state file has been written
+func (s *State) PersistCount() uint64 {
+	return s.persists.Load()
+}
+
 // restore loads state from disk
 func (s *State) restore() error {
 	if s.filePath == "" {
@@ -260,7 +350,17 @@ func (s *State) restore() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.current = TrackState(ps)
+	s.current = TrackState{
+		Track:           ps.Track,
+		Source:          ps.Source,
+		StartTime:       ps.StartTime,
+		Scrobbled:       ps.Scrobbled,
+		ScrobbleSkipped: ps.ScrobbleSkipped,
+		PausedAt:        ps.PausedAt,
+		TotalPlayTime:   ps.TotalPlayTime,
+		PlayStartedAt:   ps.PlayStartedAt,
+	}
+	s.suspended = ps.Suspended
 
 	return nil
 }
@@ -31,7 +31,7 @@ func TestThrottledPersist_SkipsWhenIntervalNotElapsed(t *testing.T) {
 		Album:  "Album A",
 		State:  music.StatePlaying,
 	}
-	if err := s.SetTrack(track); err != nil {
+	if err := s.SetTrack("apple-music", track); err != nil {
 		t.Fatalf("SetTrack: %v", err)
 	}
 
@@ -77,7 +77,7 @@ func TestThrottledPersist_WritesWhenIntervalElapsed(t *testing.T) {
 		Album:  "Album B",
 		State:  music.StatePlaying,
 	}
-	if err := s.SetTrack(track); err != nil {
+	if err := s.SetTrack("apple-music", track); err != nil {
 		t.Fatalf("SetTrack: %v", err)
 	}
 
@@ -111,7 +111,7 @@ func TestFlush_WritesWhenDirty(t *testing.T) {
 		Album:  "Album C",
 		State:  music.StatePlaying,
 	}
-	if err := s.SetTrack(track); err != nil {
+	if err := s.SetTrack("apple-music", track); err != nil {
 		t.Fatalf("SetTrack: %v", err)
 	}
 
@@ -164,7 +164,7 @@ func TestFlush_NoOpWhenClean(t *testing.T) {
 		Album:  "Album D",
 		State:  music.StatePlaying,
 	}
-	if err := s.SetTrack(track); err != nil {
+	if err := s.SetTrack("apple-music", track); err != nil {
 		t.Fatalf("SetTrack: %v", err)
 	}
 
@@ -195,3 +195,86 @@ func TestFlush_NoOpWhenClean(t *testing.T) {
 		t.Error("Flush wrote to disk when state was clean")
 	}
 }
+
+func TestUpdatePosition_KeepsLatestTrack(t *testing.T) {
+	s := newTestState(t, time.Hour)
+
+	track := &music.Track{Name: "Song A", Artist: "Artist A", State: music.StatePlaying}
+	if err := s.SetTrack("apple-music", track); err != nil {
+		t.Fatalf("SetTrack: %v", err)
+	}
+
+	paused := *track
+	paused.Position = 30 * time.Second
+	paused.State = music.StatePaused
+	if err := s.UpdatePosition(&paused); err != nil {
+		t.Fatalf("UpdatePosition: %v", err)
+	}
+
+	got := s.GetState().Track
+	if got.State != music.StatePaused || got.Position != 30*time.Second {
+		t.Errorf("expected the paused track at 30s, got state %v at %v", got.State, got.Position)
+	}
+}
+
+func TestSkipScrobble_PersistsUntilTrackChanges(t *testing.T) {
+	s := newTestState(t, time.Hour)
+
+	if err := s.SetTrack("apple-music", &music.Track{Name: "Song A", Artist: "Artist A", State: music.StatePlaying}); err != nil {
+		t.Fatalf("SetTrack: %v", err)
+	}
+	if err := s.SkipScrobble(); err != nil {
+		t.Fatalf("SkipScrobble: %v", err)
+	}
+
+	restored, err := NewState(s.filePath)
+	if err != nil {
+		t.Fatalf("NewState: %v", err)
+	}
+	if !restored.GetState().ScrobbleSkipped {
+		t.Error("expected the skip to survive a restart")
+	}
+
+	if err := s.SetTrack("apple-music", &music.Track{Name: "Song B", Artist: "Artist A", State: music.StatePlaying}); err != nil {
+		t.Fatalf("SetTrack: %v", err)
+	}
+	if s.GetState().ScrobbleSkipped {
+		t.Error("expected a new track to be scrobbled again")
+	}
+}
+
+func TestSuspend_PersistsUntilResumed(t *testing.T) {
+	s := newTestState(t, time.Hour)
+
+	track := &music.Track{Name: "Song A", Artist: "Artist A", State: music.StatePlaying}
+	if err := s.SetTrack("lounge", track); err != nil {
+		t.Fatalf("SetTrack: %v", err)
+	}
+	if err := s.MarkScrobbled(); err != nil {
+		t.Fatalf("MarkScrobbled: %v", err)
+	}
+	if err := s.Suspend(); err != nil {
+		t.Fatalf("Suspend: %v", err)
+	}
+	if s.GetState().Track != nil {
+		t.Fatal("expected no current play after Suspend")
+	}
+
+	restored, err := NewState(s.filePath)
+	if err != nil {
+		t.Fatalf("NewState: %v", err)
+	}
+	other := &music.Track{Name: "Song B", Artist: "Artist A", State: music.StatePlaying}
+	if resumed, err := restored.Resume("lounge", other); err != nil || resumed {
+		t.Errorf("expected another track not to resume the play, got %v, %v", resumed, err)
+	}
+	if resumed, err := restored.Resume("lounge", track); err != nil || !resumed {
+		t.Fatalf("expected the play to resume after a restart, got %v, %v", resumed, err)
+	}
+	if state := restored.GetState(); state.Source != "lounge" || !state.Scrobbled {
+		t.Errorf("expected the scrobbled play on lounge, got %+v", state)
+	}
+	if _, _, finished, _ := restored.FinishSuspended("lounge", nil); finished {
+		t.Error("expected no suspended play once resumed")
+	}
+}
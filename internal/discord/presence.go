@@ -6,16 +6,10 @@ import (
 
 	"github.com/rs/zerolog"
 
+	"github.com/jfmyers9/scribbles/internal/events"
 	"github.com/jfmyers9/scribbles/internal/music"
 )
 
-// TrackUpdate represents a music player state change.
-// Mirrors daemon.TrackUpdate to avoid import cycles.
-type TrackUpdate struct {
-	Track *music.Track
-	Err   error
-}
-
 type rpcClient interface {
 	SetActivity(Activity) error
 	Close() error
@@ -47,24 +41,28 @@ func New(appID string, logger zerolog.Logger) *Presence {
 	}
 }
 
-// Run consumes TrackUpdates and sets Discord Rich Presence.
+// Run consumes daemon events and sets Discord Rich Presence.
 // Connects lazily on first playing track. If Discord isn't
-// running, logs the error and retries on the next update.
-func (p *Presence) Run(ctx context.Context, updates <-chan TrackUpdate) {
+// running, logs the error and retries on the next track event.
+func (p *Presence) Run(ctx context.Context, updates <-chan events.Event) {
 	for {
 		select {
 		case <-ctx.Done():
 			p.close()
 			return
-		case u, ok := <-updates:
+		case e, ok := <-updates:
 			if !ok {
 				p.close()
 				return
 			}
-			if u.Err != nil {
-				continue
+			switch e := e.(type) {
+			case events.TrackStarted:
+				p.handleTrack(&e.Track)
+			case events.TrackResumed:
+				p.handleTrack(&e.Track)
+			case events.TrackPaused, events.TrackStopped:
+				p.handleTrack(nil)
 			}
-			p.handleTrack(u.Track)
 		}
 	}
 }
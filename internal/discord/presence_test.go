@@ -11,6 +11,7 @@ import (
 
 	"github.com/rs/zerolog"
 
+	"github.com/jfmyers9/scribbles/internal/events"
 	"github.com/jfmyers9/scribbles/internal/music"
 )
 
@@ -161,7 +162,7 @@ func TestRunStopsOnContextCancel(t *testing.T) {
 	p.client = fake
 
 	ctx, cancel := context.WithCancel(context.Background())
-	updates := make(chan TrackUpdate, 1)
+	updates := make(chan events.Event, 1)
 	done := make(chan struct{})
 
 	go func() {
@@ -181,6 +182,31 @@ func TestRunStopsOnContextCancel(t *testing.T) {
 	}
 }
 
+func TestRunFollowsTrackEvents(t *testing.T) {
+	p, fake := newTestPresence()
+	track := playingTrack("Song", "Artist", "Album")
+
+	updates := make(chan events.Event, 5)
+	updates <- events.TrackStarted{Track: *track}
+	updates <- events.ScrobbleQueued{} // Not a track event: ignored
+	updates <- events.TrackPaused{Track: *track}
+	updates <- events.TrackResumed{Track: *track}
+	updates <- events.TrackStopped{}
+	close(updates)
+
+	p.Run(context.Background(), updates)
+
+	// Set, cleared on pause, set again on resume, cleared on stop
+	if len(fake.activities) != 4 {
+		t.Fatalf("expected 4 activity updates, got %d", len(fake.activities))
+	}
+	for i, wantSet := range []bool{true, false, true, false} {
+		if got := fake.activities[i].Details != ""; got != wantSet {
+			t.Errorf("activity %d: expected set=%v, got %+v", i, wantSet, fake.activities[i])
+		}
+	}
+}
+
 func TestActivityFields(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(itunesResponse{
@@ -0,0 +1,129 @@
+package events
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Bus fans events out to subscribers. Publishing never blocks: an event
+// for a subscriber whose buffer is full is dropped and counted against
+// that subscriber. The zero value is ready to use.
+type Bus struct {
+	mu      sync.Mutex
+	subs    map[*Subscription]struct{}
+	dropped map[string]uint64 // Events dropped per subscriber name, including closed subscriptions
+}
+
+// Subscription receives the events published on a Bus
+type Subscription struct {
+	name    string
+	bus     *Bus
+	ch      chan Event
+	dropped atomic.Uint64
+	closed  bool // Guarded by bus.mu
+}
+
+// SubscriberStats describes the subscribers sharing a name
+type SubscriberStats struct {
+	Name        string `json:"name"`
+	Subscribers int    `json:"subscribers"` // Open subscriptions
+	Dropped     uint64 `json:"dropped"`     // Events dropped since the bus started
+}
+
+// Subscribe registers a subscriber that can fall buffer events behind
+// before it starts missing them. The name identifies the subscriber in
+// Stats; several subscriptions may share one, e.g. one per HTTP client.
+func (b *Bus) Subscribe(name string, buffer int) *Subscription {
+	s := &Subscription{
+		name: name,
+		bus:  b,
+		ch:   make(chan Event, buffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs == nil {
+		b.subs = make(map[*Subscription]struct{})
+	}
+	b.subs[s] = struct{}{}
+	return s
+}
+
+// Publish sends an event to every subscriber that has room for it
+func (b *Bus) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subs {
+		select {
+		case s.ch <- e:
+		default:
+			s.dropped.Add(1)
+			if b.dropped == nil {
+				b.dropped = make(map[string]uint64)
+			}
+			b.dropped[s.name]++
+		}
+	}
+}
+
+// Stats returns per-name subscriber counts and drop totals, sorted by name
+func (b *Bus) Stats() []SubscriberStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	byName := make(map[string]*SubscriberStats)
+	stats := func(name string) *SubscriberStats {
+		st, ok := byName[name]
+		if !ok {
+			st = &SubscriberStats{Name: name}
+			byName[name] = st
+		}
+		return st
+	}
+	for s := range b.subs {
+		stats(s.name).Subscribers++
+	}
+	for name, dropped := range b.dropped {
+		stats(name).Dropped = dropped
+	}
+
+	result := make([]SubscriberStats, 0, len(byName))
+	for _, st := range byName {
+		result = append(result, *st)
+	}
+	slices.SortFunc(result, func(a, b SubscriberStats) int { return cmp.Compare(a.Name, b.Name) })
+	return result
+}
+
+// Events returns the channel events are delivered on. It is closed by Close.
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Name returns the name the subscription was registered with
+func (s *Subscription) Name() string {
+	return s.name
+}
+
+// Dropped returns how many events this subscription has missed because
+// its buffer was full
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close unsubscribes and closes the events channel. It is safe to call
+// more than once.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+	delete(s.bus.subs, s)
+	close(s.ch)
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"testing"
+)
+
+func TestBus_DeliversToEachSubscriber(t *testing.T) {
+	var b Bus
+	first := b.Subscribe("first", 1)
+	second := b.Subscribe("second", 1)
+
+	b.Publish(TrackStopped{})
+
+	for _, s := range []*Subscription{first, second} {
+		select {
+		case e := <-s.Events():
+			if e.Kind() != KindTrackStopped {
+				t.Errorf("%s: expected %s, got %s", s.Name(), KindTrackStopped, e.Kind())
+			}
+		default:
+			t.Errorf("%s: expected an event", s.Name())
+		}
+	}
+}
+
+func TestBus_DropsForSlowSubscribers(t *testing.T) {
+	var b Bus
+	slow := b.Subscribe("slow", 2)
+	fast := b.Subscribe("fast", 10)
+
+	for range 5 {
+		b.Publish(Error{Message: "boom"})
+	}
+
+	if len(slow.Events()) != 2 || slow.Dropped() != 3 {
+		t.Errorf("expected the slow subscriber to buffer 2 and drop 3, got %d and %d", len(slow.Events()), slow.Dropped())
+	}
+	if len(fast.Events()) != 5 || fast.Dropped() != 0 {
+		t.Errorf("expected the fast subscriber to get every event, got %d and dropped %d", len(fast.Events()), fast.Dropped())
+	}
+}
+
+func TestBus_Stats(t *testing.T) {
+	var b Bus
+	b.Subscribe("tui", 0)
+	first := b.Subscribe("http", 0)
+	b.Subscribe("http", 0)
+
+	b.Publish(TrackStopped{})
+	first.Close()
+	first.Close() // Closing twice is harmless
+	b.Publish(TrackStopped{})
+
+	if _, ok := <-first.Events(); ok {
+		t.Error("expected Close to close the events channel")
+	}
+
+	// Drops by closed subscriptions still count
+	want := []SubscriberStats{
+		{Name: "http", Subscribers: 1, Dropped: 3},
+		{Name: "tui", Subscribers: 1, Dropped: 2},
+	}
+	got := b.Stats()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d names, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("stats[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
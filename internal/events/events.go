@@ -0,0 +1,122 @@
+// Package events is the daemon's event bus. The daemon publishes typed
+// events as tracks play and scrobbles move through the queue, and the TUI,
+// Discord Rich Presence and the HTTP API subscribe to them.
+package events
+
+import (
+	"time"
+
+	"github.com/jfmyers9/scribbles/internal/music"
+)
+
+// Event kinds, as returned by Event.Kind
+const (
+	KindTrackStarted      = "track_started"
+	KindTrackPaused       = "track_paused"
+	KindTrackResumed      = "track_resumed"
+	KindTrackSkipped      = "track_skipped"
+	KindTrackStopped      = "track_stopped"
+	KindNowPlayingUpdated = "now_playing_updated"
+	KindScrobbleQueued    = "scrobble_queued"
+	KindScrobbleSubmitted = "scrobble_submitted"
+	KindScrobbleIgnored   = "scrobble_ignored"
+	KindScrobbleFailed    = "scrobble_failed"
+	KindError             = "error"
+)
+
+// Event is something that happened in the daemon
+type Event interface {
+	// Kind names the event type, e.g. "track_started"
+	Kind() string
+}
+
+// TrackStarted is published when a new track becomes the current track.
+// The track may start out paused.
+type TrackStarted struct {
+	Track   music.Track `json:"track"`
+	Source  string      `json:"source,omitempty"`  // Music source playing the track
+	Resumed bool        `json:"resumed,omitempty"` // Continues a play set aside when another source took over
+}
+
+// TrackPaused is published when the current track is paused
+type TrackPaused struct {
+	Track music.Track `json:"track"`
+}
+
+// TrackResumed is published when the current track plays again after a pause
+type TrackResumed struct {
+	Track music.Track `json:"track"`
+}
+
+// TrackSkipped is published when a play ends before the scrobble point
+// without having been scrobbled
+type TrackSkipped struct {
+	Track  music.Track   `json:"track"`
+	Played time.Duration `json:"played"`
+}
+
+// TrackStopped is published when music stops
+type TrackStopped struct{}
+
+// NowPlayingUpdated is published after a backend is told about a new track
+type NowPlayingUpdated struct {
+	Track   music.Track `json:"track"`
+	Backend string      `json:"backend"`
+	Error   string      `json:"error,omitempty"` // Set when the update failed
+}
+
+// Scrobble identifies a scrobble in the queue
+type Scrobble struct {
+	ID        int64     `json:"id"`
+	Artist    string    `json:"artist"`
+	Track     string    `json:"track"`
+	Album     string    `json:"album,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source,omitempty"`
+}
+
+// ScrobbleQueued is published when a play is added to the scrobble queue
+type ScrobbleQueued struct {
+	Scrobble Scrobble `json:"scrobble"`
+}
+
+// ScrobbleSubmitted is published when a backend accepts a scrobble
+type ScrobbleSubmitted struct {
+	Scrobble Scrobble `json:"scrobble"`
+	Backend  string   `json:"backend"`
+}
+
+// ScrobbleIgnored is published when a backend ignores a scrobble for good
+type ScrobbleIgnored struct {
+	Scrobble Scrobble `json:"scrobble"`
+	Backend  string   `json:"backend"`
+	Code     int      `json:"code"`
+	Message  string   `json:"message"`
+}
+
+// ScrobbleFailed is published when submitting a scrobble to a backend
+// fails. Retry reports whether the submission will be retried.
+type ScrobbleFailed struct {
+	Scrobble Scrobble `json:"scrobble"`
+	Backend  string   `json:"backend"`
+	Error    string   `json:"error"`
+	Retry    bool     `json:"retry"`
+}
+
+// Error is published when the daemon fails to handle a track update or
+// to queue a scrobble
+type Error struct {
+	Message string `json:"message"`
+}
+
+func (TrackStarted) Kind() string      { return KindTrackStarted }
+func (TrackPaused) Kind() string       { return KindTrackPaused }
+func (TrackResumed) Kind() string      { return KindTrackResumed }
+func (TrackSkipped) Kind() string      { return KindTrackSkipped }
+func (TrackStopped) Kind() string      { return KindTrackStopped }
+func (NowPlayingUpdated) Kind() string { return KindNowPlayingUpdated }
+func (ScrobbleQueued) Kind() string    { return KindScrobbleQueued }
+func (ScrobbleSubmitted) Kind() string { return KindScrobbleSubmitted }
+func (ScrobbleIgnored) Kind() string   { return KindScrobbleIgnored }
+func (ScrobbleFailed) Kind() string    { return KindScrobbleFailed }
+func (Error) Kind() string             { return KindError }
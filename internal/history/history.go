@@ -0,0 +1,243 @@
+// Package history stores a permanent local record of every play.
+//
+// Unlike the scrobble queue, which only holds scrobbles until they are
+// delivered and is cleaned up periodically, the history keeps every play
+// the daemon observed, including skipped tracks, and never needs the
+// network.
+package history
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// Store is a SQLite-backed listening history
+type Store struct {
+	db *sql.DB
+}
+
+// Play is a single listen of a track
+type Play struct {
+	ID        int64
+	Track     string
+	Artist    string
+	Album     string
+	Duration  time.Duration // Length of the track
+	Played    time.Duration // How long the track was actually played, excluding pauses
+	StartedAt time.Time     // When playback of the track began
+	Skipped   bool          // Playback ended before the scrobble point
+	Scrobbled bool          // The play was queued for scrobbling
+	Source    string        // Music player the play came from, e.g. "apple-music"
+}
+
+// playJSON is the JSON representation of a play
+type playJSON struct {
+	StartedAt time.Time `json:"started_at"`
+	Artist    string    `json:"artist"`
+	Track     string    `json:"track"`
+	Album     string    `json:"album,omitempty"`
+	Duration  int       `json:"duration_seconds"`
+	Played    int       `json:"played_seconds"`
+	Skipped   bool      `json:"skipped"`
+	Scrobbled bool      `json:"scrobbled"`
+	Source    string    `json:"source,omitempty"`
+}
+
+// MarshalJSON encodes the play as shown by "scribbles history -o json" and
+// the daemon's HTTP API
+func (p Play) MarshalJSON() ([]byte, error) {
+	return json.Marshal(playJSON{
+		StartedAt: p.StartedAt,
+		Artist:    p.Artist,
+		Track:     p.Track,
+		Album:     p.Album,
+		Duration:  int(p.Duration.Seconds()),
+		Played:    int(p.Played.Seconds()),
+		Skipped:   p.Skipped,
+		Scrobbled: p.Scrobbled,
+		Source:    p.Source,
+	})
+}
+
+// Filter selects plays for List. Zero fields match everything.
+type Filter struct {
+	Artist string    // Only plays by this artist (case-insensitive)
+	Album  string    // Only plays from this album (case-insensitive)
+	Since  time.Time // Only plays started at or after this time
+	Until  time.Time // Only plays started before this time
+	Limit  int       // Maximum number of results (0 = no limit)
+}
+
+// where returns the SQL condition and arguments for the filter
+func (f Filter) where() (string, []any) {
+	conditions := []string{"1 = 1"}
+	var args []any
+
+	if f.Artist != "" {
+		conditions = append(conditions, "artist = ? COLLATE NOCASE")
+		args = append(args, f.Artist)
+	}
+	if f.Album != "" {
+		conditions = append(conditions, "album = ? COLLATE NOCASE")
+		args = append(args, f.Album)
+	}
+	if !f.Since.IsZero() {
+		conditions = append(conditions, "started_at >= ?")
+		args = append(args, f.Since.Unix())
+	}
+	if !f.Until.IsZero() {
+		conditions = append(conditions, "started_at < ?")
+		args = append(args, f.Until.Unix())
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// NewStore opens the listening history database, creating or upgrading
+// its schema as needed
+func NewStore(dbPath string) (*Store, error) {
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// A single connection keeps in-memory databases consistent
+	db.SetMaxOpenConns(1)
+
+	pragmas := []string{
+		"PRAGMA busy_timeout = 10000", // Wait up to 10 seconds on lock
+		"PRAGMA synchronous = NORMAL", // Balance between safety and performance
+		"PRAGMA journal_mode = WAL",   // Allow reads while the daemon writes
+	}
+
+	for _, pragma := range pragmas {
+		if _, err := db.Exec(pragma); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to set pragma: %w", err)
+		}
+	}
+
+	if err := migrate(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the database connection
+func (s *Store) Close() error {
+	if s.db != nil {
+		return s.db.Close()
+	}
+	return nil
+}
+
+// Add records a play and returns its ID
+func (s *Store) Add(ctx context.Context, p Play) (int64, error) {
+	query := `
+		INSERT INTO plays (track_name, artist, album, duration, played, started_at, skipped, scrobbled, source)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := s.db.ExecContext(ctx, query,
+		p.Track,
+		p.Artist,
+		p.Album,
+		int64(p.Duration.Seconds()),
+		int64(p.Played.Seconds()),
+		p.StartedAt.Unix(),
+		p.Skipped,
+		p.Scrobbled,
+		p.Source,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert play: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get insert id: %w", err)
+	}
+
+	return id, nil
+}
+
+// List retrieves plays matching the filter, newest first
+func (s *Store) List(ctx context.Context, filter Filter) ([]Play, error) {
+	where, args := filter.where()
+	query := `
+		SELECT id, track_name, artist, COALESCE(album, ''), duration, played, started_at, skipped, scrobbled, source
+		FROM plays
+		WHERE ` + where + `
+		ORDER BY started_at DESC, id DESC
+	`
+
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plays: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var plays []Play
+	for rows.Next() {
+		var p Play
+		var durationSecs, playedSecs, startedUnix int64
+
+		err := rows.Scan(
+			&p.ID,
+			&p.Track,
+			&p.Artist,
+			&p.Album,
+			&durationSecs,
+			&playedSecs,
+			&startedUnix,
+			&p.Skipped,
+			&p.Scrobbled,
+			&p.Source,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan play: %w", err)
+		}
+
+		p.Duration = time.Duration(durationSecs) * time.Second
+		p.Played = time.Duration(playedSecs) * time.Second
+		p.StartedAt = time.Unix(startedUnix, 0)
+
+		plays = append(plays, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating plays: %w", err)
+	}
+
+	return plays, nil
+}
+
+// Has reports whether the history holds a play of the same track by the
+// same artist (case-insensitive) started at the same second
+func (s *Store) Has(ctx context.Context, artist, track string, startedAt time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM plays
+			WHERE artist = ? COLLATE NOCASE AND track_name = ? COLLATE NOCASE AND started_at = ?
+		)
+	`
+
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, query, artist, track, startedAt.Unix()).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to look up play: %w", err)
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,185 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// createTestStore creates an in-memory history store for testing
+func createTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	store, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create test store: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+
+	return store
+}
+
+func TestStoreAddAndList(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	started := time.Date(2026, 3, 1, 18, 30, 0, 0, time.UTC)
+	want := Play{
+		Track:     "Believe",
+		Artist:    "Cher",
+		Album:     "Believe",
+		Duration:  239 * time.Second,
+		Played:    31 * time.Second,
+		StartedAt: started,
+		Skipped:   true,
+		Source:    "apple-music",
+	}
+
+	id, err := store.Add(ctx, want)
+	if err != nil {
+		t.Fatalf("failed to add play: %v", err)
+	}
+	want.ID = id
+
+	plays, err := store.List(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("failed to list plays: %v", err)
+	}
+	if len(plays) != 1 {
+		t.Fatalf("expected 1 play, got %d", len(plays))
+	}
+
+	got := plays[0]
+	if !got.StartedAt.Equal(want.StartedAt) {
+		t.Errorf("expected started at %v, got %v", want.StartedAt, got.StartedAt)
+	}
+	got.StartedAt = want.StartedAt
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestStoreListFilters(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	seed := []Play{
+		{Artist: "Cher", Album: "Believe", Track: "Believe"},
+		{Artist: "Cher", Album: "Believe", Track: "Strong Enough"},
+		{Artist: "Cher", Album: "Heart of Stone", Track: "If I Could Turn Back Time"},
+		{Artist: "The Beatles", Album: "Help!", Track: "Yesterday"},
+	}
+	for i, p := range seed {
+		p.Duration = 3 * time.Minute
+		p.Played = 3 * time.Minute
+		p.StartedAt = base.Add(time.Duration(i) * time.Hour)
+		if _, err := store.Add(ctx, p); err != nil {
+			t.Fatalf("failed to add play: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{name: "all newest first", filter: Filter{}, want: []string{"Yesterday", "If I Could Turn Back Time", "Strong Enough", "Believe"}},
+		{name: "artist", filter: Filter{Artist: "cher"}, want: []string{"If I Could Turn Back Time", "Strong Enough", "Believe"}},
+		{name: "album", filter: Filter{Album: "BELIEVE"}, want: []string{"Strong Enough", "Believe"}},
+		{name: "time range", filter: Filter{Since: base.Add(time.Hour), Until: base.Add(3 * time.Hour)}, want: []string{"If I Could Turn Back Time", "Strong Enough"}},
+		{name: "limit", filter: Filter{Limit: 1}, want: []string{"Yesterday"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plays, err := store.List(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("failed to list plays: %v", err)
+			}
+
+			var got []string
+			for _, p := range plays {
+				got = append(got, p.Track)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("expected %v, got %v", tt.want, got)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestNewStoreRefusesNewerSchema(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "history.db")
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if _, err := db.Exec("PRAGMA user_version = 99"); err != nil {
+		t.Fatalf("failed to set user_version: %v", err)
+	}
+	_ = db.Close()
+
+	_, err = NewStore(dbPath)
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("expected ErrSchemaTooNew, got %v", err)
+	}
+}
+
+func TestStorePersistsAcrossReopen(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "history.db")
+	ctx := context.Background()
+
+	store, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	if _, err := store.Add(ctx, Play{Track: "Yesterday", Artist: "The Beatles", StartedAt: time.Now()}); err != nil {
+		t.Fatalf("failed to add play: %v", err)
+	}
+	_ = store.Close()
+
+	store, err = NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	plays, err := store.List(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("failed to list plays: %v", err)
+	}
+	if len(plays) != 1 {
+		t.Errorf("expected play to survive reopening, got %d plays", len(plays))
+	}
+}
+
+func TestStoreHas(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	started := time.Date(2026, 3, 1, 18, 40, 0, 0, time.UTC)
+	if _, err := store.Add(ctx, Play{Artist: "Cher", Track: "Believe", StartedAt: started}); err != nil {
+		t.Fatalf("failed to add play: %v", err)
+	}
+
+	if ok, err := store.Has(ctx, "cher", "BELIEVE", started); err != nil || !ok {
+		t.Errorf("expected case-insensitive match, got %v, %v", ok, err)
+	}
+	if ok, err := store.Has(ctx, "Cher", "Believe", started.Add(time.Minute)); err != nil || ok {
+		t.Errorf("expected no match at a different time, got %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,56 @@
+package history
+
+import (
+	"database/sql"
+
+	"github.com/jfmyers9/scribbles/internal/sqlitemigrate"
+)
+
+// ErrSchemaTooNew is returned by NewStore when the database was written by
+// a newer version of scribbles than this one
+var ErrSchemaTooNew = sqlitemigrate.ErrSchemaTooNew
+
+// migrations are applied in order by sqlitemigrate, like the scrobble
+// queue's. Never edit a released migration: append a new one instead.
+var migrations = []sqlitemigrate.Migration{
+	{
+		Version:     1,
+		Description: "create plays table",
+		SQL: `
+			CREATE TABLE plays (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				track_name TEXT NOT NULL,
+				artist TEXT NOT NULL,
+				album TEXT,
+				duration INTEGER NOT NULL,
+				played INTEGER NOT NULL,
+				started_at INTEGER NOT NULL,
+				skipped BOOLEAN DEFAULT 0,
+				scrobbled BOOLEAN DEFAULT 0,
+				source TEXT NOT NULL DEFAULT '',
+				created_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now'))
+			);
+
+			CREATE INDEX idx_plays_started_at ON plays(started_at);
+			CREATE INDEX idx_plays_artist ON plays(artist COLLATE NOCASE);
+		`,
+	},
+	{
+		Version:     2,
+		Description: "create sync_state table",
+		SQL: `
+			CREATE TABLE sync_state (
+				username TEXT PRIMARY KEY COLLATE NOCASE,
+				synced_until INTEGER NOT NULL DEFAULT 0,
+				pending_to INTEGER NOT NULL DEFAULT 0,
+				pending_newest INTEGER NOT NULL DEFAULT 0,
+				updated_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now'))
+			);
+		`,
+	},
+}
+
+// migrate brings the database schema up to the latest migration
+func migrate(db *sql.DB) error {
+	return sqlitemigrate.Migrate(db, migrations)
+}
@@ -0,0 +1,281 @@
+package history
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// StatsOptions configures ComputeStats
+type StatsOptions struct {
+	Since    time.Time      // Start of the period (zero = first play)
+	Until    time.Time      // End of the period, exclusive (zero = Now)
+	Top      int            // Entries per top list (default 10)
+	Location *time.Location // Time zone for days, weeks and hours (default time.Local)
+	Now      time.Time      // Current time (default time.Now())
+}
+
+// Stats summarises listening over a period
+type Stats struct {
+	Since time.Time // Start of the period
+	Until time.Time // End of the period, exclusive
+
+	Plays         int           // Plays that were not skipped
+	Skips         int           // Plays skipped before the scrobble point
+	ListeningTime time.Duration // Time actually played, including skips
+	Artists       int           // Distinct artists played
+
+	TopArtists []Ranked
+	TopAlbums  []Ranked
+	TopTracks  []Ranked
+
+	Days  []Bucket // One per day of the period
+	Weeks []Bucket // One per week of the period, starting on Monday
+
+	CurrentStreak int // Consecutive days with listening up to the end of the period
+	LongestStreak int // Longest run of consecutive days with listening
+
+	// Heatmap counts plays by weekday (indexed by time.Weekday) and hour
+	Heatmap [7][24]int
+
+	// NewArtists are artists played for the first time during the period,
+	// in order of discovery
+	NewArtists []Discovery
+}
+
+// Ranked is an entry in a top list. Album and Track are empty for artists,
+// and Track is empty for albums.
+type Ranked struct {
+	Artist string
+	Album  string
+	Track  string
+	Plays  int
+	Time   time.Duration
+}
+
+// Bucket aggregates listening over a day or week
+type Bucket struct {
+	Start time.Time
+	Plays int
+	Time  time.Duration
+}
+
+// Discovery records when an artist was first played
+type Discovery struct {
+	Artist      string
+	FirstPlayed time.Time
+}
+
+// Stats computes listening statistics over the store's history. Plays
+// before the period are read too, so artists heard earlier are not
+// counted as new.
+func (s *Store) Stats(ctx context.Context, opts StatsOptions) (*Stats, error) {
+	plays, err := s.List(ctx, Filter{Until: opts.Until})
+	if err != nil {
+		return nil, err
+	}
+	return ComputeStats(plays, opts), nil
+}
+
+// ComputeStats computes listening statistics from plays in any order.
+// Plays outside the period only contribute to new-artist discovery.
+func ComputeStats(plays []Play, opts StatsOptions) *Stats {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	top := opts.Top
+	if top <= 0 {
+		top = 10
+	}
+
+	plays = slices.Clone(plays)
+	slices.SortStableFunc(plays, func(a, b Play) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+
+	st := &Stats{Since: opts.Since, Until: opts.Until}
+	if st.Until.IsZero() {
+		st.Until = now
+	}
+
+	firstPlayed := make(map[string]Discovery)
+	var inPeriod []Play
+	for _, p := range plays {
+		key := strings.ToLower(p.Artist)
+		if _, ok := firstPlayed[key]; !ok {
+			firstPlayed[key] = Discovery{Artist: p.Artist, FirstPlayed: p.StartedAt}
+		}
+		if p.StartedAt.Before(st.Since) || !p.StartedAt.Before(st.Until) {
+			continue
+		}
+		inPeriod = append(inPeriod, p)
+	}
+
+	if st.Since.IsZero() && len(inPeriod) > 0 {
+		st.Since = inPeriod[0].StartedAt
+	}
+
+	st.Days = buckets(st.Since, st.Until, loc, 0, 0, 1)
+	st.Weeks = buckets(startOfWeek(st.Since, loc), st.Until, loc, 0, 0, 7)
+
+	artists := newRanking()
+	albums := newRanking()
+	tracks := newRanking()
+	seenArtists := make(map[string]bool)
+
+	for _, p := range inPeriod {
+		st.ListeningTime += p.Played
+		addToBucket(st.Days, p)
+		addToBucket(st.Weeks, p)
+
+		artistKey := strings.ToLower(p.Artist)
+		if !seenArtists[artistKey] {
+			seenArtists[artistKey] = true
+			if d := firstPlayed[artistKey]; !d.FirstPlayed.Before(st.Since) {
+				st.NewArtists = append(st.NewArtists, d)
+			}
+		}
+
+		if p.Skipped {
+			st.Skips++
+			continue
+		}
+		st.Plays++
+
+		local := p.StartedAt.In(loc)
+		st.Heatmap[local.Weekday()][local.Hour()]++
+
+		artists.add(artistKey, Ranked{Artist: p.Artist}, p.Played)
+		if p.Album != "" {
+			albums.add(artistKey+"\x00"+strings.ToLower(p.Album), Ranked{Artist: p.Artist, Album: p.Album}, p.Played)
+		}
+		tracks.add(artistKey+"\x00"+strings.ToLower(p.Track), Ranked{Artist: p.Artist, Album: p.Album, Track: p.Track}, p.Played)
+	}
+
+	st.Artists = len(seenArtists)
+	st.TopArtists = artists.top(top)
+	st.TopAlbums = albums.top(top)
+	st.TopTracks = tracks.top(top)
+	st.CurrentStreak, st.LongestStreak = streaks(st.Days)
+
+	return st
+}
+
+// buckets returns consecutive buckets of years/months/days length covering
+// [start, end), with the first bucket starting at midnight of start's day
+func buckets(start, end time.Time, loc *time.Location, years, months, days int) []Bucket {
+	if start.IsZero() || !start.Before(end) {
+		return nil
+	}
+
+	var result []Bucket
+	for t := startOfDay(start, loc); t.Before(end); t = t.AddDate(years, months, days) {
+		result = append(result, Bucket{Start: t})
+	}
+	return result
+}
+
+// addToBucket adds a play to the bucket containing its start time
+func addToBucket(bs []Bucket, p Play) {
+	i, found := slices.BinarySearchFunc(bs, p.StartedAt, func(b Bucket, t time.Time) int {
+		return b.Start.Compare(t)
+	})
+	if !found {
+		i--
+	}
+	if i < 0 {
+		return
+	}
+
+	bs[i].Time += p.Played
+	if !p.Skipped {
+		bs[i].Plays++
+	}
+}
+
+// streaks returns the current and longest runs of consecutive days with
+// listening. The current streak may end on the last or second-to-last
+// day, so a streak is not broken before the day is over.
+func streaks(days []Bucket) (current, longest int) {
+	run := 0
+	for _, d := range days {
+		if d.Time > 0 || d.Plays > 0 {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+
+	end := len(days) - 1
+	if end >= 0 && days[end].Time == 0 && days[end].Plays == 0 {
+		end--
+	}
+	for i := end; i >= 0 && (days[i].Time > 0 || days[i].Plays > 0); i-- {
+		current++
+	}
+
+	return current, longest
+}
+
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// startOfWeek returns midnight of the Monday on or before t
+func startOfWeek(t time.Time, loc *time.Location) time.Time {
+	day := startOfDay(t, loc)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// ranking counts plays per key, keeping the first spelling seen
+type ranking struct {
+	entries map[string]*Ranked
+}
+
+func newRanking() *ranking {
+	return &ranking{entries: make(map[string]*Ranked)}
+}
+
+func (r *ranking) add(key string, entry Ranked, played time.Duration) {
+	e, ok := r.entries[key]
+	if !ok {
+		e = &entry
+		r.entries[key] = e
+	}
+	e.Plays++
+	e.Time += played
+}
+
+// top returns the n entries with the most plays, breaking ties by time
+// played and then by name
+func (r *ranking) top(n int) []Ranked {
+	result := make([]Ranked, 0, len(r.entries))
+	for _, e := range r.entries {
+		result = append(result, *e)
+	}
+
+	slices.SortFunc(result, func(a, b Ranked) int {
+		return cmp.Or(
+			cmp.Compare(b.Plays, a.Plays),
+			cmp.Compare(b.Time, a.Time),
+			cmp.Compare(a.Artist, b.Artist),
+			cmp.Compare(a.Album, b.Album),
+			cmp.Compare(a.Track, b.Track),
+		)
+	})
+
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
@@ -0,0 +1,149 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// at returns a time on the given day of March 2026 in UTC
+func at(day, hour int) time.Time {
+	return time.Date(2026, 3, day, hour, 0, 0, 0, time.UTC)
+}
+
+func statsFixture() []Play {
+	play := func(started time.Time, artist, album, track string, played time.Duration) Play {
+		return Play{Artist: artist, Album: album, Track: track, Duration: 4 * time.Minute, Played: played, StartedAt: started}
+	}
+
+	skip := play(at(3, 9), "Cher", "Believe", "Strong Enough", 20*time.Second)
+	skip.Skipped = true
+
+	return []Play{
+		// Before the period: The Beatles are not new
+		play(at(1, 20), "The Beatles", "Help!", "Yesterday", 2*time.Minute),
+
+		// Monday 2 March
+		play(at(2, 8), "Cher", "Believe", "Believe", 4*time.Minute),
+		play(at(2, 8), "cher", "believe", "believe", 4*time.Minute),
+		play(at(2, 21), "The Beatles", "Help!", "Yesterday", 2*time.Minute),
+
+		// Tuesday 3 March
+		play(at(3, 8), "Cher", "Believe", "Believe", 4*time.Minute),
+		skip,
+
+		// Thursday 5 March, after a gap
+		play(at(5, 22), "Madonna", "Ray of Light", "Frozen", 4*time.Minute),
+
+		// After the period
+		play(at(9, 8), "Kylie Minogue", "Fever", "Fever", 3*time.Minute),
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	st := ComputeStats(statsFixture(), StatsOptions{
+		Since:    at(2, 0),
+		Until:    at(6, 0),
+		Top:      2,
+		Location: time.UTC,
+	})
+
+	if st.Plays != 5 || st.Skips != 1 {
+		t.Errorf("expected 5 plays and 1 skip, got %d and %d", st.Plays, st.Skips)
+	}
+	if want := 18*time.Minute + 20*time.Second; st.ListeningTime != want {
+		t.Errorf("expected listening time %v, got %v", want, st.ListeningTime)
+	}
+	if st.Artists != 3 {
+		t.Errorf("expected 3 artists, got %d", st.Artists)
+	}
+
+	if len(st.TopArtists) != 2 || st.TopArtists[0].Artist != "Cher" || st.TopArtists[0].Plays != 3 {
+		t.Errorf("expected Cher (3 plays, skips excluded) to top artists, got %+v", st.TopArtists)
+	}
+	if st.TopArtists[1].Artist != "Madonna" {
+		t.Errorf("expected Madonna to beat The Beatles on time played, got %+v", st.TopArtists[1])
+	}
+	if st.TopTracks[0].Track != "Believe" || st.TopTracks[0].Plays != 3 {
+		t.Errorf("expected case-insensitive grouping of Believe, got %+v", st.TopTracks[0])
+	}
+	if st.TopAlbums[0].Album != "Believe" || st.TopAlbums[0].Artist != "Cher" {
+		t.Errorf("unexpected top album: %+v", st.TopAlbums[0])
+	}
+
+	if len(st.Days) != 4 {
+		t.Fatalf("expected 4 days, got %d", len(st.Days))
+	}
+	if d := st.Days[0]; !d.Start.Equal(at(2, 0)) || d.Plays != 3 || d.Time != 10*time.Minute {
+		t.Errorf("unexpected first day: %+v", d)
+	}
+	if d := st.Days[2]; d.Plays != 0 || d.Time != 0 {
+		t.Errorf("expected no listening on 4 March, got %+v", d)
+	}
+
+	if len(st.Weeks) != 1 || st.Weeks[0].Plays != 5 {
+		t.Errorf("expected a single week with 5 plays, got %+v", st.Weeks)
+	}
+
+	if st.LongestStreak != 2 || st.CurrentStreak != 1 {
+		t.Errorf("expected streaks current=1 longest=2, got current=%d longest=%d", st.CurrentStreak, st.LongestStreak)
+	}
+
+	if st.Heatmap[time.Monday][8] != 2 || st.Heatmap[time.Thursday][22] != 1 {
+		t.Errorf("unexpected heatmap: Monday 08h=%d Thursday 22h=%d",
+			st.Heatmap[time.Monday][8], st.Heatmap[time.Thursday][22])
+	}
+	if st.Heatmap[time.Tuesday][9] != 0 {
+		t.Error("expected skips to be left out of the heatmap")
+	}
+
+	if len(st.NewArtists) != 2 || st.NewArtists[0].Artist != "Cher" || st.NewArtists[1].Artist != "Madonna" {
+		t.Errorf("expected Cher and Madonna to be new, got %+v", st.NewArtists)
+	}
+}
+
+func TestComputeStats_OpenRange(t *testing.T) {
+	now := at(10, 12)
+	st := ComputeStats(statsFixture(), StatsOptions{Location: time.UTC, Now: now})
+
+	if !st.Since.Equal(at(1, 20)) || !st.Until.Equal(now) {
+		t.Errorf("expected range from first play to now, got %v - %v", st.Since, st.Until)
+	}
+	if len(st.Days) != 10 {
+		t.Errorf("expected 10 days, got %d", len(st.Days))
+	}
+	// 9 March had a play, 10 March has none yet: the streak is not broken
+	if st.CurrentStreak != 1 {
+		t.Errorf("expected current streak 1, got %d", st.CurrentStreak)
+	}
+	if len(st.Weeks) != 3 || !st.Weeks[0].Start.Equal(at(1, 0).AddDate(0, 0, -6)) {
+		t.Errorf("expected weeks to start on Monday 23 February, got %+v", st.Weeks)
+	}
+}
+
+func TestComputeStats_Empty(t *testing.T) {
+	st := ComputeStats(nil, StatsOptions{Location: time.UTC})
+	if st.Plays != 0 || len(st.Days) != 0 || st.CurrentStreak != 0 || len(st.TopArtists) != 0 {
+		t.Errorf("expected empty stats, got %+v", st)
+	}
+}
+
+func TestStoreStats(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	for _, p := range statsFixture() {
+		if _, err := store.Add(ctx, p); err != nil {
+			t.Fatalf("failed to add play: %v", err)
+		}
+	}
+
+	st, err := store.Stats(ctx, StatsOptions{Since: at(2, 0), Until: at(6, 0), Location: time.UTC})
+	if err != nil {
+		t.Fatalf("failed to compute stats: %v", err)
+	}
+	if st.Plays != 5 || len(st.NewArtists) != 2 {
+		t.Errorf("expected plays before the period to count only for discovery, got %d plays and %d new artists",
+			st.Plays, len(st.NewArtists))
+	}
+}
@@ -0,0 +1,318 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jfmyers9/scribbles/pkg/lastfm"
+)
+
+// SourceLastFM is the Play.Source of plays backfilled by Sync
+const SourceLastFM = "lastfm"
+
+// Sync defaults
+const (
+	defaultSyncPageSize = 200 // Largest page user.getRecentTracks allows
+	defaultSyncInterval = time.Second
+
+	// syncMatchSlack is how far a scrobble may fall outside a local play
+	// and still be treated as the same listen. Two real scrobbles of one
+	// track can never be this close together.
+	syncMatchSlack = 10 * time.Second
+)
+
+// RecentTracksFetcher fetches one page of a user's scrobbles, newest first.
+// It is satisfied by *lastfm.UserService.
+type RecentTracksFetcher interface {
+	GetRecentTracks(ctx context.Context, user string, opts lastfm.RecentTracksOptions) (*lastfm.RecentTracksResponse, error)
+}
+
+// SyncOptions controls a Sync run
+type SyncOptions struct {
+	User     string        // Last.fm user whose scrobbles are fetched
+	Full     bool          // Ignore previous progress and scan the whole account
+	Interval time.Duration // Minimum time between requests (default 1s)
+	PageSize int           // Scrobbles per request (default 200)
+	Now      time.Time     // Upper bound of a new run (default time.Now)
+
+	// Progress, when set, is called after each page is merged
+	Progress func(SyncProgress)
+}
+
+// SyncProgress reports how far a Sync run has got
+type SyncProgress struct {
+	Page       int
+	TotalPages int
+	Fetched    int // Scrobbles fetched so far in this run
+	Added      int // Plays added so far in this run
+}
+
+// SyncResult summarizes a completed Sync run
+type SyncResult struct {
+	Fetched     int       // Scrobbles returned by Last.fm
+	Added       int       // Scrobbles added to the history
+	Duplicates  int       // Scrobbles already in the history
+	Resumed     bool      // The run continued an interrupted one
+	SyncedUntil time.Time // Newest scrobble the history is now complete up to
+}
+
+// syncState is the persisted progress of syncing one user.
+//
+// SyncedUntil is the newest scrobble covered by a completed run; the next
+// run only asks for scrobbles from then on. While a run is in progress,
+// PendingTo holds the oldest scrobble merged so far, so an interrupted run
+// can pick up where it stopped, and PendingNewest holds the newest scrobble
+// seen, which becomes SyncedUntil once the run completes.
+type syncState struct {
+	SyncedUntil   time.Time
+	PendingTo     time.Time
+	PendingNewest time.Time
+}
+
+// Sync merges a Last.fm user's scrobbles into the history.
+//
+// Pages are fetched newest first, at most one request per Interval, and
+// progress is saved after every page. Each run only fetches scrobbles
+// newer than the last completed run, and a run that is interrupted
+// resumes from the oldest page it had reached. Scrobbles that match a
+// play already in the history, such as one recorded by the daemon, are
+// skipped, so re-fetching a page is harmless.
+func (s *Store) Sync(ctx context.Context, fetcher RecentTracksFetcher, opts SyncOptions) (SyncResult, error) {
+	var result SyncResult
+
+	if opts.User == "" {
+		return result, fmt.Errorf("user is required")
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = defaultSyncPageSize
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultSyncInterval
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	state, err := s.loadSyncState(ctx, opts.User)
+	if err != nil {
+		return result, err
+	}
+	if opts.Full {
+		state.PendingTo = time.Time{}
+		state.PendingNewest = time.Time{}
+	}
+
+	from := state.SyncedUntil
+	if opts.Full {
+		from = time.Time{}
+	}
+
+	// The upper bound stays fixed for the whole run so that scrobbles made
+	// while syncing cannot shift the pages underneath us
+	to := state.PendingTo
+	result.Resumed = !to.IsZero()
+	if to.IsZero() {
+		to = opts.Now
+	}
+
+	for page := 1; ; page++ {
+		if page > 1 {
+			if err := sleepContext(ctx, opts.Interval); err != nil {
+				return result, err
+			}
+		}
+
+		resp, err := fetcher.GetRecentTracks(ctx, opts.User, lastfm.RecentTracksOptions{
+			ListOptions: lastfm.ListOptions{Limit: opts.PageSize, Page: page},
+			From:        from,
+			To:          to,
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to fetch page %d: %w", page, err)
+		}
+
+		var oldest time.Time
+		for _, t := range resp.Tracks {
+			if t.NowPlaying || t.Timestamp.IsZero() {
+				continue
+			}
+			result.Fetched++
+			oldest = t.Timestamp
+			if t.Timestamp.After(state.PendingNewest) {
+				state.PendingNewest = t.Timestamp
+			}
+
+			added, err := s.mergeScrobble(ctx, t)
+			if err != nil {
+				return result, err
+			}
+			if added {
+				result.Added++
+			} else {
+				result.Duplicates++
+			}
+		}
+
+		if !oldest.IsZero() {
+			state.PendingTo = oldest
+			if err := s.saveSyncState(ctx, opts.User, state); err != nil {
+				return result, err
+			}
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(SyncProgress{
+				Page:       page,
+				TotalPages: resp.TotalPages,
+				Fetched:    result.Fetched,
+				Added:      result.Added,
+			})
+		}
+
+		if len(resp.Tracks) == 0 || page >= resp.TotalPages {
+			break
+		}
+	}
+
+	if state.PendingNewest.After(state.SyncedUntil) {
+		state.SyncedUntil = state.PendingNewest
+	}
+	state.PendingTo = time.Time{}
+	state.PendingNewest = time.Time{}
+	if err := s.saveSyncState(ctx, opts.User, state); err != nil {
+		return result, err
+	}
+
+	result.SyncedUntil = state.SyncedUntil
+	return result, nil
+}
+
+// mergeScrobble adds a Last.fm scrobble to the history unless a matching
+// play is already there, reporting whether it was added
+func (s *Store) mergeScrobble(ctx context.Context, t lastfm.RecentTrack) (bool, error) {
+	exists, err := s.hasOverlapping(ctx, t.Artist, t.Track, t.Timestamp)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	// Last.fm does not report how long a scrobble was, so synced plays
+	// count towards play counts but not listening time
+	_, err = s.Add(ctx, Play{
+		Track:     t.Track,
+		Artist:    t.Artist,
+		Album:     t.Album,
+		StartedAt: t.Timestamp,
+		Scrobbled: true,
+		Source:    SourceLastFM,
+	})
+	return err == nil, err
+}
+
+// hasOverlapping reports whether the history holds a play of the same
+// track by the same artist that was underway at the given time. The daemon
+// records when a play started but scrobbles it part way through, so an
+// exact timestamp match is not enough.
+func (s *Store) hasOverlapping(ctx context.Context, artist, track string, at time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM plays
+			WHERE artist = ? COLLATE NOCASE AND track_name = ? COLLATE NOCASE
+				AND started_at <= ? + ?
+				AND started_at + MAX(played, duration) + ? >= ?
+		)
+	`
+
+	slack := int64(syncMatchSlack.Seconds())
+	ts := at.Unix()
+
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, query, artist, track, ts, slack, slack, ts).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to look up play: %w", err)
+	}
+
+	return exists, nil
+}
+
+// loadSyncState returns the sync progress for a user, which is empty if
+// the user has never been synced
+func (s *Store) loadSyncState(ctx context.Context, user string) (syncState, error) {
+	query := `
+		SELECT synced_until, pending_to, pending_newest
+		FROM sync_state
+		WHERE username = ?
+	`
+
+	var syncedUntil, pendingTo, pendingNewest int64
+	err := s.db.QueryRowContext(ctx, query, user).Scan(&syncedUntil, &pendingTo, &pendingNewest)
+	if errors.Is(err, sql.ErrNoRows) {
+		return syncState{}, nil
+	}
+	if err != nil {
+		return syncState{}, fmt.Errorf("failed to read sync state: %w", err)
+	}
+
+	return syncState{
+		SyncedUntil:   unixOrZero(syncedUntil),
+		PendingTo:     unixOrZero(pendingTo),
+		PendingNewest: unixOrZero(pendingNewest),
+	}, nil
+}
+
+// saveSyncState records the sync progress for a user
+func (s *Store) saveSyncState(ctx context.Context, user string, state syncState) error {
+	query := `
+		INSERT INTO sync_state (username, synced_until, pending_to, pending_newest, updated_at)
+		VALUES (?, ?, ?, ?, strftime('%s', 'now'))
+		ON CONFLICT (username) DO UPDATE SET
+			synced_until = excluded.synced_until,
+			pending_to = excluded.pending_to,
+			pending_newest = excluded.pending_newest,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := s.db.ExecContext(ctx, query, user,
+		zeroOrUnix(state.SyncedUntil),
+		zeroOrUnix(state.PendingTo),
+		zeroOrUnix(state.PendingNewest),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save sync state: %w", err)
+	}
+
+	return nil
+}
+
+// unixOrZero converts a stored Unix timestamp, mapping 0 to the zero time
+func unixOrZero(secs int64) time.Time {
+	if secs == 0 {
+		return time.Time{}
+	}
+	return time.Unix(secs, 0)
+}
+
+// zeroOrUnix converts a time for storage, mapping the zero time to 0
+func zeroOrUnix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
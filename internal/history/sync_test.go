@@ -0,0 +1,314 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jfmyers9/scribbles/pkg/lastfm"
+)
+
+// fakeRecentTracks serves a fixed scrobble history the way
+// user.getRecentTracks does: filtered by from/to, newest first, paged
+type fakeRecentTracks struct {
+	tracks   []lastfm.RecentTrack // Newest first
+	failPage int                  // Page number that returns an error (0 = never)
+	requests []lastfm.RecentTracksOptions
+}
+
+func (f *fakeRecentTracks) GetRecentTracks(ctx context.Context, user string, opts lastfm.RecentTracksOptions) (*lastfm.RecentTracksResponse, error) {
+	f.requests = append(f.requests, opts)
+	if opts.Page == f.failPage {
+		return nil, errors.New("connection reset")
+	}
+
+	var matching []lastfm.RecentTrack
+	for _, t := range f.tracks {
+		if !opts.From.IsZero() && t.Timestamp.Before(opts.From) {
+			continue
+		}
+		if !opts.To.IsZero() && t.Timestamp.After(opts.To) {
+			continue
+		}
+		matching = append(matching, t)
+	}
+
+	start := min((opts.Page-1)*opts.Limit, len(matching))
+	end := min(start+opts.Limit, len(matching))
+
+	return &lastfm.RecentTracksResponse{
+		Pagination: lastfm.Pagination{
+			Page:       opts.Page,
+			PerPage:    opts.Limit,
+			TotalPages: (len(matching) + opts.Limit - 1) / opts.Limit,
+			Total:      len(matching),
+		},
+		User:   user,
+		Tracks: matching[start:end],
+	}, nil
+}
+
+// scrobbleAt returns a recent track scrobbled n minutes after the base time
+func scrobbleAt(n int, artist, track string) lastfm.RecentTrack {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return lastfm.RecentTrack{
+		Artist:    artist,
+		Track:     track,
+		Album:     "Album",
+		Timestamp: base.Add(time.Duration(n) * time.Minute),
+	}
+}
+
+// syncFixture returns seven scrobbles, newest first
+func syncFixture() []lastfm.RecentTrack {
+	return []lastfm.RecentTrack{
+		scrobbleAt(60, "Björk", "Hyperballad"),
+		scrobbleAt(50, "Björk", "Joga"),
+		scrobbleAt(40, "Cher", "Believe"),
+		scrobbleAt(30, "Cher", "Strong Enough"),
+		scrobbleAt(20, "Daft Punk", "Digital Love"),
+		scrobbleAt(10, "Daft Punk", "One More Time"),
+		scrobbleAt(0, "Daft Punk", "Aerodynamic"),
+	}
+}
+
+func testSyncOptions() SyncOptions {
+	return SyncOptions{
+		User:     "rj",
+		PageSize: 3,
+		Interval: time.Millisecond,
+		Now:      time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestStoreSync(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	fetcher := &fakeRecentTracks{
+		tracks: append([]lastfm.RecentTrack{{Artist: "Cher", Track: "Believe", NowPlaying: true}}, syncFixture()...),
+	}
+
+	var progress []SyncProgress
+	opts := testSyncOptions()
+	opts.Progress = func(p SyncProgress) { progress = append(progress, p) }
+
+	result, err := store.Sync(ctx, fetcher, opts)
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+
+	if result.Fetched != 7 || result.Added != 7 || result.Duplicates != 0 {
+		t.Errorf("expected 7 fetched and added, got %+v", result)
+	}
+	if result.Resumed {
+		t.Error("expected a fresh run")
+	}
+	if want := scrobbleAt(60, "", "").Timestamp; !result.SyncedUntil.Equal(want) {
+		t.Errorf("expected synced until %v, got %v", want, result.SyncedUntil)
+	}
+	if len(progress) != 3 || progress[2].TotalPages != 3 || progress[2].Added != 7 {
+		t.Errorf("unexpected progress reports: %+v", progress)
+	}
+
+	plays, err := store.List(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("failed to list plays: %v", err)
+	}
+	if len(plays) != 7 {
+		t.Fatalf("expected 7 plays, got %d", len(plays))
+	}
+	got := plays[0]
+	if got.Track != "Hyperballad" || got.Source != SourceLastFM || !got.Scrobbled || got.Skipped {
+		t.Errorf("unexpected synced play: %+v", got)
+	}
+
+	for i, req := range fetcher.requests {
+		if !req.To.Equal(opts.Now) {
+			t.Errorf("request %d: expected fixed upper bound %v, got %v", i, opts.Now, req.To)
+		}
+	}
+}
+
+func TestStoreSync_Incremental(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	fetcher := &fakeRecentTracks{tracks: syncFixture()}
+
+	if _, err := store.Sync(ctx, fetcher, testSyncOptions()); err != nil {
+		t.Fatalf("first sync failed: %v", err)
+	}
+
+	// Two new scrobbles arrive
+	fetcher.tracks = append([]lastfm.RecentTrack{
+		scrobbleAt(80, "Cher", "Believe"),
+		scrobbleAt(70, "Björk", "Army of Me"),
+	}, fetcher.tracks...)
+	fetcher.requests = nil
+
+	result, err := store.Sync(ctx, fetcher, testSyncOptions())
+	if err != nil {
+		t.Fatalf("second sync failed: %v", err)
+	}
+
+	if want := scrobbleAt(60, "", "").Timestamp; !fetcher.requests[0].From.Equal(want) {
+		t.Errorf("expected sync from %v, got %v", want, fetcher.requests[0].From)
+	}
+	// The boundary scrobble is fetched again but not duplicated
+	if result.Added != 2 || result.Duplicates != 1 {
+		t.Errorf("expected 2 added and 1 duplicate, got %+v", result)
+	}
+	if want := scrobbleAt(80, "", "").Timestamp; !result.SyncedUntil.Equal(want) {
+		t.Errorf("expected synced until %v, got %v", want, result.SyncedUntil)
+	}
+
+	plays, err := store.List(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("failed to list plays: %v", err)
+	}
+	if len(plays) != 9 {
+		t.Errorf("expected 9 plays, got %d", len(plays))
+	}
+}
+
+func TestStoreSync_ResumesAfterInterruption(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	fetcher := &fakeRecentTracks{tracks: syncFixture(), failPage: 2}
+
+	result, err := store.Sync(ctx, fetcher, testSyncOptions())
+	if err == nil {
+		t.Fatal("expected sync to fail on page 2")
+	}
+	if result.Added != 3 {
+		t.Errorf("expected the first page to be merged, got %+v", result)
+	}
+
+	fetcher.failPage = 0
+	fetcher.requests = nil
+
+	result, err = store.Sync(ctx, fetcher, testSyncOptions())
+	if err != nil {
+		t.Fatalf("resumed sync failed: %v", err)
+	}
+
+	if !result.Resumed {
+		t.Error("expected the run to resume")
+	}
+	// Resuming starts at the oldest scrobble merged so far
+	if want := scrobbleAt(40, "", "").Timestamp; !fetcher.requests[0].To.Equal(want) {
+		t.Errorf("expected resume before %v, got %v", want, fetcher.requests[0].To)
+	}
+	if result.Added != 4 || result.Duplicates != 1 {
+		t.Errorf("expected 4 added and 1 duplicate, got %+v", result)
+	}
+	// The newest scrobble from the interrupted run is still the watermark
+	if want := scrobbleAt(60, "", "").Timestamp; !result.SyncedUntil.Equal(want) {
+		t.Errorf("expected synced until %v, got %v", want, result.SyncedUntil)
+	}
+
+	plays, err := store.List(ctx, Filter{})
+	if err != nil {
+		t.Fatalf("failed to list plays: %v", err)
+	}
+	if len(plays) != 7 {
+		t.Errorf("expected 7 plays, got %d", len(plays))
+	}
+}
+
+func TestStoreSync_SkipsLocalPlays(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+
+	// The daemon recorded Believe starting two minutes before it was
+	// scrobbled
+	believe := scrobbleAt(40, "Cher", "Believe")
+	_, err := store.Add(ctx, Play{
+		Track:     "believe",
+		Artist:    "CHER",
+		Duration:  239 * time.Second,
+		Played:    239 * time.Second,
+		StartedAt: believe.Timestamp.Add(-2 * time.Minute),
+		Scrobbled: true,
+		Source:    "apple-music",
+	})
+	if err != nil {
+		t.Fatalf("failed to add play: %v", err)
+	}
+
+	result, err := store.Sync(ctx, &fakeRecentTracks{tracks: syncFixture()}, testSyncOptions())
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+
+	if result.Added != 6 || result.Duplicates != 1 {
+		t.Errorf("expected 6 added and 1 duplicate, got %+v", result)
+	}
+
+	plays, err := store.List(ctx, Filter{Artist: "Cher"})
+	if err != nil {
+		t.Fatalf("failed to list plays: %v", err)
+	}
+	if len(plays) != 2 {
+		t.Fatalf("expected 2 Cher plays, got %d", len(plays))
+	}
+	if plays[0].Source != "apple-music" {
+		t.Errorf("expected the local play to be kept, got %+v", plays[0])
+	}
+}
+
+func TestStoreSync_Full(t *testing.T) {
+	store := createTestStore(t)
+	ctx := context.Background()
+	fetcher := &fakeRecentTracks{tracks: syncFixture()}
+
+	if _, err := store.Sync(ctx, fetcher, testSyncOptions()); err != nil {
+		t.Fatalf("first sync failed: %v", err)
+	}
+	fetcher.requests = nil
+
+	opts := testSyncOptions()
+	opts.Full = true
+	result, err := store.Sync(ctx, fetcher, opts)
+	if err != nil {
+		t.Fatalf("full sync failed: %v", err)
+	}
+
+	if !fetcher.requests[0].From.IsZero() {
+		t.Errorf("expected a full scan, got from %v", fetcher.requests[0].From)
+	}
+	if result.Added != 0 || result.Duplicates != 7 {
+		t.Errorf("expected every scrobble to be a duplicate, got %+v", result)
+	}
+}
+
+func TestStoreSync_RequiresUser(t *testing.T) {
+	store := createTestStore(t)
+
+	_, err := store.Sync(context.Background(), &fakeRecentTracks{}, SyncOptions{})
+	if err == nil {
+		t.Fatal("expected an error without a user")
+	}
+}
+
+func TestStoreSync_Cancelled(t *testing.T) {
+	store := createTestStore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	opts := testSyncOptions()
+	opts.Interval = time.Hour
+	opts.Progress = func(SyncProgress) { cancel() }
+
+	_, err := store.Sync(ctx, &fakeRecentTracks{tracks: syncFixture()}, opts)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	state, err := store.loadSyncState(context.Background(), "rj")
+	if err != nil {
+		t.Fatalf("failed to load sync state: %v", err)
+	}
+	if state.PendingTo.IsZero() {
+		t.Error("expected progress to be saved for the next run")
+	}
+}
@@ -0,0 +1,144 @@
+package listens
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvHeader is the header row written by writeCSV
+var csvHeader = []string{
+	"timestamp", "artist", "track", "album",
+	"duration_seconds", "played_seconds", "skipped", "source",
+}
+
+func writeCSV(w io.Writer, listens []Listen) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, l := range listens {
+		if err := cw.Write([]string{
+			l.Timestamp.Format(time.RFC3339),
+			l.Artist,
+			l.Track,
+			l.Album,
+			strconv.Itoa(int(l.Duration.Seconds())),
+			strconv.Itoa(int(l.Played.Seconds())),
+			strconv.FormatBool(l.Skipped),
+			l.Source,
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// readCSV reads CSV with a header row. Columns are matched by name, in any
+// order; unknown columns are ignored. "started_at" is accepted for
+// "timestamp" so `scribbles history -o csv` output can be imported.
+func readCSV(r io.Reader) ([]Listen, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "started_at" {
+			name = "timestamp"
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"timestamp", "artist", "track"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV is missing the %q column", required)
+		}
+	}
+
+	var listens []Listen
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		l := Listen{
+			Artist: field("artist"),
+			Track:  field("track"),
+			Album:  field("album"),
+			Source: field("source"),
+		}
+		if l.Timestamp, err = parseTimestamp(field("timestamp")); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if l.Duration, err = parseSeconds(field("duration_seconds")); err != nil {
+			return nil, fmt.Errorf("line %d: duration: %w", line, err)
+		}
+		if l.Played, err = parseSeconds(field("played_seconds")); err != nil {
+			return nil, fmt.Errorf("line %d: played: %w", line, err)
+		}
+		if s := field("skipped"); s != "" {
+			if l.Skipped, err = strconv.ParseBool(s); err != nil {
+				return nil, fmt.Errorf("line %d: invalid skipped value %q", line, s)
+			}
+		}
+		if err := l.validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		listens = append(listens, l)
+	}
+
+	return listens, nil
+}
+
+// parseTimestamp parses an RFC 3339 time or Unix seconds
+func parseTimestamp(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if unix, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q (must be RFC 3339 or Unix seconds)", s)
+	}
+	return t, nil
+}
+
+// parseSeconds parses a whole number of seconds; empty means zero
+func parseSeconds(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid number of seconds %q", s)
+	}
+	return time.Duration(n) * time.Second, nil
+}
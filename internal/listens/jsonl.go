@@ -0,0 +1,82 @@
+package listens
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// jsonListen is the JSON Lines representation of a listen
+type jsonListen struct {
+	Timestamp time.Time `json:"timestamp"`
+	Artist    string    `json:"artist"`
+	Track     string    `json:"track"`
+	Album     string    `json:"album,omitempty"`
+	Duration  int       `json:"duration_seconds,omitempty"`
+	Played    int       `json:"played_seconds,omitempty"`
+	Skipped   bool      `json:"skipped,omitempty"`
+	Source    string    `json:"source,omitempty"`
+}
+
+func writeJSONL(w io.Writer, listens []Listen) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, l := range listens {
+		if err := enc.Encode(jsonListen{
+			Timestamp: l.Timestamp,
+			Artist:    l.Artist,
+			Track:     l.Track,
+			Album:     l.Album,
+			Duration:  int(l.Duration.Seconds()),
+			Played:    int(l.Played.Seconds()),
+			Skipped:   l.Skipped,
+			Source:    l.Source,
+		}); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func readJSONL(r io.Reader) ([]Listen, error) {
+	var listens []Listen
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var jl jsonListen
+		if err := json.Unmarshal(data, &jl); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		l := Listen{
+			Artist:    jl.Artist,
+			Track:     jl.Track,
+			Album:     jl.Album,
+			Duration:  time.Duration(jl.Duration) * time.Second,
+			Timestamp: jl.Timestamp,
+			Played:    time.Duration(jl.Played) * time.Second,
+			Skipped:   jl.Skipped,
+			Source:    jl.Source,
+		}
+		if err := l.validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		listens = append(listens, l)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return listens, nil
+}
@@ -0,0 +1,146 @@
+package listens
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// lbListen is a ListenBrainz listen, as returned by its API and user data
+// export
+type lbListen struct {
+	ListenedAt    int64           `json:"listened_at"`
+	TrackMetadata lbTrackMetadata `json:"track_metadata"`
+}
+
+type lbTrackMetadata struct {
+	ArtistName     string           `json:"artist_name"`
+	TrackName      string           `json:"track_name"`
+	ReleaseName    string           `json:"release_name,omitempty"`
+	AdditionalInfo lbAdditionalInfo `json:"additional_info"`
+}
+
+type lbAdditionalInfo struct {
+	DurationMs       int64  `json:"duration_ms,omitempty"`
+	Duration         int64  `json:"duration,omitempty"` // Seconds; older clients
+	MediaPlayer      string `json:"media_player,omitempty"`
+	SubmissionClient string `json:"submission_client,omitempty"`
+}
+
+// lbPayload is the submit-listens body, which wraps listens in "payload"
+type lbPayload struct {
+	Payload []lbListen `json:"payload"`
+}
+
+// writeListenBrainz writes listens as a JSON array in the format of the
+// ListenBrainz user data export
+func writeListenBrainz(w io.Writer, listens []Listen) error {
+	out := make([]lbListen, len(listens))
+	for i, l := range listens {
+		out[i] = lbListen{
+			ListenedAt: l.Timestamp.Unix(),
+			TrackMetadata: lbTrackMetadata{
+				ArtistName:  l.Artist,
+				TrackName:   l.Track,
+				ReleaseName: l.Album,
+				AdditionalInfo: lbAdditionalInfo{
+					DurationMs:       l.Duration.Milliseconds(),
+					MediaPlayer:      l.Source,
+					SubmissionClient: "scribbles",
+				},
+			},
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// readListenBrainz reads a JSON array of listens, a stream of listens one
+// per line (the newer export layout), or submit-listens bodies with a
+// "payload" array
+func readListenBrainz(r io.Reader) ([]Listen, error) {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []lbListen
+	dec := json.NewDecoder(br)
+	if first == '[' {
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("invalid ListenBrainz JSON: %w", err)
+		}
+	} else {
+		for n := 1; ; n++ {
+			var obj json.RawMessage
+			if err := dec.Decode(&obj); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("invalid ListenBrainz JSON (object %d): %w", n, err)
+			}
+
+			if bytes.Contains(obj, []byte(`"payload"`)) {
+				var p lbPayload
+				if err := json.Unmarshal(obj, &p); err == nil && p.Payload != nil {
+					raw = append(raw, p.Payload...)
+					continue
+				}
+			}
+
+			var l lbListen
+			if err := json.Unmarshal(obj, &l); err != nil {
+				return nil, fmt.Errorf("invalid ListenBrainz JSON (object %d): %w", n, err)
+			}
+			raw = append(raw, l)
+		}
+	}
+
+	listens := make([]Listen, 0, len(raw))
+	for i, lb := range raw {
+		md := lb.TrackMetadata
+		l := Listen{
+			Artist:   md.ArtistName,
+			Track:    md.TrackName,
+			Album:    md.ReleaseName,
+			Duration: time.Duration(md.AdditionalInfo.DurationMs) * time.Millisecond,
+			Source:   md.AdditionalInfo.MediaPlayer,
+		}
+		if l.Duration == 0 {
+			l.Duration = time.Duration(md.AdditionalInfo.Duration) * time.Second
+		}
+		if lb.ListenedAt > 0 {
+			l.Timestamp = time.Unix(lb.ListenedAt, 0)
+		}
+		if err := l.validate(); err != nil {
+			return nil, fmt.Errorf("listen %d: %w", i+1, err)
+		}
+		listens = append(listens, l)
+	}
+
+	return listens, nil
+}
+
+// peekNonSpace skips leading whitespace and returns the next byte without
+// consuming it
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, br.UnreadByte()
+	}
+}
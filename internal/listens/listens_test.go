@@ -0,0 +1,226 @@
+package listens
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testListens() []Listen {
+	ts := time.Unix(1772390400, 0).UTC()
+	return []Listen{
+		{
+			Artist:    "The Beatles",
+			Track:     "Yesterday",
+			Album:     "Help!",
+			Duration:  125 * time.Second,
+			Timestamp: ts,
+			Played:    125 * time.Second,
+			Source:    "apple-music",
+		},
+		{
+			Artist:    "Cher",
+			Track:     "Believe, \"Remix\"",
+			Duration:  239 * time.Second,
+			Timestamp: ts.Add(125 * time.Second),
+			Played:    12 * time.Second,
+			Skipped:   true,
+			Source:    "apple-music",
+		},
+	}
+}
+
+func readFixture(t *testing.T, name string, format Format) []Listen {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	listens, err := Read(f, format)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", name, err)
+	}
+	return listens
+}
+
+func TestRoundTrip(t *testing.T) {
+	// Fields each format does not carry are expected to come back zero
+	tests := []struct {
+		format Format
+		strip  func(*Listen)
+	}{
+		{FormatCSV, func(*Listen) {}},
+		{FormatJSONL, func(*Listen) {}},
+		{FormatListenBrainz, func(l *Listen) { l.Played = 0; l.Skipped = false }},
+		{FormatScrobblerLog, func(l *Listen) { l.Played = 0; l.Source = "scribbles" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, tt.format, testListens()); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+
+			got, err := Read(&buf, tt.format)
+			if err != nil {
+				t.Fatalf("Read: %v\n%s", err, buf.String())
+			}
+
+			want := testListens()
+			if len(got) != len(want) {
+				t.Fatalf("expected %d listens, got %d", len(want), len(got))
+			}
+			for i := range want {
+				tt.strip(&want[i])
+				if !got[i].Timestamp.Equal(want[i].Timestamp) {
+					t.Errorf("listen %d: expected timestamp %v, got %v", i, want[i].Timestamp, got[i].Timestamp)
+				}
+				got[i].Timestamp = want[i].Timestamp
+				if got[i] != want[i] {
+					t.Errorf("listen %d: expected %+v, got %+v", i, want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadScrobblerLog(t *testing.T) {
+	listens := readFixture(t, "rockbox.scrobbler.log", FormatScrobblerLog)
+	if len(listens) != 3 {
+		t.Fatalf("expected 3 listens, got %d", len(listens))
+	}
+
+	first := listens[0]
+	if first.Artist != "The Beatles" || first.Album != "Help!" || first.Track != "Yesterday" || first.Duration != 125*time.Second {
+		t.Errorf("unexpected first listen: %+v", first)
+	}
+	if !first.Timestamp.Equal(time.Unix(1772390400, 0)) {
+		t.Errorf("expected UTC timestamp, got %v", first.Timestamp)
+	}
+	if first.Source != "Rockbox sansaclipplus $Revision$" {
+		t.Errorf("expected the client header as source, got %q", first.Source)
+	}
+	if !listens[1].Skipped || listens[0].Skipped {
+		t.Error("expected only the S-rated listen to be skipped")
+	}
+	if listens[2].Album != "" || listens[2].Track != "Roads" {
+		t.Errorf("expected a listen without album or MBID to parse, got %+v", listens[2])
+	}
+}
+
+func TestReadScrobblerLog_UnknownTimeZone(t *testing.T) {
+	log := "#AUDIOSCROBBLER/1.1\n#TZ/UNKNOWN\n#CLIENT/Rockbox\nCher\tBelieve\tBelieve\t1\t239\tL\t1772390400\t\n"
+
+	listens, err := Read(strings.NewReader(log), FormatScrobblerLog)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	want := time.Date(2026, 3, 1, 18, 40, 0, 0, time.Local)
+	if len(listens) != 1 || !listens[0].Timestamp.Equal(want) {
+		t.Errorf("expected wall-clock time %v, got %+v", want, listens)
+	}
+}
+
+func TestReadScrobblerLog_Invalid(t *testing.T) {
+	for name, log := range map[string]string{
+		"too few fields": "Cher\tBelieve\tBelieve\n",
+		"bad rating":     "Cher\tBelieve\tBelieve\t1\t239\tX\t1772390400\t\n",
+		"bad timestamp":  "Cher\tBelieve\tBelieve\t1\t239\tL\tyesterday\t\n",
+		"missing artist": "\tBelieve\tBelieve\t1\t239\tL\t1772390400\t\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Read(strings.NewReader(log), FormatScrobblerLog); err == nil || !strings.Contains(err.Error(), "line 1") {
+				t.Errorf("expected a line 1 error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReadListenBrainz(t *testing.T) {
+	listens := readFixture(t, "listenbrainz.json", FormatListenBrainz)
+	if len(listens) != 2 {
+		t.Fatalf("expected 2 listens, got %d", len(listens))
+	}
+
+	if l := listens[0]; l.Artist != "The Beatles" || l.Album != "Help!" || l.Duration != 125*time.Second || l.Source != "Apple Music" {
+		t.Errorf("unexpected first listen: %+v", l)
+	}
+	if l := listens[1]; l.Track != "Believe" || l.Duration != 239*time.Second || !l.Timestamp.Equal(time.Unix(1772390525, 0)) {
+		t.Errorf("expected duration in seconds to be accepted, got %+v", l)
+	}
+}
+
+func TestReadListenBrainz_Streams(t *testing.T) {
+	inputs := map[string]string{
+		"json lines": `{"listened_at": 1772390400, "track_metadata": {"artist_name": "Cher", "track_name": "Believe"}}
+{"listened_at": 1772390525, "track_metadata": {"artist_name": "Cher", "track_name": "Strong Enough"}}`,
+		"submit payload": `{"listen_type": "import", "payload": [
+			{"listened_at": 1772390400, "track_metadata": {"artist_name": "Cher", "track_name": "Believe"}},
+			{"listened_at": 1772390525, "track_metadata": {"artist_name": "Cher", "track_name": "Strong Enough"}}
+		]}`,
+	}
+
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			listens, err := Read(strings.NewReader(input), FormatListenBrainz)
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if len(listens) != 2 || listens[1].Track != "Strong Enough" {
+				t.Errorf("expected 2 listens, got %+v", listens)
+			}
+		})
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	input := "Artist,Track,started_at,extra\nCher,Believe,1772390400,x\nThe Beatles,Yesterday,2026-03-01T18:42:05Z,y\n"
+
+	listens, err := Read(strings.NewReader(input), FormatCSV)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(listens) != 2 {
+		t.Fatalf("expected 2 listens, got %d", len(listens))
+	}
+	if !listens[0].Timestamp.Equal(time.Unix(1772390400, 0)) || !listens[1].Timestamp.Equal(time.Unix(1772390525, 0)) {
+		t.Errorf("expected Unix and RFC 3339 timestamps, got %v and %v", listens[0].Timestamp, listens[1].Timestamp)
+	}
+
+	if _, err := Read(strings.NewReader("artist,track\nCher,Believe\n"), FormatCSV); err == nil {
+		t.Error("expected error for missing timestamp column")
+	}
+	if _, err := Read(strings.NewReader("artist,track,timestamp\nCher,,1772390400\n"), FormatCSV); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected line 2 error for missing track, got %v", err)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := map[string]Format{
+		"plays.csv":                    FormatCSV,
+		"plays.jsonl":                  FormatJSONL,
+		"export.ndjson":                FormatJSONL,
+		"listens.json":                 FormatListenBrainz,
+		"/Volumes/IPOD/.scrobbler.log": FormatScrobblerLog,
+	}
+	for path, want := range tests {
+		if got, err := DetectFormat(path); err != nil || got != want {
+			t.Errorf("DetectFormat(%q) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+
+	if _, err := DetectFormat("plays.txt"); err == nil {
+		t.Error("expected error for unknown extension")
+	}
+	if f, err := ParseFormat("ListenBrainz"); err != nil || f != FormatListenBrainz {
+		t.Errorf("ParseFormat(ListenBrainz) = %q, %v", f, err)
+	}
+}
@@ -0,0 +1,132 @@
+package listens
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The .scrobbler.log format (Audioscrobbler portable player log 1.1) has
+// a few header lines starting with '#' followed by one tab-separated line
+// per listen:
+//
+//	ARTIST  ALBUM  TITLE  TRACKNUM  LENGTH  RATING  TIMESTAMP  MBID
+//
+// LENGTH is in seconds and RATING is "L" (listened) or "S" (skipped).
+// TIMESTAMP is Unix seconds, or local wall-clock time encoded as Unix
+// seconds when the header says "#TZ/UNKNOWN", as on players without a
+// time zone setting.
+const (
+	scrobblerLogHeader = "#AUDIOSCROBBLER/1.1"
+	scrobblerLogFields = 8
+)
+
+func writeScrobblerLog(w io.Writer, listens []Listen) error {
+	bw := bufio.NewWriter(w)
+	if _, err := fmt.Fprintf(bw, "%s\n#TZ/UTC\n#CLIENT/scribbles\n", scrobblerLogHeader); err != nil {
+		return err
+	}
+
+	for _, l := range listens {
+		rating := "L"
+		if l.Skipped {
+			rating = "S"
+		}
+		fields := []string{
+			logField(l.Artist),
+			logField(l.Album),
+			logField(l.Track),
+			"",
+			strconv.Itoa(int(l.Duration.Seconds())),
+			rating,
+			strconv.FormatInt(l.Timestamp.Unix(), 10),
+			"",
+		}
+		if _, err := fmt.Fprintln(bw, strings.Join(fields, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// logField strips the tabs and newlines the format cannot represent
+func logField(s string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(s)
+}
+
+func readScrobblerLog(r io.Reader) ([]Listen, error) {
+	var listens []Listen
+	localTime := false
+	source := "scrobbler-log"
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if text == "" {
+			continue
+		}
+		if strings.HasPrefix(text, "#") {
+			switch {
+			case strings.EqualFold(text, "#TZ/UNKNOWN"):
+				localTime = true
+			case strings.HasPrefix(text, "#CLIENT/"):
+				source = strings.TrimSpace(strings.TrimPrefix(text, "#CLIENT/"))
+			}
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) < scrobblerLogFields-1 {
+			return nil, fmt.Errorf("line %d: expected %d tab-separated fields, got %d", line, scrobblerLogFields, len(fields))
+		}
+
+		length, err := parseSeconds(fields[4])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: length: %w", line, err)
+		}
+
+		unix, err := strconv.ParseInt(fields[6], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid timestamp %q", line, fields[6])
+		}
+		timestamp := time.Unix(unix, 0)
+		if localTime {
+			u := timestamp.UTC()
+			timestamp = time.Date(u.Year(), u.Month(), u.Day(), u.Hour(), u.Minute(), u.Second(), 0, time.Local)
+		}
+
+		var skipped bool
+		switch strings.ToUpper(fields[5]) {
+		case "L":
+		case "S":
+			skipped = true
+		default:
+			return nil, fmt.Errorf("line %d: invalid rating %q (must be L or S)", line, fields[5])
+		}
+
+		l := Listen{
+			Artist:    fields[0],
+			Album:     fields[1],
+			Track:     fields[2],
+			Duration:  length,
+			Timestamp: timestamp,
+			Skipped:   skipped,
+			Source:    source,
+		}
+		if err := l.validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		listens = append(listens, l)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return listens, nil
+}
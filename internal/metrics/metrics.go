@@ -0,0 +1,325 @@
+// Package metrics keeps counters, gauges and histograms and writes them in
+// the Prometheus text exposition format, for scraping the daemon's health
+// from its HTTP API.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBuckets are histogram buckets, in seconds, suited to request
+// latencies: 5ms to 10s
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// ContentType is the media type of the text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Registry holds metrics and writes them in registration order. The zero
+// value is ready to use.
+type Registry struct {
+	mu       sync.Mutex
+	families []family
+}
+
+// family is a named metric and the samples it currently has
+type family struct {
+	name    string
+	help    string
+	typ     string
+	collect func() []sample
+}
+
+// sample is a single line of a metric family
+type sample struct {
+	suffix string // Appended to the family name, e.g. "_bucket"
+	labels string // Rendered label set, e.g. {backend="lastfm"}
+	value  float64
+}
+
+func (r *Registry) register(name, help, typ string, collect func() []sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.families = append(r.families, family{name: name, help: help, typ: typ, collect: collect})
+}
+
+// Counter registers a counter
+func (r *Registry) Counter(name, help string) *Counter {
+	c := &Counter{}
+	r.register(name, help, "counter", func() []sample {
+		return []sample{{value: float64(c.Value())}}
+	})
+	return c
+}
+
+// CounterVec registers a counter partitioned by one label
+func (r *Registry) CounterVec(name, help, label string) *CounterVec {
+	v := &CounterVec{counters: make(map[string]*Counter)}
+	r.register(name, help, "counter", func() []sample {
+		v.mu.Lock()
+		defer v.mu.Unlock()
+
+		var samples []sample
+		for _, value := range slices.Sorted(maps.Keys(v.counters)) {
+			samples = append(samples, sample{
+				labels: labelSet(label, value),
+				value:  float64(v.counters[value].Value()),
+			})
+		}
+		return samples
+	})
+	return v
+}
+
+// CounterFunc registers a counter whose value is read from f when the
+// metrics are written
+func (r *Registry) CounterFunc(name, help string, f func() float64) {
+	r.register(name, help, "counter", func() []sample {
+		return []sample{{value: f()}}
+	})
+}
+
+// CounterVecFunc registers a counter partitioned by one label, whose values
+// by label value are read from f when the metrics are written
+func (r *Registry) CounterVecFunc(name, help, label string, f func() map[string]float64) {
+	r.register(name, help, "counter", func() []sample {
+		values := f()
+		var samples []sample
+		for _, value := range slices.Sorted(maps.Keys(values)) {
+			samples = append(samples, sample{labels: labelSet(label, value), value: values[value]})
+		}
+		return samples
+	})
+}
+
+// GaugeFunc registers a gauge whose value is read from f when the metrics
+// are written
+func (r *Registry) GaugeFunc(name, help string, f func() float64) {
+	r.register(name, help, "gauge", func() []sample {
+		return []sample{{value: f()}}
+	})
+}
+
+// Histogram registers a histogram with the given upper bucket bounds
+func (r *Registry) Histogram(name, help string, buckets []float64) *Histogram {
+	h := newHistogram(buckets)
+	r.register(name, help, "histogram", func() []sample {
+		return h.samples("", "")
+	})
+	return h
+}
+
+// HistogramVec registers a histogram partitioned by one label
+func (r *Registry) HistogramVec(name, help, label string, buckets []float64) *HistogramVec {
+	v := &HistogramVec{buckets: buckets, histograms: make(map[string]*Histogram)}
+	r.register(name, help, "histogram", func() []sample {
+		v.mu.Lock()
+		defer v.mu.Unlock()
+
+		var samples []sample
+		for _, value := range slices.Sorted(maps.Keys(v.histograms)) {
+			samples = append(samples, v.histograms[value].samples(label, value)...)
+		}
+		return samples
+	})
+	return v
+}
+
+// WriteTo writes every metric in the text exposition format
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	families := slices.Clone(r.families)
+	r.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, f := range families {
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.name, f.typ)
+		for _, s := range f.collect() {
+			fmt.Fprintf(bw, "%s%s%s %s\n", f.name, s.suffix, s.labels, formatValue(s.value))
+		}
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ServeHTTP serves the metrics for a Prometheus scrape
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = r.WriteTo(w)
+}
+
+// Counter is a count that only goes up
+type Counter struct {
+	n atomic.Uint64
+}
+
+// Inc adds one to the counter
+func (c *Counter) Inc() {
+	c.n.Add(1)
+}
+
+// Add adds n to the counter
+func (c *Counter) Add(n uint64) {
+	c.n.Add(n)
+}
+
+// Value returns the current count
+func (c *Counter) Value() uint64 {
+	return c.n.Load()
+}
+
+// CounterVec is a set of counters partitioned by a label
+type CounterVec struct {
+	mu       sync.Mutex
+	counters map[string]*Counter
+}
+
+// With returns the counter for a label value, creating it at zero
+func (v *CounterVec) With(value string) *Counter {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	c, ok := v.counters[value]
+	if !ok {
+		c = &Counter{}
+		v.counters[value] = c
+	}
+	return c
+}
+
+// Histogram counts observations into buckets
+type Histogram struct {
+	mu     sync.Mutex
+	bounds []float64
+	counts []uint64 // Per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	bounds := slices.Sorted(slices.Values(buckets))
+	return &Histogram{bounds: bounds, counts: make([]uint64, len(bounds))}
+}
+
+// Observe records a value, such as a latency in seconds
+func (h *Histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if i, _ := slices.BinarySearch(h.bounds, v); i < len(h.bounds) {
+		h.counts[i]++
+	}
+	h.sum += v
+	h.count++
+}
+
+// samples returns the histogram's cumulative buckets, sum and count,
+// labelled with label=value if label is set
+func (h *Histogram) samples(label, value string) []sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	pairs := func(extra ...string) string {
+		if label == "" {
+			return labelSet(extra...)
+		}
+		return labelSet(append([]string{label, value}, extra...)...)
+	}
+
+	samples := make([]sample, 0, len(h.bounds)+3)
+	var cumulative uint64
+	for i, bound := range h.bounds {
+		cumulative += h.counts[i]
+		samples = append(samples, sample{suffix: "_bucket", labels: pairs("le", formatValue(bound)), value: float64(cumulative)})
+	}
+	samples = append(samples,
+		sample{suffix: "_bucket", labels: pairs("le", "+Inf"), value: float64(h.count)},
+		sample{suffix: "_sum", labels: pairs(), value: h.sum},
+		sample{suffix: "_count", labels: pairs(), value: float64(h.count)},
+	)
+	return samples
+}
+
+// HistogramVec is a set of histograms partitioned by a label
+type HistogramVec struct {
+	mu         sync.Mutex
+	buckets    []float64
+	histograms map[string]*Histogram
+}
+
+// With returns the histogram for a label value, creating it empty
+func (v *HistogramVec) With(value string) *Histogram {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	h, ok := v.histograms[value]
+	if !ok {
+		h = newHistogram(v.buckets)
+		v.histograms[value] = h
+	}
+	return h
+}
+
+// labelSet renders name/value pairs as a label set, or "" for none
+func labelSet(pairs ...string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, "%s=\"%s\"", pairs[i], labelEscaper.Replace(pairs[i+1]))
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+// formatValue formats a sample value as the exposition format expects
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// countingWriter counts the bytes written through it, for WriteTo
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	var r Registry
+	polls := r.Counter("test_polls_total", "Polls of the player")
+	scrobbles := r.CounterVec("test_scrobbles_total", "Scrobbles by backend", "backend")
+	r.GaugeFunc("test_queue_pending", "Pending scrobbles", func() float64 { return 3 })
+	r.CounterVecFunc("test_dropped_total", "Dropped events", "subscriber", func() map[string]float64 {
+		return map[string]float64{"tui": 1, "http": 2}
+	})
+	latency := r.Histogram("test_latency_seconds", "Latency", []float64{1, 0.1})
+
+	polls.Inc()
+	polls.Add(2)
+	scrobbles.With("lastfm").Inc()
+	scrobbles.With(`say "hi"`)
+	latency.Observe(0.05)
+	latency.Observe(0.1)
+	latency.Observe(5)
+
+	var sb strings.Builder
+	if _, err := r.WriteTo(&sb); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	want := `# HELP test_polls_total Polls of the player
+# TYPE test_polls_total counter
+test_polls_total 3
+# HELP test_scrobbles_total Scrobbles by backend
+# TYPE test_scrobbles_total counter
+test_scrobbles_total{backend="lastfm"} 1
+test_scrobbles_total{backend="say \"hi\""} 0
+# HELP test_queue_pending Pending scrobbles
+# TYPE test_queue_pending gauge
+test_queue_pending 3
+# HELP test_dropped_total Dropped events
+# TYPE test_dropped_total counter
+test_dropped_total{subscriber="http"} 2
+test_dropped_total{subscriber="tui"} 1
+# HELP test_latency_seconds Latency
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{le="0.1"} 2
+test_latency_seconds_bucket{le="1"} 2
+test_latency_seconds_bucket{le="+Inf"} 3
+test_latency_seconds_sum 5.15
+test_latency_seconds_count 3
+`
+	if got := sb.String(); got != want {
+		t.Errorf("unexpected exposition:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestHistogramVec(t *testing.T) {
+	var r Registry
+	latency := r.HistogramVec("test_request_seconds", "Request latency", "backend", []float64{1})
+	latency.With("lastfm").Observe(0.5)
+
+	var sb strings.Builder
+	if _, err := r.WriteTo(&sb); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	for _, want := range []string{
+		`test_request_seconds_bucket{backend="lastfm",le="1"} 1`,
+		`test_request_seconds_bucket{backend="lastfm",le="+Inf"} 1`,
+		`test_request_seconds_sum{backend="lastfm"} 0.5`,
+		`test_request_seconds_count{backend="lastfm"} 1`,
+	} {
+		if !strings.Contains(sb.String(), want+"\n") {
+			t.Errorf("expected %q in:\n%s", want, sb.String())
+		}
+	}
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	var r Registry
+	r.GaugeFunc("test_unknown", "A gauge that failed to read", func() float64 { return math.NaN() })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("expected %q, got %q", ContentType, ct)
+	}
+	if !strings.Contains(rec.Body.String(), "test_unknown NaN\n") {
+		t.Errorf("expected a NaN sample, got:\n%s", rec.Body.String())
+	}
+}
@@ -2,9 +2,14 @@ package music
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrControlsUnsupported is returned by playback controls of players
+// scribbles can only listen to
+var ErrControlsUnsupported = errors.New("playback controls are not supported by this player")
+
 // Track represents a music track with its metadata and current state
 type Track struct {
 	Name     string        // Track name/title
@@ -61,3 +66,12 @@ type Client interface {
 	// PreviousTrack goes to the previous track
 	PreviousTrack(ctx context.Context) error
 }
+
+// Watcher is implemented by clients whose player pushes changes, such as
+// MPD's idle command, so that they need not be polled
+type Watcher interface {
+	// Watch sends the current track (nil if stopped) on tracks, then sends
+	// it again each time the player changes track or state. It blocks until
+	// ctx is cancelled or watching fails.
+	Watch(ctx context.Context, tracks chan<- *Track) error
+}
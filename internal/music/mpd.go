@@ -0,0 +1,347 @@
+package music
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// DefaultMPDAddress is where MPD listens by default
+const DefaultMPDAddress = "localhost:6600"
+
+// MPDClient implements the Client interface for Music Player Daemon, and
+// servers speaking its protocol such as Mopidy, over TCP or a Unix socket
+type MPDClient struct {
+	address  string // host:port, or the path of a Unix socket
+	password string
+	dialer   net.Dialer
+}
+
+// NewMPDClient creates a client for the MPD server at address, a host:port
+// or the path of a Unix socket. An empty password skips authentication.
+func NewMPDClient(address, password string) *MPDClient {
+	if address == "" {
+		address = DefaultMPDAddress
+	}
+	return &MPDClient{address: address, password: password}
+}
+
+// mpdConn is a connection to MPD that has read the server's greeting
+type mpdConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// dial connects to MPD and authenticates. The connection's deadline is
+// ctx's deadline, if it has one.
+func (c *MPDClient) dial(ctx context.Context) (*mpdConn, error) {
+	network := "tcp"
+	if strings.HasPrefix(c.address, "/") || strings.HasPrefix(c.address, "@") {
+		network = "unix"
+	}
+
+	conn, err := c.dialer.DialContext(ctx, network, c.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MPD at %s: %w", c.address, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	m := &mpdConn{conn: conn, r: bufio.NewReader(conn)}
+	greeting, err := m.r.ReadString('\n')
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to read MPD greeting: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "OK MPD ") {
+		_ = conn.Close()
+		return nil, fmt.Errorf("unexpected MPD greeting %q", strings.TrimSpace(greeting))
+	}
+
+	if c.password != "" {
+		if _, err := m.command("password", c.password); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *mpdConn) close() error {
+	return m.conn.Close()
+}
+
+// command sends a command and returns the key/value pairs of its response
+func (m *mpdConn) command(name string, args ...string) (map[string]string, error) {
+	line := name
+	for _, arg := range args {
+		line += " " + quoteMPD(arg)
+	}
+	if _, err := fmt.Fprintf(m.conn, "%s\n", line); err != nil {
+		return nil, fmt.Errorf("failed to send MPD command %s: %w", name, err)
+	}
+
+	pairs := make(map[string]string)
+	for {
+		line, err := m.r.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("failed to read MPD response to %s: %w", name, err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "OK":
+			return pairs, nil
+		case strings.HasPrefix(line, "ACK "):
+			return nil, parseMPDError(line)
+		}
+
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, fmt.Errorf("malformed MPD response line %q", line)
+		}
+		if _, seen := pairs[key]; !seen {
+			pairs[key] = value
+		}
+	}
+}
+
+// currentTrack queries the player status and current song
+func (m *mpdConn) currentTrack() (*Track, error) {
+	status, err := m.command("status")
+	if err != nil {
+		return nil, err
+	}
+	song, err := m.command("currentsong")
+	if err != nil {
+		return nil, err
+	}
+	return parseMPDTrack(status, song)
+}
+
+// parseMPDError turns an ACK line, e.g. "ACK [50@0] {play} No such song",
+// into an error
+func parseMPDError(line string) error {
+	msg := strings.TrimPrefix(line, "ACK ")
+	if _, rest, ok := strings.Cut(msg, "] "); ok {
+		msg = rest
+	}
+	if cmd, rest, ok := strings.Cut(msg, "} "); ok && strings.HasPrefix(cmd, "{") {
+		cmd = strings.TrimPrefix(cmd, "{")
+		if cmd == "" {
+			return fmt.Errorf("mpd: %s", rest)
+		}
+		return fmt.Errorf("mpd %s: %s", cmd, rest)
+	}
+	return fmt.Errorf("mpd: %s", msg)
+}
+
+// quoteMPD quotes a command argument
+func quoteMPD(arg string) string {
+	arg = strings.ReplaceAll(arg, `\`, `\\`)
+	arg = strings.ReplaceAll(arg, `"`, `\"`)
+	return `"` + arg + `"`
+}
+
+// parseMPDTrack builds a track from the responses to status and
+// currentsong. It returns nil when playback is stopped.
+func parseMPDTrack(status, song map[string]string) (*Track, error) {
+	var state PlayState
+	switch status["state"] {
+	case "play":
+		state = StatePlaying
+	case "pause":
+		state = StatePaused
+	case "stop", "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown MPD state: %q", status["state"])
+	}
+
+	// "time" is the deprecated elapsed:duration in whole seconds
+	timeElapsed, timeTotal, _ := strings.Cut(status["time"], ":")
+
+	duration, err := firstSeconds(status["duration"], song["duration"], song["Time"], timeTotal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse duration: %w", err)
+	}
+	position, err := firstSeconds(status["elapsed"], timeElapsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse position: %w", err)
+	}
+
+	name := song["Title"]
+	if name == "" {
+		// Untagged files and streams
+		name = song["Name"]
+	}
+	if name == "" {
+		name = path.Base(song["file"])
+	}
+	artist := song["Artist"]
+	if artist == "" {
+		artist = song["AlbumArtist"]
+	}
+
+	return &Track{
+		Name:     name,
+		Artist:   artist,
+		Album:    song["Album"],
+		Duration: secondsToDuration(duration),
+		Position: secondsToDuration(position),
+		State:    state,
+	}, nil
+}
+
+// firstSeconds parses the first non-empty value as seconds, or returns 0
+func firstSeconds(values ...string) (float64, error) {
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid seconds %q: %w", v, err)
+		}
+		return seconds, nil
+	}
+	return 0, nil
+}
+
+// run connects, sends a single command and disconnects
+func (c *MPDClient) run(ctx context.Context, name string, args ...string) error {
+	m, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = m.close() }()
+
+	_, err = m.command(name, args...)
+	return err
+}
+
+// IsRunning checks if an MPD server answers at the configured address
+func (c *MPDClient) IsRunning(ctx context.Context) (bool, error) {
+	m, err := c.dial(ctx)
+	if err != nil {
+		return false, nil
+	}
+	defer func() { _ = m.close() }()
+
+	if _, err := m.command("ping"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetCurrentTrack returns the playing or paused song, or nil if MPD is stopped
+func (c *MPDClient) GetCurrentTrack(ctx context.Context) (*Track, error) {
+	m, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = m.close() }()
+
+	return m.currentTrack()
+}
+
+// Watch sends the current track on tracks, and sends it again each time
+// the player changes song or state or seeks. Rather than polling, it
+// waits on MPD's "idle player" command. It returns when ctx is cancelled
+// or the connection fails.
+func (c *MPDClient) Watch(ctx context.Context, tracks chan<- *Track) error {
+	m, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = m.close() }()
+
+	// Unblock a pending idle when ctx is cancelled
+	stop := context.AfterFunc(ctx, func() { _ = m.close() })
+	defer stop()
+
+	for {
+		track, err := m.currentTrack()
+		if err != nil {
+			return watchErr(ctx, err)
+		}
+
+		select {
+		case tracks <- track:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if _, err := m.command("idle", "player"); err != nil {
+			return watchErr(ctx, err)
+		}
+	}
+}
+
+// watchErr reports cancellation rather than the error it caused
+func watchErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// Play starts or resumes playback
+func (c *MPDClient) Play(ctx context.Context) error {
+	if err := c.run(ctx, "play"); err != nil {
+		return fmt.Errorf("failed to play: %w", err)
+	}
+	return nil
+}
+
+// Pause pauses playback
+func (c *MPDClient) Pause(ctx context.Context) error {
+	if err := c.run(ctx, "pause", "1"); err != nil {
+		return fmt.Errorf("failed to pause: %w", err)
+	}
+	return nil
+}
+
+// PlayPause pauses if playing, and plays otherwise
+func (c *MPDClient) PlayPause(ctx context.Context) error {
+	m, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = m.close() }()
+
+	status, err := m.command("status")
+	if err != nil {
+		return fmt.Errorf("failed to playpause: %w", err)
+	}
+	if status["state"] == "play" {
+		_, err = m.command("pause", "1")
+	} else {
+		_, err = m.command("play")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to playpause: %w", err)
+	}
+	return nil
+}
+
+// NextTrack skips to the next song in the queue
+func (c *MPDClient) NextTrack(ctx context.Context) error {
+	if err := c.run(ctx, "next"); err != nil {
+		return fmt.Errorf("failed to skip to next track: %w", err)
+	}
+	return nil
+}
+
+// PreviousTrack goes to the previous song in the queue
+func (c *MPDClient) PreviousTrack(ctx context.Context) error {
+	if err := c.run(ctx, "previous"); err != nil {
+		return fmt.Errorf("failed to go to previous track: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,366 @@
+package music
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMPD is an in-process MPD server with a two-song queue
+type fakeMPD struct {
+	ln       net.Listener
+	password string
+
+	mu       sync.Mutex
+	state    string // play, pause or stop
+	song     int
+	elapsed  float64
+	commands []string
+	version  int           // Incremented when the player changes
+	changed  chan struct{} // Closed and replaced when the player changes
+}
+
+var fakeMPDSongs = []map[string]string{
+	{"file": "cher/believe.flac", "Title": "Believe", "Artist": "Cher", "Album": "Believe", "duration": "239.000"},
+	{"file": "untagged/track01.mp3", "Time": "180"},
+}
+
+func newFakeMPD(t *testing.T, network, password string) *fakeMPD {
+	t.Helper()
+	address := "127.0.0.1:0"
+	if network == "unix" {
+		address = filepath.Join(t.TempDir(), "mpd.sock")
+	}
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+
+	f := &fakeMPD{ln: ln, password: password, state: "play", elapsed: 12.5, changed: make(chan struct{})}
+	t.Cleanup(func() { _ = ln.Close() })
+	go f.serve()
+	return f
+}
+
+func (f *fakeMPD) address() string {
+	return f.ln.Addr().String()
+}
+
+func (f *fakeMPD) serve() {
+	for {
+		conn, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeMPD) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	_, _ = fmt.Fprint(conn, "OK MPD 0.23.5\n")
+
+	// Like MPD, report changes made since the connection's last idle,
+	// even if they happened while it wasn't idling
+	f.mu.Lock()
+	seen := f.version
+	f.mu.Unlock()
+
+	authed := f.password == ""
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		name, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+		arg = strings.Trim(arg, `"`)
+
+		f.mu.Lock()
+		f.commands = append(f.commands, strings.TrimSpace(line))
+		f.mu.Unlock()
+
+		if name == "password" {
+			if arg != f.password {
+				_, _ = fmt.Fprint(conn, "ACK [3@0] {password} incorrect password\n")
+				continue
+			}
+			authed = true
+			_, _ = fmt.Fprint(conn, "OK\n")
+			continue
+		}
+		if !authed {
+			_, _ = fmt.Fprintf(conn, "ACK [4@0] {%s} you don't have permission for \"%s\"\n", name, name)
+			continue
+		}
+
+		if name == "idle" {
+			for {
+				f.mu.Lock()
+				version, changed := f.version, f.changed
+				f.mu.Unlock()
+				if version > seen {
+					seen = version
+					break
+				}
+				<-changed
+			}
+			_, _ = fmt.Fprint(conn, "changed: player\nOK\n")
+			continue
+		}
+
+		_, _ = fmt.Fprint(conn, f.respond(name, arg))
+	}
+}
+
+// respond runs a command against the fake player
+func (f *fakeMPD) respond(name, arg string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch name {
+	case "ping":
+	case "status":
+		return fmt.Sprintf("volume: 100\nstate: %s\nsong: %d\nelapsed: %.3f\nOK\n", f.state, f.song, f.elapsed)
+	case "currentsong":
+		if f.state == "stop" {
+			return "OK\n"
+		}
+		var sb strings.Builder
+		for k, v := range fakeMPDSongs[f.song] {
+			fmt.Fprintf(&sb, "%s: %s\n", k, v)
+		}
+		return sb.String() + "OK\n"
+	case "play":
+		f.setLocked("play", f.song)
+	case "pause":
+		f.setLocked("pause", f.song)
+	case "next":
+		if f.song+1 >= len(fakeMPDSongs) {
+			return "ACK [55@0] {next} Not playing\n"
+		}
+		f.setLocked(f.state, f.song+1)
+	case "previous":
+		f.setLocked(f.state, max(f.song-1, 0))
+	default:
+		return fmt.Sprintf("ACK [5@0] {} unknown command \"%s\"\n", name)
+	}
+	return "OK\n"
+}
+
+// set changes the player state and wakes idle clients
+func (f *fakeMPD) set(state string, song int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.setLocked(state, song)
+}
+
+func (f *fakeMPD) setLocked(state string, song int) {
+	if song != f.song {
+		f.elapsed = 0
+	}
+	f.state, f.song = state, song
+	f.version++
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeMPD) lastCommand() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.commands[len(f.commands)-1]
+}
+
+func TestMPDClient_GetCurrentTrack(t *testing.T) {
+	for _, network := range []string{"tcp", "unix"} {
+		t.Run(network, func(t *testing.T) {
+			server := newFakeMPD(t, network, "")
+			client := NewMPDClient(server.address(), "")
+			ctx := context.Background()
+
+			track, err := client.GetCurrentTrack(ctx)
+			if err != nil {
+				t.Fatalf("GetCurrentTrack: %v", err)
+			}
+			want := Track{
+				Name:     "Believe",
+				Artist:   "Cher",
+				Album:    "Believe",
+				Duration: 239 * time.Second,
+				Position: 12500 * time.Millisecond,
+				State:    StatePlaying,
+			}
+			if track == nil || *track != want {
+				t.Errorf("expected %+v, got %+v", want, track)
+			}
+
+			server.set("stop", 0)
+			if track, err := client.GetCurrentTrack(ctx); err != nil || track != nil {
+				t.Errorf("expected no track when stopped, got %+v, %v", track, err)
+			}
+		})
+	}
+}
+
+func TestMPDClient_Controls(t *testing.T) {
+	server := newFakeMPD(t, "tcp", "")
+	client := NewMPDClient(server.address(), "")
+	ctx := context.Background()
+
+	steps := []struct {
+		name    string
+		run     func(context.Context) error
+		command string
+	}{
+		{"Pause", client.Pause, `pause "1"`},
+		{"PlayPause", client.PlayPause, "play"},
+		{"PlayPause", client.PlayPause, `pause "1"`},
+		{"Play", client.Play, "play"},
+		{"NextTrack", client.NextTrack, "next"},
+		{"PreviousTrack", client.PreviousTrack, "previous"},
+	}
+	for _, step := range steps {
+		if err := step.run(ctx); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := server.lastCommand(); got != step.command {
+			t.Errorf("%s: expected %q, got %q", step.name, step.command, got)
+		}
+	}
+
+	server.set("play", 1)
+	err := client.NextTrack(ctx)
+	if err == nil || !strings.Contains(err.Error(), "mpd next: Not playing") {
+		t.Errorf("expected MPD's error, got %v", err)
+	}
+}
+
+func TestMPDClient_Password(t *testing.T) {
+	server := newFakeMPD(t, "tcp", "hunter2")
+	ctx := context.Background()
+
+	if _, err := NewMPDClient(server.address(), "wrong").GetCurrentTrack(ctx); err == nil || !strings.Contains(err.Error(), "incorrect password") {
+		t.Errorf("expected a password error, got %v", err)
+	}
+	if _, err := NewMPDClient(server.address(), "").GetCurrentTrack(ctx); err == nil {
+		t.Error("expected an error without the password")
+	}
+	if _, err := NewMPDClient(server.address(), "hunter2").GetCurrentTrack(ctx); err != nil {
+		t.Errorf("GetCurrentTrack with password: %v", err)
+	}
+}
+
+func TestMPDClient_IsRunning(t *testing.T) {
+	server := newFakeMPD(t, "tcp", "")
+	ctx := context.Background()
+
+	if running, err := NewMPDClient(server.address(), "").IsRunning(ctx); err != nil || !running {
+		t.Errorf("expected MPD to be running, got %v, %v", running, err)
+	}
+
+	address := server.address()
+	_ = server.ln.Close()
+	if running, err := NewMPDClient(address, "").IsRunning(ctx); err != nil || running {
+		t.Errorf("expected MPD not to be running, got %v, %v", running, err)
+	}
+}
+
+func TestMPDClient_Watch(t *testing.T) {
+	server := newFakeMPD(t, "tcp", "")
+	client := NewMPDClient(server.address(), "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tracks := make(chan *Track)
+	done := make(chan error, 1)
+	go func() { done <- client.Watch(ctx, tracks) }()
+
+	next := func() *Track {
+		t.Helper()
+		select {
+		case track := <-tracks:
+			return track
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for a track")
+			return nil
+		}
+	}
+
+	if track := next(); track == nil || track.Name != "Believe" {
+		t.Fatalf("expected the current track first, got %+v", track)
+	}
+
+	server.set("play", 1)
+	if track := next(); track == nil || track.Name != "track01.mp3" || track.Duration != 3*time.Minute {
+		t.Errorf("expected the untagged file after the change, got %+v", track)
+	}
+
+	server.set("stop", 1)
+	if track := next(); track != nil {
+		t.Errorf("expected no track after stopping, got %+v", track)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("expected Watch to return context.Canceled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch did not return after cancel")
+	}
+}
+
+func TestParseMPDTrack(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  map[string]string
+		song    map[string]string
+		want    *Track
+		wantErr bool
+	}{
+		{
+			name:   "paused, deprecated time field",
+			status: map[string]string{"state": "pause", "time": "61:200"},
+			song:   map[string]string{"Title": "Song", "AlbumArtist": "Band"},
+			want:   &Track{Name: "Song", Artist: "Band", Duration: 200 * time.Second, Position: 61 * time.Second, State: StatePaused},
+		},
+		{
+			name:   "stream with a name",
+			status: map[string]string{"state": "play", "elapsed": "5.000"},
+			song:   map[string]string{"file": "http://radio.example/stream", "Name": "Radio"},
+			want:   &Track{Name: "Radio", Position: 5 * time.Second, State: StatePlaying},
+		},
+		{
+			name:   "stopped",
+			status: map[string]string{"state": "stop"},
+		},
+		{
+			name:    "unknown state",
+			status:  map[string]string{"state": "rewinding"},
+			wantErr: true,
+		},
+		{
+			name:    "bad elapsed",
+			status:  map[string]string{"state": "play", "elapsed": "soon"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMPDTrack(tt.status, tt.song)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMPDTrack() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("parseMPDTrack() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package music
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// subsonicAPIVersion is the Subsonic API version scribbles speaks. 1.13.0
+// introduced token authentication.
+const subsonicAPIVersion = "1.16.1"
+
+// subsonicStaleAfter is how long past the end of its track a now playing
+// entry is ignored, since servers keep entries for a while after playback
+const subsonicStaleAfter = time.Minute
+
+// SubsonicClient implements the Client interface for a user's now playing
+// entry on a Subsonic-compatible server, such as Navidrome or Airsonic. It
+// can star and unstar songs and submit scrobbles, but can't control the
+// player, which may be any of the server's clients.
+type SubsonicClient struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	mu         sync.Mutex
+	nowPlaying subsonicSong // Latest now playing entry of the user
+	since      time.Time    // When it started, estimated from minutesAgo
+}
+
+// NewSubsonicClient creates a client for username's plays on the server at
+// baseURL, e.g. https://music.example.com
+func NewSubsonicClient(baseURL, username, password string) *SubsonicClient {
+	return &SubsonicClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SubsonicError is a failed response from a Subsonic server
+type SubsonicError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error returns the error message
+func (e *SubsonicError) Error() string {
+	return fmt.Sprintf("subsonic: error %d: %s", e.Code, e.Message)
+}
+
+// subsonicSong is a song in a Subsonic response
+type subsonicSong struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Artist     string `json:"artist"`
+	Album      string `json:"album"`
+	Duration   int    `json:"duration"` // Seconds
+	Username   string `json:"username"`
+	MinutesAgo int    `json:"minutesAgo"`
+}
+
+// subsonicResponse is the envelope of every Subsonic response, with the
+// fields of the endpoints scribbles calls
+type subsonicResponse struct {
+	Status     string         `json:"status"`
+	Error      *SubsonicError `json:"error"`
+	NowPlaying struct {
+		Entry []subsonicSong `json:"entry"`
+	} `json:"nowPlaying"`
+	SearchResult3 struct {
+		Song []subsonicSong `json:"song"`
+	} `json:"searchResult3"`
+}
+
+// call requests a Subsonic endpoint, such as "getNowPlaying", with token
+// authentication
+func (c *SubsonicClient) call(ctx context.Context, endpoint string, params url.Values) (*subsonicResponse, error) {
+	salt, err := subsonicSalt()
+	if err != nil {
+		return nil, err
+	}
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("u", c.username)
+	params.Set("t", subsonicToken(c.password, salt))
+	params.Set("s", salt)
+	params.Set("v", subsonicAPIVersion)
+	params.Set("c", "scribbles")
+	params.Set("f", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/rest/"+endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("subsonic: failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("subsonic: request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("subsonic: failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("subsonic: %s: HTTP %d", endpoint, resp.StatusCode)
+	}
+
+	var envelope struct {
+		Response subsonicResponse `json:"subsonic-response"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("subsonic: failed to parse %s response: %w", endpoint, err)
+	}
+	r := &envelope.Response
+	if r.Status != "ok" {
+		if r.Error != nil {
+			return nil, r.Error
+		}
+		return nil, fmt.Errorf("subsonic: %s failed", endpoint)
+	}
+	return r, nil
+}
+
+// subsonicToken is the token for password with salt: md5(password + salt)
+func subsonicToken(password, salt string) string {
+	sum := md5.Sum([]byte(password + salt))
+	return hex.EncodeToString(sum[:])
+}
+
+// subsonicSalt returns a new random salt for a request
+func subsonicSalt() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("subsonic: failed to generate salt: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// IsRunning checks if the server answers a ping. Authentication errors are
+// returned, since the server is up but unusable.
+func (c *SubsonicClient) IsRunning(ctx context.Context) (bool, error) {
+	_, err := c.call(ctx, "ping", nil)
+	var apiErr *SubsonicError
+	if errors.As(err, &apiErr) {
+		return false, err
+	}
+	return err == nil, nil
+}
+
+// GetCurrentTrack returns the user's most recent now playing entry, or nil
+// if there is none. Subsonic doesn't report pauses or the position, so the
+// track is always playing, from about when the entry appeared.
+func (c *SubsonicClient) GetCurrentTrack(ctx context.Context) (*Track, error) {
+	resp, err := c.call(ctx, "getNowPlaying", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry *subsonicSong
+	for i, e := range resp.NowPlaying.Entry {
+		if !strings.EqualFold(e.Username, c.username) {
+			continue
+		}
+		if entry == nil || e.MinutesAgo < entry.MinutesAgo {
+			entry = &resp.NowPlaying.Entry[i]
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry == nil {
+		c.nowPlaying = subsonicSong{}
+		return nil, nil
+	}
+
+	// A new entry, or the same song started again: minutesAgo is whole
+	// minutes, so the play began less than a minute before it says
+	now := time.Now()
+	if entry.ID != c.nowPlaying.ID || now.Sub(c.since) > time.Duration(entry.MinutesAgo+1)*time.Minute {
+		c.since = now.Add(-time.Duration(entry.MinutesAgo) * time.Minute)
+	}
+	c.nowPlaying = *entry
+
+	duration := time.Duration(entry.Duration) * time.Second
+	position := now.Sub(c.since)
+	if duration > 0 {
+		if position > duration+subsonicStaleAfter {
+			return nil, nil
+		}
+		position = min(position, duration)
+	}
+
+	return &Track{
+		Name:     entry.Title,
+		Artist:   entry.Artist,
+		Album:    entry.Album,
+		Duration: duration,
+		Position: position,
+		State:    StatePlaying,
+	}, nil
+}
+
+// Star stars a song by ID
+func (c *SubsonicClient) Star(ctx context.Context, id string) error {
+	if _, err := c.call(ctx, "star", url.Values{"id": {id}}); err != nil {
+		return fmt.Errorf("failed to star song %s: %w", id, err)
+	}
+	return nil
+}
+
+// Unstar removes the star from a song by ID
+func (c *SubsonicClient) Unstar(ctx context.Context, id string) error {
+	if _, err := c.call(ctx, "unstar", url.Values{"id": {id}}); err != nil {
+		return fmt.Errorf("failed to unstar song %s: %w", id, err)
+	}
+	return nil
+}
+
+// Scrobble registers a play of a song by ID that happened at playedAt, or
+// with submission false, marks it as now playing
+func (c *SubsonicClient) Scrobble(ctx context.Context, id string, playedAt time.Time, submission bool) error {
+	params := url.Values{
+		"id":         {id},
+		"time":       {strconv.FormatInt(playedAt.UnixMilli(), 10)},
+		"submission": {strconv.FormatBool(submission)},
+	}
+	if _, err := c.call(ctx, "scrobble", params); err != nil {
+		return fmt.Errorf("failed to scrobble song %s: %w", id, err)
+	}
+	return nil
+}
+
+// LoveTrack stars a song by artist and title, so loves made through
+// scribbles are mirrored on the server
+func (c *SubsonicClient) LoveTrack(ctx context.Context, artist, track string) error {
+	id, err := c.SongID(ctx, artist, track)
+	if err != nil {
+		return err
+	}
+	return c.Star(ctx, id)
+}
+
+// UnloveTrack unstars a song by artist and title
+func (c *SubsonicClient) UnloveTrack(ctx context.Context, artist, track string) error {
+	id, err := c.SongID(ctx, artist, track)
+	if err != nil {
+		return err
+	}
+	return c.Unstar(ctx, id)
+}
+
+// SongID finds the ID of a song by artist and title: the now playing
+// entry's if it matches, or otherwise the first exact match in a search
+func (c *SubsonicClient) SongID(ctx context.Context, artist, track string) (string, error) {
+	matches := func(s subsonicSong) bool {
+		return strings.EqualFold(s.Artist, artist) && strings.EqualFold(s.Title, track)
+	}
+
+	c.mu.Lock()
+	current := c.nowPlaying
+	c.mu.Unlock()
+	if current.ID != "" && matches(current) {
+		return current.ID, nil
+	}
+
+	resp, err := c.call(ctx, "search3", url.Values{
+		"query":       {track},
+		"songCount":   {"50"},
+		"artistCount": {"0"},
+		"albumCount":  {"0"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to search for %s - %s: %w", artist, track, err)
+	}
+	for _, s := range resp.SearchResult3.Song {
+		if matches(s) {
+			return s.ID, nil
+		}
+	}
+	return "", fmt.Errorf("subsonic: no song %s - %s", artist, track)
+}
+
+// Play is unsupported
+func (c *SubsonicClient) Play(ctx context.Context) error {
+	return ErrControlsUnsupported
+}
+
+// Pause is unsupported
+func (c *SubsonicClient) Pause(ctx context.Context) error {
+	return ErrControlsUnsupported
+}
+
+// PlayPause is unsupported
+func (c *SubsonicClient) PlayPause(ctx context.Context) error {
+	return ErrControlsUnsupported
+}
+
+// NextTrack is unsupported
+func (c *SubsonicClient) NextTrack(ctx context.Context) error {
+	return ErrControlsUnsupported
+}
+
+// PreviousTrack is unsupported
+func (c *SubsonicClient) PreviousTrack(ctx context.Context) error {
+	return ErrControlsUnsupported
+}
@@ -0,0 +1,184 @@
+package music
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSubsonic is a Subsonic server for user elan with password s3cret
+type fakeSubsonic struct {
+	mu         sync.Mutex
+	nowPlaying string // JSON entries of getNowPlaying
+	calls      []url.Values
+}
+
+func newFakeSubsonic(t *testing.T) (*fakeSubsonic, *SubsonicClient) {
+	t.Helper()
+	f := &fakeSubsonic{}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, NewSubsonicClient(srv.URL+"/", "elan", "s3cret")
+}
+
+func (f *fakeSubsonic) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, q)
+
+	w.Header().Set("Content-Type", "application/json")
+	if q.Get("u") != "elan" || q.Get("t") != subsonicToken("s3cret", q.Get("s")) ||
+		q.Get("v") == "" || q.Get("c") != "scribbles" || q.Get("f") != "json" {
+		fmt.Fprint(w, `{"subsonic-response": {"status": "failed", "version": "1.16.1", "error": {"code": 40, "message": "Wrong username or password"}}}`)
+		return
+	}
+
+	body := ""
+	switch r.URL.Path {
+	case "/rest/ping", "/rest/star", "/rest/unstar", "/rest/scrobble":
+	case "/rest/getNowPlaying":
+		body = fmt.Sprintf(`, "nowPlaying": {"entry": [%s]}`, f.nowPlaying)
+	case "/rest/search3":
+		body = `, "searchResult3": {"song": [
+			{"id": "tr-9", "title": "Teardrop (Remix)", "artist": "Massive Attack"},
+			{"id": "tr-1", "title": "Teardrop", "artist": "Massive Attack"}
+		]}`
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	fmt.Fprintf(w, `{"subsonic-response": {"status": "ok", "version": "1.16.1"%s}}`, body)
+}
+
+func (f *fakeSubsonic) lastCall() url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[len(f.calls)-1]
+}
+
+func TestSubsonicClient_GetCurrentTrack(t *testing.T) {
+	f, c := newFakeSubsonic(t)
+	ctx := context.Background()
+
+	f.nowPlaying = `
+		{"id": "old-1", "title": "Angel", "artist": "Massive Attack", "album": "Mezzanine", "duration": 379, "username": "elan", "minutesAgo": 7},
+		{"id": "tr-1", "title": "Teardrop", "artist": "Massive Attack", "album": "Mezzanine", "duration": 330, "username": "elan", "minutesAgo": 2},
+		{"id": "bj-1", "title": "Hyperballad", "artist": "Björk", "album": "Post", "duration": 321, "username": "someone", "minutesAgo": 0}`
+	track, err := c.GetCurrentTrack(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentTrack: %v", err)
+	}
+	if track == nil || track.Name != "Teardrop" || track.Album != "Mezzanine" ||
+		track.Duration != 330*time.Second || track.State != StatePlaying {
+		t.Fatalf("expected the user's latest entry, got %+v", track)
+	}
+	if track.Position < 2*time.Minute || track.Position > 2*time.Minute+time.Second {
+		t.Errorf("expected the position estimated from minutesAgo, got %v", track.Position)
+	}
+
+	// Long past its end, the entry is ignored
+	f.nowPlaying = `{"id": "tr-1", "title": "Teardrop", "artist": "Massive Attack", "duration": 330, "username": "elan", "minutesAgo": 20}`
+	c.since = time.Now().Add(-20 * time.Minute)
+	if track, _ := c.GetCurrentTrack(ctx); track != nil {
+		t.Errorf("expected a stale entry to be ignored, got %+v", track)
+	}
+
+	f.nowPlaying = ""
+	if track, err := c.GetCurrentTrack(ctx); err != nil || track != nil {
+		t.Errorf("expected nothing playing, got %+v, %v", track, err)
+	}
+}
+
+func TestSubsonicClient_RestartedSong(t *testing.T) {
+	f, c := newFakeSubsonic(t)
+	f.nowPlaying = `{"id": "tr-1", "title": "Teardrop", "artist": "Massive Attack", "duration": 330, "username": "elan", "minutesAgo": 0}`
+
+	// The same song, started again after the last play ended
+	c.nowPlaying = subsonicSong{ID: "tr-1"}
+	c.since = time.Now().Add(-6 * time.Minute)
+	track, err := c.GetCurrentTrack(context.Background())
+	if err != nil {
+		t.Fatalf("GetCurrentTrack: %v", err)
+	}
+	if track == nil || track.Position > time.Second {
+		t.Errorf("expected the play to restart, got %+v", track)
+	}
+}
+
+func TestSubsonicClient_Endpoints(t *testing.T) {
+	f, c := newFakeSubsonic(t)
+	ctx := context.Background()
+
+	if running, err := c.IsRunning(ctx); err != nil || !running {
+		t.Errorf("expected the server to be running, got %v, %v", running, err)
+	}
+
+	if err := c.Star(ctx, "tr-1"); err != nil {
+		t.Fatalf("Star: %v", err)
+	}
+	if q := f.lastCall(); q.Get("id") != "tr-1" {
+		t.Errorf("expected star of tr-1, got %v", q)
+	}
+
+	playedAt := time.UnixMilli(1760600000123)
+	if err := c.Scrobble(ctx, "tr-1", playedAt, true); err != nil {
+		t.Fatalf("Scrobble: %v", err)
+	}
+	if q := f.lastCall(); q.Get("id") != "tr-1" || q.Get("time") != "1760600000123" || q.Get("submission") != "true" {
+		t.Errorf("expected a submission of tr-1 at playedAt, got %v", q)
+	}
+
+	// Not playing, so the song is found by searching
+	if err := c.UnloveTrack(ctx, "massive attack", "teardrop"); err != nil {
+		t.Fatalf("UnloveTrack: %v", err)
+	}
+	if q := f.lastCall(); q.Get("id") != "tr-1" {
+		t.Errorf("expected unstar of tr-1, got %v", q)
+	}
+	if err := c.LoveTrack(ctx, "Massive Attack", "Unfinished Sympathy"); err == nil {
+		t.Error("expected an error for a song the server doesn't have")
+	}
+
+	if err := c.Play(ctx); err != ErrControlsUnsupported {
+		t.Errorf("expected ErrControlsUnsupported, got %v", err)
+	}
+}
+
+func TestSubsonicClient_LoveTrackUsesNowPlaying(t *testing.T) {
+	f, c := newFakeSubsonic(t)
+	ctx := context.Background()
+	f.nowPlaying = `{"id": "np-7", "title": "Teardrop", "artist": "Massive Attack", "duration": 330, "username": "elan", "minutesAgo": 0}`
+	if _, err := c.GetCurrentTrack(ctx); err != nil {
+		t.Fatalf("GetCurrentTrack: %v", err)
+	}
+
+	if err := c.LoveTrack(ctx, "Massive Attack", "Teardrop"); err != nil {
+		t.Fatalf("LoveTrack: %v", err)
+	}
+	if q := f.lastCall(); q.Get("id") != "np-7" {
+		t.Errorf("expected star of the playing song, got %v", q)
+	}
+}
+
+func TestSubsonicClient_WrongPassword(t *testing.T) {
+	_, c := newFakeSubsonic(t)
+	c.password = "wrong"
+
+	running, err := c.IsRunning(context.Background())
+	if running || err == nil {
+		t.Fatalf("expected an authentication error, got %v, %v", running, err)
+	}
+	if apiErr, ok := err.(*SubsonicError); !ok || apiErr.Code != 40 {
+		t.Errorf("expected a SubsonicError with code 40, got %v", err)
+	}
+
+	if _, err := c.GetCurrentTrack(context.Background()); err == nil {
+		t.Error("expected GetCurrentTrack to fail")
+	}
+}
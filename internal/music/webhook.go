@@ -0,0 +1,373 @@
+package music
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultWebhookAddress is where WebhookClient listens by default
+const DefaultWebhookAddress = "127.0.0.1:7416"
+
+// webhookStaleAfter is how long past the end of its track a playing track
+// is kept when no event stops it, in case a player never sends one
+const webhookStaleAfter = time.Minute
+
+// maxWebhookBody bounds request bodies. Plex attaches a thumbnail.
+const maxWebhookBody = 10 << 20
+
+// jellyfinTick is the unit of Jellyfin's durations
+const jellyfinTick = 100 * time.Nanosecond
+
+// WebhookClient implements the Client interface for media servers and
+// players that POST playback events: Plex webhooks, the Jellyfin webhook
+// plugin and a generic JSON schema for custom players. It is a Watcher
+// that serves its endpoints while watched:
+//
+//	POST /plex      Plex multipart payloads
+//	POST /jellyfin  Jellyfin webhook plugin JSON
+//	POST /track     generic JSON (see GenericEvent)
+//
+// Its player can't be controlled.
+type WebhookClient struct {
+	address string
+	token   string // Required as ?token= or a bearer token, if set
+	user    string // Only follow this Plex or Jellyfin user, if set
+
+	mu         sync.Mutex
+	track      *Track    // Latest track (nil if stopped)
+	receivedAt time.Time // When track was received, to advance its position
+	changed    chan struct{}
+}
+
+// NewWebhookClient creates a client that listens on address. Requests must
+// carry token if it is set, and events for users other than user are
+// ignored if it is set.
+func NewWebhookClient(address, token, user string) *WebhookClient {
+	if address == "" {
+		address = DefaultWebhookAddress
+	}
+	return &WebhookClient{
+		address: address,
+		token:   token,
+		user:    user,
+		changed: make(chan struct{}, 1),
+	}
+}
+
+// GenericEvent is the JSON body of POST /track, for players without a
+// format of their own. Durations are in seconds.
+type GenericEvent struct {
+	Event    string  `json:"event"` // play, resume, progress, pause or stop
+	Track    string  `json:"track"`
+	Artist   string  `json:"artist"`
+	Album    string  `json:"album,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+	Position float64 `json:"position,omitempty"`
+	User     string  `json:"user,omitempty"`
+}
+
+// errIgnoredEvent marks events that aren't about music, such as a movie
+// playing on Plex, or that come from another user
+var errIgnoredEvent = errors.New("ignored event")
+
+// ServeHTTP takes a playback event
+func (c *WebhookClient) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !c.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxWebhookBody)
+
+	var track *Track
+	var err error
+	switch r.URL.Path {
+	case "/plex":
+		track, err = c.parsePlex(r)
+	case "/jellyfin":
+		track, err = c.parseJellyfin(r)
+	case "/track":
+		track, err = c.parseGeneric(r)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case errors.Is(err, errIgnoredEvent):
+		w.WriteHeader(http.StatusAccepted)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		c.setTrack(track)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (c *WebhookClient) authorized(r *http.Request) bool {
+	if c.token == "" {
+		return true
+	}
+	got := r.URL.Query().Get("token")
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		got = bearer
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(c.token)) == 1
+}
+
+// plexPayload is the part of a Plex webhook payload scribbles reads
+type plexPayload struct {
+	Event   string `json:"event"`
+	Account struct {
+		Title string `json:"title"`
+	} `json:"Account"`
+	Metadata struct {
+		Type             string `json:"type"`
+		Title            string `json:"title"`
+		ParentTitle      string `json:"parentTitle"`      // Album
+		GrandparentTitle string `json:"grandparentTitle"` // Album artist
+		OriginalTitle    string `json:"originalTitle"`    // Track artist, if not the album's
+		Duration         int64  `json:"duration"`         // Milliseconds
+		ViewOffset       int64  `json:"viewOffset"`       // Milliseconds
+	} `json:"Metadata"`
+}
+
+// parsePlex reads a Plex webhook, a multipart form with the event as JSON
+// in its payload field
+func (c *WebhookClient) parsePlex(r *http.Request) (*Track, error) {
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		return nil, fmt.Errorf("invalid Plex webhook: %w", err)
+	}
+	var p plexPayload
+	if err := json.Unmarshal([]byte(r.FormValue("payload")), &p); err != nil {
+		return nil, fmt.Errorf("invalid Plex payload: %w", err)
+	}
+	if p.Metadata.Type != "track" || !c.followsUser(p.Account.Title) {
+		return nil, errIgnoredEvent
+	}
+
+	var state PlayState
+	switch p.Event {
+	case "media.play", "media.resume", "media.scrobble":
+		state = StatePlaying
+	case "media.pause":
+		state = StatePaused
+	case "media.stop":
+		return nil, nil
+	default:
+		// Ratings and library events
+		return nil, errIgnoredEvent
+	}
+
+	artist := p.Metadata.OriginalTitle
+	if artist == "" {
+		artist = p.Metadata.GrandparentTitle
+	}
+	return &Track{
+		Name:     p.Metadata.Title,
+		Artist:   artist,
+		Album:    p.Metadata.ParentTitle,
+		Duration: time.Duration(p.Metadata.Duration) * time.Millisecond,
+		Position: time.Duration(p.Metadata.ViewOffset) * time.Millisecond,
+		State:    state,
+	}, nil
+}
+
+// jellyfinPayload is the JSON the Jellyfin webhook plugin's default
+// template sends, as far as scribbles reads it
+type jellyfinPayload struct {
+	NotificationType      string `json:"NotificationType"`
+	NotificationUsername  string `json:"NotificationUsername"`
+	ItemType              string `json:"ItemType"`
+	Name                  string `json:"Name"`
+	Artist                string `json:"Artist"`
+	Album                 string `json:"Album"`
+	RunTimeTicks          int64  `json:"RunTimeTicks"`
+	PlaybackPositionTicks int64  `json:"PlaybackPositionTicks"`
+	IsPaused              bool   `json:"IsPaused"`
+}
+
+// parseJellyfin reads a Jellyfin webhook plugin event
+func (c *WebhookClient) parseJellyfin(r *http.Request) (*Track, error) {
+	var p jellyfinPayload
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		return nil, fmt.Errorf("invalid Jellyfin payload: %w", err)
+	}
+	if p.ItemType != "Audio" || !c.followsUser(p.NotificationUsername) {
+		return nil, errIgnoredEvent
+	}
+
+	state := StatePlaying
+	switch p.NotificationType {
+	case "PlaybackStart", "PlaybackProgress":
+		if p.IsPaused {
+			state = StatePaused
+		}
+	case "PlaybackStop":
+		return nil, nil
+	default:
+		return nil, errIgnoredEvent
+	}
+
+	return &Track{
+		Name:     p.Name,
+		Artist:   p.Artist,
+		Album:    p.Album,
+		Duration: time.Duration(p.RunTimeTicks) * jellyfinTick,
+		Position: time.Duration(p.PlaybackPositionTicks) * jellyfinTick,
+		State:    state,
+	}, nil
+}
+
+// parseGeneric reads a GenericEvent
+func (c *WebhookClient) parseGeneric(r *http.Request) (*Track, error) {
+	var e GenericEvent
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		return nil, fmt.Errorf("invalid event: %w", err)
+	}
+	if !c.followsUser(e.User) {
+		return nil, errIgnoredEvent
+	}
+
+	var state PlayState
+	switch e.Event {
+	case "play", "resume", "progress":
+		state = StatePlaying
+	case "pause":
+		state = StatePaused
+	case "stop":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown event %q (must be one of: play, resume, progress, pause, stop)", e.Event)
+	}
+	if e.Track == "" || e.Artist == "" {
+		return nil, errors.New("track and artist are required")
+	}
+
+	return &Track{
+		Name:     e.Track,
+		Artist:   e.Artist,
+		Album:    e.Album,
+		Duration: secondsToDuration(e.Duration),
+		Position: secondsToDuration(e.Position),
+		State:    state,
+	}, nil
+}
+
+// followsUser reports whether events from user are followed. Events
+// without a user are followed.
+func (c *WebhookClient) followsUser(user string) bool {
+	return c.user == "" || user == "" || strings.EqualFold(user, c.user)
+}
+
+// setTrack records the latest track and wakes Watch
+func (c *WebhookClient) setTrack(track *Track) {
+	c.mu.Lock()
+	c.track = track
+	c.receivedAt = time.Now()
+	c.mu.Unlock()
+
+	select {
+	case c.changed <- struct{}{}:
+	default:
+		// Watch hasn't sent the previous change yet, and will send this one
+	}
+}
+
+// IsRunning reports true: the player runs elsewhere, and may be idle
+func (c *WebhookClient) IsRunning(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+// GetCurrentTrack returns the latest track received, with its position
+// advanced by the time since if it is playing, or nil if stopped. A
+// playing track is dropped once it would have ended a minute ago.
+func (c *WebhookClient) GetCurrentTrack(ctx context.Context) (*Track, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.track == nil {
+		return nil, nil
+	}
+	track := *c.track
+	if track.State == StatePlaying {
+		track.Position += time.Since(c.receivedAt)
+		if track.Duration > 0 {
+			if track.Position > track.Duration+webhookStaleAfter {
+				return nil, nil
+			}
+			track.Position = min(track.Position, track.Duration)
+		}
+	}
+	return &track, nil
+}
+
+// Watch serves the webhook endpoints on the client's address, sending the
+// current track and then the track after each event, until ctx is
+// cancelled or the server fails
+func (c *WebhookClient) Watch(ctx context.Context, tracks chan<- *Track) error {
+	ln, err := net.Listen("tcp", c.address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", c.address, err)
+	}
+
+	srv := &http.Server{Handler: c, ReadHeaderTimeout: 5 * time.Second}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.Serve(ln) }()
+	defer func() { _ = srv.Close() }()
+
+	for {
+		track, _ := c.GetCurrentTrack(ctx)
+		select {
+		case tracks <- track:
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-serveErr:
+			return err
+		}
+
+		select {
+		case <-c.changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-serveErr:
+			return err
+		}
+	}
+}
+
+// Play is unsupported
+func (c *WebhookClient) Play(ctx context.Context) error {
+	return ErrControlsUnsupported
+}
+
+// Pause is unsupported
+func (c *WebhookClient) Pause(ctx context.Context) error {
+	return ErrControlsUnsupported
+}
+
+// PlayPause is unsupported
+func (c *WebhookClient) PlayPause(ctx context.Context) error {
+	return ErrControlsUnsupported
+}
+
+// NextTrack is unsupported
+func (c *WebhookClient) NextTrack(ctx context.Context) error {
+	return ErrControlsUnsupported
+}
+
+// PreviousTrack is unsupported
+func (c *WebhookClient) PreviousTrack(ctx context.Context) error {
+	return ErrControlsUnsupported
+}
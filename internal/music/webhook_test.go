@@ -0,0 +1,203 @@
+package music
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// postFixture POSTs a recorded webhook payload to path, wrapped in a
+// multipart form as Plex sends it for /plex
+func postFixture(t *testing.T, c *WebhookClient, path, fixture string) *httptest.ResponseRecorder {
+	t.Helper()
+	payload, err := os.ReadFile(filepath.Join("testdata", "webhooks", fixture))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	body := bytes.NewBuffer(payload)
+	contentType := "application/json"
+	if strings.HasPrefix(path, "/plex") {
+		body = &bytes.Buffer{}
+		mw := multipart.NewWriter(body)
+		if err := mw.WriteField("payload", string(payload)); err != nil {
+			t.Fatalf("WriteField: %v", err)
+		}
+		thumb, err := mw.CreateFormFile("thumb", "thumb.jpg")
+		if err != nil {
+			t.Fatalf("CreateFormFile: %v", err)
+		}
+		_, _ = thumb.Write([]byte("\xff\xd8\xff\xe0 not really a jpeg"))
+		_ = mw.Close()
+		contentType = mw.FormDataContentType()
+	}
+
+	req := httptest.NewRequest(http.MethodPost, path, body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookClient_Fixtures(t *testing.T) {
+	tests := []struct {
+		path    string
+		fixture string
+		status  int
+		want    *Track
+	}{
+		{"/plex", "plex_play.json", http.StatusNoContent, &Track{
+			Name: "Love The One You're With", Artist: "Stephen Stills", Album: "Stephen Stills",
+			Duration: 184 * time.Second, Position: 3 * time.Second, State: StatePlaying,
+		}},
+		{"/plex", "plex_pause.json", http.StatusNoContent, &Track{
+			Name: "Both Sides Now", Artist: "Joni Mitchell", Album: "Folk Classics",
+			Duration: 276 * time.Second, Position: 61 * time.Second, State: StatePaused,
+		}},
+		{"/plex", "plex_stop.json", http.StatusNoContent, nil},
+		{"/jellyfin", "jellyfin_start.json", http.StatusNoContent, &Track{
+			Name: "Hyperballad", Artist: "Björk", Album: "Post",
+			Duration: 321500 * time.Millisecond, State: StatePlaying,
+		}},
+		{"/jellyfin", "jellyfin_progress_paused.json", http.StatusNoContent, &Track{
+			Name: "Hyperballad", Artist: "Björk", Album: "Post",
+			Duration: 321500 * time.Millisecond, Position: 120400 * time.Millisecond, State: StatePaused,
+		}},
+		{"/jellyfin", "jellyfin_stop.json", http.StatusNoContent, nil},
+		{"/track", "generic_play.json", http.StatusNoContent, &Track{
+			Name: "Teardrop", Artist: "Massive Attack", Album: "Mezzanine",
+			Duration: 330500 * time.Millisecond, Position: 12 * time.Second, State: StatePlaying,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			c := NewWebhookClient("", "", "")
+			if rec := postFixture(t, c, tt.path, tt.fixture); rec.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+
+			c.mu.Lock()
+			got := c.track
+			c.mu.Unlock()
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWebhookClient_IgnoresOtherMediaAndUsers(t *testing.T) {
+	c := NewWebhookClient("", "", "elan")
+	if rec := postFixture(t, c, "/plex", "plex_play.json"); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected the followed user's track, got %d", rec.Code)
+	}
+
+	// A movie and another user's music leave the track alone
+	if rec := postFixture(t, c, "/plex", "plex_movie.json"); rec.Code != http.StatusAccepted {
+		t.Errorf("expected a movie to be ignored, got %d", rec.Code)
+	}
+	if rec := postFixture(t, c, "/jellyfin", "jellyfin_stop.json"); rec.Code != http.StatusAccepted {
+		t.Errorf("expected another user's event to be ignored, got %d", rec.Code)
+	}
+
+	track, err := c.GetCurrentTrack(context.Background())
+	if err != nil || track == nil || track.Name != "Love The One You're With" {
+		t.Errorf("expected the Plex track to remain, got %+v, %v", track, err)
+	}
+}
+
+func TestWebhookClient_RejectsBadRequests(t *testing.T) {
+	c := NewWebhookClient("", "s3cret", "")
+
+	if rec := postFixture(t, c, "/track", "generic_play.json"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without the token, got %d", rec.Code)
+	}
+	if rec := postFixture(t, c, "/track?token=s3cret", "generic_play.json"); rec.Code != http.StatusNoContent {
+		t.Errorf("expected the token to be accepted, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/track", strings.NewReader(`{"event": "rewind", "track": "T", "artist": "A"}`))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "unknown event") {
+		t.Errorf("expected an unknown event to be rejected, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestWebhookClient_GetCurrentTrackAdvancesPosition(t *testing.T) {
+	c := NewWebhookClient("", "", "")
+	c.track = &Track{Name: "Song", Duration: 3 * time.Minute, Position: 10 * time.Second, State: StatePlaying}
+	c.receivedAt = time.Now().Add(-20 * time.Second)
+
+	track, _ := c.GetCurrentTrack(context.Background())
+	if track == nil || track.Position < 30*time.Second || track.Position > 31*time.Second {
+		t.Errorf("expected the position to advance to ~30s, got %+v", track)
+	}
+
+	// A player that never said it stopped
+	c.receivedAt = time.Now().Add(-time.Hour)
+	if track, _ := c.GetCurrentTrack(context.Background()); track != nil {
+		t.Errorf("expected a long-finished track to be dropped, got %+v", track)
+	}
+}
+
+func TestWebhookClient_Watch(t *testing.T) {
+	// Find a free port for Watch to listen on
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	address := ln.Addr().String()
+	_ = ln.Close()
+
+	c := NewWebhookClient(address, "", "")
+	ctx, cancel := context.WithCancel(context.Background())
+	tracks := make(chan *Track)
+	done := make(chan error, 1)
+	go func() { done <- c.Watch(ctx, tracks) }()
+
+	next := func() *Track {
+		t.Helper()
+		select {
+		case track := <-tracks:
+			return track
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for a track")
+			return nil
+		}
+	}
+
+	if track := next(); track != nil {
+		t.Fatalf("expected no track before any event, got %+v", track)
+	}
+
+	resp, err := http.Post("http://"+address+"/track", "application/json",
+		strings.NewReader(`{"event": "play", "track": "Teardrop", "artist": "Massive Attack", "duration": 330}`))
+	if err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	_ = resp.Body.Close()
+	if track := next(); track == nil || track.Name != "Teardrop" {
+		t.Errorf("expected the posted track, got %+v", track)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch did not return after cancel")
+	}
+}
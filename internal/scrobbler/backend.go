@@ -0,0 +1,43 @@
+package scrobbler
+
+import (
+	"context"
+	"time"
+)
+
+// Backend is a scrobbling service such as Last.fm or ListenBrainz. The
+// daemon sends now playing updates straight to its backend and submits
+// scrobbles from the Queue in batches.
+type Backend interface {
+	// Name identifies the backend in logs and in the queue, e.g. "lastfm"
+	Name() string
+
+	// UpdateNowPlaying announces the track that has just started playing
+	UpdateNowPlaying(ctx context.Context, artist, track, album string, duration time.Duration) error
+
+	// ScrobbleTrack submits a single scrobble
+	ScrobbleTrack(ctx context.Context, artist, track, album string, timestamp time.Time, duration time.Duration) error
+
+	// ScrobbleBatch submits up to MaxBatchSize scrobbles and returns a
+	// result for each one, in order. A returned error means the request
+	// as a whole failed and no results are available.
+	ScrobbleBatch(ctx context.Context, scrobbles []Scrobble) ([]ScrobbleResult, error)
+}
+
+// Lover is implemented by backends that can mark a track as loved, such
+// as Last.fm and the Audioscrobbler-compatible profiles
+type Lover interface {
+	LoveTrack(ctx context.Context, artist, track string) error
+}
+
+// MaxBatchSize is the largest batch every backend accepts in one request.
+// Last.fm allows 50 scrobbles per track.scrobble call.
+const MaxBatchSize = 50
+
+var (
+	_ Backend = (*Client)(nil)
+	_ Backend = (*ListenBrainz)(nil)
+	_ Backend = (*Webhook)(nil)
+
+	_ Lover = (*Client)(nil)
+)
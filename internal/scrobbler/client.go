@@ -8,42 +8,63 @@ import (
 	"github.com/jfmyers9/scribbles/pkg/lastfm"
 )
 
-// Client wraps the Last.fm API client
+// Client wraps the Last.fm API client. It also talks to other Audioscrobbler
+// 2.0 compatible servers, such as Libre.fm, GNU FM and Maloja.
 type Client struct {
 	client *lastfm.Client
+	name   string
+}
+
+// Endpoint points a Client at an Audioscrobbler 2.0 compatible server.
+// Zero fields default to Last.fm.
+type Endpoint struct {
+	Name    string // Backend name, e.g. the profile name (default "lastfm")
+	APIURL  string // API root, e.g. https://libre.fm/2.0/
+	AuthURL string // Page where users authorize tokens, e.g. https://libre.fm/api/auth/
 }
 
 // New creates a new Last.fm client
 func New(apiKey, apiSecret string) *Client {
-	client, err := lastfm.NewClient(lastfm.Config{
-		APIKey:    apiKey,
-		APISecret: apiSecret,
-	})
-	if err != nil {
-		// This should never happen since we validate the inputs
-		panic(fmt.Sprintf("failed to create lastfm client: %v", err))
-	}
-	return &Client{
-		client: client,
-	}
+	return NewForEndpoint(Endpoint{}, apiKey, apiSecret, "")
 }
 
 // NewWithSession creates a new Last.fm client with an existing session key
 func NewWithSession(apiKey, apiSecret, sessionKey string) *Client {
+	return NewForEndpoint(Endpoint{}, apiKey, apiSecret, sessionKey)
+}
+
+// NewForEndpoint creates a client for an Audioscrobbler 2.0 compatible
+// server, with an existing session key if one is given
+func NewForEndpoint(endpoint Endpoint, apiKey, apiSecret, sessionKey string) *Client {
 	client, err := lastfm.NewClient(lastfm.Config{
 		APIKey:     apiKey,
 		APISecret:  apiSecret,
 		SessionKey: sessionKey,
+		BaseURL:    endpoint.APIURL,
+		AuthURL:    endpoint.AuthURL,
 	})
 	if err != nil {
 		// This should never happen since we validate the inputs
 		panic(fmt.Sprintf("failed to create lastfm client: %v", err))
 	}
+
+	name := endpoint.Name
+	if name == "" {
+		name = "lastfm"
+	}
+
 	return &Client{
 		client: client,
+		name:   name,
 	}
 }
 
+// Name identifies the server as a Backend: "lastfm", or the endpoint's
+// name
+func (c *Client) Name() string {
+	return c.name
+}
+
 // AuthenticateWithToken initiates the authentication flow
 // Returns the auth URL that the user should visit
 func (c *Client) AuthenticateWithToken(ctx context.Context) (token string, authURL string, err error) {
@@ -59,21 +80,22 @@ func (c *Client) AuthenticateWithToken(ctx context.Context) (token string, authU
 }
 
 // GetSession completes the authentication flow after user authorization
-// Returns the session key that should be stored for future use
-func (c *Client) GetSession(ctx context.Context, token string) (sessionKey string, err error) {
+// Returns the session key that should be stored for future use and the
+// name of the user who authorized it
+func (c *Client) GetSession(ctx context.Context, token string) (sessionKey, username string, err error) {
 	session, err := c.client.Auth().GetSession(ctx, token)
 	if err != nil {
-		return "", fmt.Errorf("failed to login with token: %w", err)
+		return "", "", fmt.Errorf("failed to login with token: %w", err)
 	}
 
 	if session.Key == "" {
-		return "", fmt.Errorf("received empty session key")
+		return "", "", fmt.Errorf("received empty session key")
 	}
 
 	// Update the client with the session key
 	c.client.SetSessionKey(session.Key)
 
-	return session.Key, nil
+	return session.Key, session.Username, nil
 }
 
 func (c *Client) UpdateNowPlaying(ctx context.Context, artist, track, album string, duration time.Duration) error {
@@ -121,13 +143,25 @@ func (c *Client) ScrobbleTrack(ctx context.Context, artist, track, album string,
 	return nil
 }
 
-func (c *Client) ScrobbleBatch(ctx context.Context, scrobbles []Scrobble) error {
+// LoveTrack marks a track as loved by the authenticated user
+func (c *Client) LoveTrack(ctx context.Context, artist, track string) error {
+	if err := c.client.Track().Love(ctx, artist, track); err != nil {
+		return fmt.Errorf("failed to love track: %w", err)
+	}
+	return nil
+}
+
+// ScrobbleBatch submits up to 50 scrobbles in one request and returns
+// Last.fm's verdict for each one, in the same order as scrobbles.
+// A returned error means the request as a whole failed and no results
+// are available.
+func (c *Client) ScrobbleBatch(ctx context.Context, scrobbles []Scrobble) ([]ScrobbleResult, error) {
 	if len(scrobbles) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	if len(scrobbles) > 50 {
-		return fmt.Errorf("cannot scrobble more than 50 tracks at once (got %d)", len(scrobbles))
+	if len(scrobbles) > MaxBatchSize {
+		return nil, fmt.Errorf("cannot scrobble more than %d tracks at once (got %d)", MaxBatchSize, len(scrobbles))
 	}
 
 	// Convert internal Scrobble type to pkg/lastfm Scrobble type
@@ -146,14 +180,71 @@ func (c *Client) ScrobbleBatch(ctx context.Context, scrobbles []Scrobble) error
 
 	resp, err := c.client.Scrobble().ScrobbleBatch(ctx, lfmScrobbles)
 	if err != nil {
-		return fmt.Errorf("failed to scrobble batch: %w", err)
+		return nil, fmt.Errorf("failed to scrobble batch: %w", err)
 	}
 
-	if resp.Ignored > 0 {
-		return fmt.Errorf("%d scrobbles were ignored by Last.fm", resp.Ignored)
+	return batchResults(resp, len(scrobbles))
+}
+
+// batchResults maps a track.scrobble response onto per-item results.
+// Last.fm returns one <scrobble> element per submitted item, in order.
+func batchResults(resp *lastfm.ScrobbleResponse, n int) ([]ScrobbleResult, error) {
+	results := make([]ScrobbleResult, n)
+
+	if len(resp.Scrobbles) != n {
+		// Without per-item elements we can only trust an all-accepted batch
+		if resp.Ignored == 0 {
+			for i := range results {
+				results[i].Accepted = true
+			}
+			return results, nil
+		}
+		return nil, &BatchResultsError{Ignored: resp.Ignored, Results: len(resp.Scrobbles), Scrobbles: n}
 	}
 
-	return nil
+	for i, s := range resp.Scrobbles {
+		results[i] = ScrobbleResult{
+			Accepted:       s.IgnoredMessage.Code == 0,
+			IgnoredCode:    s.IgnoredMessage.Code,
+			IgnoredMessage: s.IgnoredMessage.Text,
+		}
+	}
+
+	return results, nil
+}
+
+// BatchResultsError is a batch response that ignored some scrobbles
+// without saying which. It is permanent, so the batch is resubmitted one
+// scrobble at a time and each gets its own outcome.
+type BatchResultsError struct {
+	Ignored   int // Scrobbles Last.fm reported as ignored
+	Results   int // Per-item results in the response
+	Scrobbles int // Scrobbles submitted
+}
+
+// Error returns the error message
+func (e *BatchResultsError) Error() string {
+	return fmt.Sprintf("%d scrobbles were ignored by Last.fm but %d results were returned for %d scrobbles",
+		e.Ignored, e.Results, e.Scrobbles)
+}
+
+// ScrobbleResult is a backend's verdict on a single submitted scrobble
+type ScrobbleResult struct {
+	Accepted       bool
+	IgnoredCode    int    // Last.fm ignore code (lastfm.Ignored*), 0 if accepted
+	IgnoredMessage string // Human-readable reason the scrobble was ignored
+
+	// Err is set when this scrobble alone could not be submitted, by
+	// backends that send each scrobble in its own request. It is handled
+	// like a failed request: retried, or failed if IsPermanentError.
+	Err error
+}
+
+// Retryable reports whether an ignored scrobble may be accepted if
+// submitted again later. Only the daily limit is transient; other ignore
+// reasons will be returned again for the same scrobble.
+func (r ScrobbleResult) Retryable() bool {
+	return r.IgnoredCode == lastfm.IgnoredDailyLimitExceeded
 }
 
 // Scrobble represents a single scrobble to submit
@@ -163,6 +254,7 @@ type Scrobble struct {
 	Album     string
 	Timestamp time.Time
 	Duration  time.Duration
+	Source    string // Music source the play came from, e.g. "apple-music" (empty if unknown)
 }
 
 // IsAuthenticated checks if the client has a valid session
@@ -174,3 +266,9 @@ func (c *Client) IsAuthenticated() bool {
 func (c *Client) GetSessionKey() string {
 	return c.client.GetSessionKey()
 }
+
+// Users returns the Last.fm user service, used to read a user's scrobble
+// history
+func (c *Client) Users() *lastfm.UserService {
+	return c.client.User()
+}
@@ -2,7 +2,13 @@ package scrobbler
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"testing"
+
+	"github.com/jfmyers9/scribbles/pkg/lastfm"
 )
 
 func TestNew(t *testing.T) {
@@ -28,6 +34,41 @@ func TestNewWithSession(t *testing.T) {
 	}
 }
 
+func TestNewForEndpoint(t *testing.T) {
+	// A GNU FM server answering auth.getToken
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.FormValue("method"); got != "auth.getToken" {
+			t.Errorf("expected auth.getToken, got %q", got)
+		}
+		_, _ = fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><lfm status="ok"><token>gnufm-token</token></lfm>`)
+	}))
+	defer server.Close()
+
+	client := NewForEndpoint(Endpoint{
+		Name:    "librefm",
+		APIURL:  server.URL + "/2.0/",
+		AuthURL: "https://libre.fm/api/auth/",
+	}, "test_key", "test_secret", "")
+
+	if client.Name() != "librefm" {
+		t.Errorf("expected backend name librefm, got %q", client.Name())
+	}
+	if New("test_key", "test_secret").Name() != "lastfm" {
+		t.Error("expected the default endpoint to be named lastfm")
+	}
+
+	token, authURL, err := client.AuthenticateWithToken(context.Background())
+	if err != nil {
+		t.Fatalf("AuthenticateWithToken: %v", err)
+	}
+	if token != "gnufm-token" {
+		t.Errorf("expected token from the server, got %q", token)
+	}
+	if want := "https://libre.fm/api/auth/?api_key=test_key&token=gnufm-token"; authURL != want {
+		t.Errorf("expected auth URL %q, got %q", want, authURL)
+	}
+}
+
 // TestAuthenticateWithToken is an integration test that requires valid API credentials
 // Skip in unit tests - use for manual testing
 func TestAuthenticateWithToken(t *testing.T) {
@@ -75,7 +116,7 @@ func TestScrobbleBatch(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			client := NewWithSession("test_key", "test_secret", "test_session")
 			ctx := context.Background()
-			err := client.ScrobbleBatch(ctx, tt.scrobbles)
+			_, err := client.ScrobbleBatch(ctx, tt.scrobbles)
 
 			if tt.expectError {
 				if err == nil {
@@ -93,6 +134,69 @@ func TestScrobbleBatch(t *testing.T) {
 	}
 }
 
+// scrobbleResponse builds a lastfm.ScrobbleResponse with one <scrobble>
+// element per ignore code (0 for accepted)
+func scrobbleResponse(codes ...int) *lastfm.ScrobbleResponse {
+	resp := &lastfm.ScrobbleResponse{}
+	resp.Scrobbles = slices.Grow(resp.Scrobbles, len(codes))[:len(codes)]
+	for i, code := range codes {
+		if code == 0 {
+			resp.Accepted++
+			continue
+		}
+		resp.Ignored++
+		resp.Scrobbles[i].IgnoredMessage.Code = code
+		resp.Scrobbles[i].IgnoredMessage.Text = fmt.Sprintf("ignored with code %d", code)
+	}
+	return resp
+}
+
+func TestBatchResults(t *testing.T) {
+	t.Run("per-item results", func(t *testing.T) {
+		resp := scrobbleResponse(0, lastfm.IgnoredTimestampTooOld, lastfm.IgnoredDailyLimitExceeded)
+
+		results, err := batchResults(resp, 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !results[0].Accepted || results[0].IgnoredCode != 0 {
+			t.Errorf("expected first scrobble accepted, got %+v", results[0])
+		}
+		if results[1].Accepted || results[1].IgnoredCode != lastfm.IgnoredTimestampTooOld || results[1].IgnoredMessage != "ignored with code 3" {
+			t.Errorf("expected second scrobble ignored as too old, got %+v", results[1])
+		}
+		if results[1].Retryable() {
+			t.Error("expected too-old scrobble not to be retryable")
+		}
+		if results[2].Accepted || !results[2].Retryable() {
+			t.Errorf("expected daily limit to be retryable, got %+v", results[2])
+		}
+	})
+
+	t.Run("all accepted without items", func(t *testing.T) {
+		results, err := batchResults(&lastfm.ScrobbleResponse{Accepted: 2}, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i, r := range results {
+			if !r.Accepted {
+				t.Errorf("expected result %d accepted", i)
+			}
+		}
+	})
+
+	t.Run("ignored without items", func(t *testing.T) {
+		_, err := batchResults(&lastfm.ScrobbleResponse{Accepted: 1, Ignored: 1}, 2)
+		if err == nil {
+			t.Fatal("expected error when ignored scrobbles cannot be matched to items")
+		}
+		if !IsPermanentError(err) {
+			t.Errorf("expected a permanent error so the batch is split, got %v", err)
+		}
+	})
+}
+
 func TestIsAuthenticated(t *testing.T) {
 	tests := []struct {
 		name          string
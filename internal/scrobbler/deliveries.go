@@ -0,0 +1,559 @@
+package scrobbler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultBackend is the backend a queue delivers to until SetBackends is
+// called. Queues created before per-backend delivery only knew Last.fm.
+const DefaultBackend = "lastfm"
+
+// errBackendRemoved is recorded on pending deliveries whose backend is no
+// longer configured
+const errBackendRemoved = "backend is no longer configured"
+
+// Delivery is the state of a queued scrobble with one backend
+type Delivery struct {
+	Backend        string
+	Status         Status
+	Attempts       int       // Failed submissions to this backend
+	NextAttemptAt  time.Time // Not retried before this time after a failure
+	Error          string
+	IgnoredCode    int
+	IgnoredMessage string
+}
+
+// Owed returns the backends the scrobble is still pending for
+func (s QueuedScrobble) Owed() []string {
+	var owed []string
+	for _, d := range s.Deliveries {
+		if d.Status == StatusPending {
+			owed = append(owed, d.Backend)
+		}
+	}
+	return owed
+}
+
+// Deliveries is the queue as seen by one backend. Its GetPending only
+// returns scrobbles that backend is owed, and its updates only change that
+// backend's delivery, so one backend failing never holds up another.
+type Deliveries struct {
+	q       *Queue
+	backend string // Empty to act on every backend
+}
+
+// For returns the view of the queue for the named backend
+func (q *Queue) For(backend string) *Deliveries {
+	return &Deliveries{q: q, backend: backend}
+}
+
+// all returns a view that acts on every backend of a scrobble
+func (q *Queue) all() *Deliveries {
+	return &Deliveries{q: q}
+}
+
+// Backend returns the name of the backend the view acts on
+func (d *Deliveries) Backend() string {
+	return d.backend
+}
+
+// Backends returns the backends new scrobbles are owed to
+func (q *Queue) Backends() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return append([]string(nil), q.backends...)
+}
+
+// SetBackends records the backends new scrobbles are owed to. The list is
+// stored with the queue, so other processes adding scrobbles, such as an
+// import, use the daemon's backends. Pending deliveries to backends that
+// are no longer listed are marked failed, where Retry can revive them.
+// Backends that are new to the list are only owed scrobbles added from now
+// on.
+func (q *Queue) SetBackends(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		return fmt.Errorf("at least one backend is required")
+	}
+	for _, name := range names {
+		if name == "" {
+			return fmt.Errorf("backend name cannot be empty")
+		}
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ")
+	args := make([]any, len(names))
+	for i, name := range names {
+		args[i] = name
+	}
+
+	err := q.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM backends"); err != nil {
+			return fmt.Errorf("failed to clear backends: %w", err)
+		}
+		for _, name := range names {
+			if _, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO backends (name) VALUES (?)", name); err != nil {
+				return fmt.Errorf("failed to save backend: %w", err)
+			}
+		}
+
+		ids, err := scrobbleIDs(ctx, tx, `
+			SELECT DISTINCT scrobble_id FROM deliveries
+			WHERE status = 'pending' AND backend NOT IN (`+placeholders+`)
+		`, args...)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE deliveries
+			SET status = 'failed', error = ?, updated_at = strftime('%s', 'now')
+			WHERE status = 'pending' AND backend NOT IN (` + placeholders + `)
+		`
+		if _, err := tx.ExecContext(ctx, query, append([]any{errBackendRemoved}, args...)...); err != nil {
+			return fmt.Errorf("failed to retire deliveries: %w", err)
+		}
+
+		return refreshSummaries(ctx, tx, ids)
+	})
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	q.backends = append([]string(nil), names...)
+	q.mu.Unlock()
+
+	return nil
+}
+
+// GetPending retrieves scrobbles the backend is owed that are due for
+// submission, ordered by timestamp. The scrobbles' state fields are their
+// summary across backends; see Deliveries for this backend's own state.
+func (d *Deliveries) GetPending(ctx context.Context, limit int) ([]QueuedScrobble, error) {
+	if d.backend == "" {
+		return d.q.GetPending(ctx, limit)
+	}
+
+	query := `
+		SELECT ` + queuedScrobbleColumns + `
+		FROM scrobbles
+		WHERE id IN (
+			SELECT scrobble_id FROM deliveries
+			WHERE backend = ? AND status = 'pending' AND next_attempt_at <= ?
+		)
+		ORDER BY timestamp ASC
+	`
+
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	scrobbles, err := d.q.query(ctx, query, d.backend, d.q.now().Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending scrobbles: %w", err)
+	}
+
+	return scrobbles, nil
+}
+
+// MarkScrobbled marks a scrobble as accepted by the backend
+func (d *Deliveries) MarkScrobbled(ctx context.Context, id int64) error {
+	return d.update(ctx, id, "mark scrobble as scrobbled", `
+		SET status = 'scrobbled', error = NULL
+	`, "")
+}
+
+// MarkScrobbledBatch marks multiple scrobbles as accepted by the backend
+func (d *Deliveries) MarkScrobbledBatch(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	scope, scopeArgs := d.scope()
+	query := `
+		UPDATE deliveries
+		SET status = 'scrobbled', error = NULL, updated_at = strftime('%s', 'now')
+		WHERE scrobble_id = ?` + scope
+
+	return d.q.inTx(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to prepare statement: %w", err)
+		}
+		defer func() { _ = stmt.Close() }()
+
+		for _, id := range ids {
+			if _, err := stmt.ExecContext(ctx, append([]any{id}, scopeArgs...)...); err != nil {
+				return fmt.Errorf("failed to mark scrobble %d: %w", id, err)
+			}
+		}
+
+		return refreshSummaries(ctx, tx, ids)
+	})
+}
+
+// MarkError records a failed submission attempt with its error message.
+// The delivery is retried after an exponential backoff; once the retry
+// policy's MaxAttempts is reached it is moved to the failed state instead.
+func (d *Deliveries) MarkError(ctx context.Context, id int64, errMsg string) error {
+	return d.markError(ctx, id, errMsg, true)
+}
+
+// MarkTransientError records a submission attempt that failed with a
+// transient error (see IsTransientError). The delivery is retried after
+// the same backoff as MarkError, but the attempt doesn't count toward
+// MaxAttempts. It only moves to the failed state once the scrobble is
+// older than the retry policy's MaxAge.
+func (d *Deliveries) MarkTransientError(ctx context.Context, id int64, errMsg string) error {
+	return d.markError(ctx, id, errMsg, false)
+}
+
+// markError records a failed attempt, counting it toward MaxAttempts if
+// counted is set
+func (d *Deliveries) markError(ctx context.Context, id int64, errMsg string, counted bool) error {
+	scope, scopeArgs := d.scope()
+	query := `
+		SELECT backend, attempts, failures FROM deliveries
+		WHERE scrobble_id = ? AND status = 'pending'` + scope
+
+	return d.q.inTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query, append([]any{id}, scopeArgs...)...)
+		if err != nil {
+			return fmt.Errorf("failed to read scrobble attempts: %w", err)
+		}
+
+		type count struct{ attempts, failures int }
+		counts := make(map[string]count)
+		for rows.Next() {
+			var backend string
+			var c count
+			if err := rows.Scan(&backend, &c.attempts, &c.failures); err != nil {
+				_ = rows.Close()
+				return fmt.Errorf("failed to scan delivery: %w", err)
+			}
+			counts[backend] = c
+		}
+		if err := rows.Err(); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("error iterating deliveries: %w", err)
+		}
+		_ = rows.Close()
+
+		if len(counts) == 0 {
+			return d.missing(ctx, tx, id)
+		}
+
+		retry := d.q.retry
+		expired := false
+		if !counted && retry.MaxAge > 0 {
+			var timestamp int64
+			if err := tx.QueryRowContext(ctx, "SELECT timestamp FROM scrobbles WHERE id = ?", id).Scan(&timestamp); err != nil {
+				return fmt.Errorf("failed to read scrobble timestamp: %w", err)
+			}
+			expired = d.q.now().Sub(time.Unix(timestamp, 0)) >= retry.MaxAge
+		}
+
+		for backend, c := range counts {
+			c.attempts++
+			if counted {
+				c.failures++
+			}
+			status := StatusPending
+			nextAttempt := d.q.now().Add(retry.Backoff(c.attempts)).Unix()
+			if expired || (retry.MaxAttempts > 0 && c.failures >= retry.MaxAttempts) {
+				status = StatusFailed
+			}
+
+			update := `
+				UPDATE deliveries
+				SET status = ?, error = ?, attempts = ?, failures = ?, next_attempt_at = ?, updated_at = strftime('%s', 'now')
+				WHERE scrobble_id = ? AND backend = ?
+			`
+			if _, err := tx.ExecContext(ctx, update, status, errMsg, c.attempts, c.failures, nextAttempt, id, backend); err != nil {
+				return fmt.Errorf("failed to mark scrobble error: %w", err)
+			}
+		}
+
+		return refreshSummary(ctx, tx, id)
+	})
+}
+
+// MarkFailed moves the delivery straight to the failed state without
+// further retries. Use it for permanent errors (see IsPermanentError).
+// Deliveries that already succeeded are left alone.
+func (d *Deliveries) MarkFailed(ctx context.Context, id int64, errMsg string) error {
+	return d.update(ctx, id, "mark scrobble as failed", `
+		SET status = 'failed', error = ?, attempts = attempts + 1
+	`, " AND status != 'scrobbled'", errMsg)
+}
+
+// MarkIgnored records that the backend ignored a scrobble, with its ignore
+// code and message. Ignored deliveries are no longer pending.
+func (d *Deliveries) MarkIgnored(ctx context.Context, id int64, code int, message string) error {
+	return d.update(ctx, id, "mark scrobble as ignored", `
+		SET status = 'ignored', ignored_code = ?, ignored_message = ?, error = NULL
+	`, " AND status != 'scrobbled'", code, message)
+}
+
+// update applies a SET clause to the scrobble's deliveries in scope,
+// narrowed by an extra condition, and refreshes the scrobble's summary
+func (d *Deliveries) update(ctx context.Context, id int64, action, set, cond string, args ...any) error {
+	scope, scopeArgs := d.scope()
+	query := `UPDATE deliveries ` + set + `, updated_at = strftime('%s', 'now')
+		WHERE scrobble_id = ?` + cond + scope
+
+	args = append(append(args, id), scopeArgs...)
+
+	return d.q.inTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to %s: %w", action, err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rows == 0 {
+			return d.missing(ctx, tx, id)
+		}
+
+		return refreshSummary(ctx, tx, id)
+	})
+}
+
+// scope returns the condition restricting deliveries to the view's backend
+func (d *Deliveries) scope() (string, []any) {
+	if d.backend == "" {
+		return "", nil
+	}
+	return " AND backend = ?", []any{d.backend}
+}
+
+// missing explains why an update matched no deliveries: the scrobble does
+// not exist, or is not queued for the backend. Otherwise there was simply
+// nothing left to update.
+func (d *Deliveries) missing(ctx context.Context, tx *sql.Tx, id int64) error {
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM scrobbles WHERE id = ?)", id).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to look up scrobble: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("scrobble with id %d not found", id)
+	}
+
+	if d.backend != "" {
+		query := "SELECT EXISTS (SELECT 1 FROM deliveries WHERE scrobble_id = ? AND backend = ?)"
+		if err := tx.QueryRowContext(ctx, query, id, d.backend).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to look up delivery: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("scrobble with id %d is not queued for %s", id, d.backend)
+		}
+	}
+
+	return nil
+}
+
+// summaryQuery recomputes a scrobble's state columns from its deliveries,
+// so that filters, counts and cleanup can keep working on scrobbles alone.
+// Errors and ignore details come from the first backend by name that has
+// them.
+const summaryQuery = `
+	UPDATE scrobbles SET
+		scrobbled = NOT EXISTS (
+			SELECT 1 FROM deliveries d WHERE d.scrobble_id = scrobbles.id AND d.status != 'scrobbled'
+		),
+		failed = NOT EXISTS (
+			SELECT 1 FROM deliveries d WHERE d.scrobble_id = scrobbles.id AND d.status = 'pending'
+		) AND EXISTS (
+			SELECT 1 FROM deliveries d WHERE d.scrobble_id = scrobbles.id AND d.status = 'failed'
+		),
+		ignored = NOT EXISTS (
+			SELECT 1 FROM deliveries d WHERE d.scrobble_id = scrobbles.id AND d.status IN ('pending', 'failed')
+		) AND EXISTS (
+			SELECT 1 FROM deliveries d WHERE d.scrobble_id = scrobbles.id AND d.status = 'ignored'
+		),
+		attempts = COALESCE((
+			SELECT MAX(d.attempts) FROM deliveries d WHERE d.scrobble_id = scrobbles.id
+		), 0),
+		next_attempt_at = COALESCE((
+			SELECT MIN(d.next_attempt_at) FROM deliveries d WHERE d.scrobble_id = scrobbles.id AND d.status = 'pending'
+		), 0),
+		error = (
+			SELECT d.error FROM deliveries d
+			WHERE d.scrobble_id = scrobbles.id AND d.status != 'scrobbled' AND d.error IS NOT NULL
+			ORDER BY d.backend LIMIT 1
+		),
+		ignored_code = (
+			SELECT d.ignored_code FROM deliveries d
+			WHERE d.scrobble_id = scrobbles.id AND d.status = 'ignored'
+			ORDER BY d.backend LIMIT 1
+		),
+		ignored_message = (
+			SELECT d.ignored_message FROM deliveries d
+			WHERE d.scrobble_id = scrobbles.id AND d.status = 'ignored'
+			ORDER BY d.backend LIMIT 1
+		)
+	WHERE id = ?
+`
+
+// refreshSummary recomputes a scrobble's state columns from its deliveries
+func refreshSummary(ctx context.Context, tx *sql.Tx, id int64) error {
+	if _, err := tx.ExecContext(ctx, summaryQuery, id); err != nil {
+		return fmt.Errorf("failed to update scrobble %d: %w", id, err)
+	}
+	return nil
+}
+
+// refreshSummaries recomputes the state columns of several scrobbles
+func refreshSummaries(ctx context.Context, tx *sql.Tx, ids []int64) error {
+	for _, id := range ids {
+		if err := refreshSummary(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadDeliveries fills in the Deliveries of each scrobble
+func (q *Queue) loadDeliveries(ctx context.Context, scrobbles []QueuedScrobble) error {
+	// Keep well under SQLite's limit on bound parameters
+	const chunkSize = 500
+
+	index := make(map[int64]int, len(scrobbles))
+	for i, s := range scrobbles {
+		index[s.ID] = i
+	}
+
+	for start := 0; start < len(scrobbles); start += chunkSize {
+		chunk := scrobbles[start:min(start+chunkSize, len(scrobbles))]
+		args := make([]any, len(chunk))
+		for i, s := range chunk {
+			args[i] = s.ID
+		}
+
+		query := `
+			SELECT scrobble_id, backend, status, attempts, next_attempt_at, COALESCE(error, ''),
+				COALESCE(ignored_code, 0), COALESCE(ignored_message, '')
+			FROM deliveries
+			WHERE scrobble_id IN (` + strings.TrimSuffix(strings.Repeat("?, ", len(chunk)), ", ") + `)
+			ORDER BY backend
+		`
+
+		if err := q.scanDeliveries(ctx, query, args, scrobbles, index); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scanDeliveries runs a deliveries query and attaches each row to its
+// scrobble
+func (q *Queue) scanDeliveries(ctx context.Context, query string, args []any, scrobbles []QueuedScrobble, index map[int64]int) error {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query deliveries: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var id int64
+		var d Delivery
+		var nextAttemptUnix int64
+		err := rows.Scan(&id, &d.Backend, &d.Status, &d.Attempts, &nextAttemptUnix,
+			&d.Error, &d.IgnoredCode, &d.IgnoredMessage)
+		if err != nil {
+			return fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		if nextAttemptUnix > 0 {
+			d.NextAttemptAt = time.Unix(nextAttemptUnix, 0)
+		}
+
+		i := index[id]
+		scrobbles[i].Deliveries = append(scrobbles[i].Deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// loadBackends reads the backends new scrobbles are owed to
+func loadBackends(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("SELECT name FROM backends ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backends: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var backends []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan backend: %w", err)
+		}
+		backends = append(backends, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating backends: %w", err)
+	}
+
+	if len(backends) == 0 {
+		backends = []string{DefaultBackend}
+	}
+
+	return backends, nil
+}
+
+// scrobbleIDs runs a query selecting scrobble IDs
+func scrobbleIDs(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scrobbles: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan scrobble id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scrobbles: %w", err)
+	}
+
+	return ids, nil
+}
+
+// inTx runs fn in a transaction, committing if it succeeds
+func (q *Queue) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := q.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+package scrobbler
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createMultiBackendQueue creates a test queue that delivers to Last.fm and
+// ListenBrainz
+func createMultiBackendQueue(t *testing.T) *Queue {
+	t.Helper()
+
+	queue := createTestQueue(t)
+	if err := queue.SetBackends(context.Background(), []string{"lastfm", "listenbrainz"}); err != nil {
+		t.Fatalf("failed to set backends: %v", err)
+	}
+	return queue
+}
+
+func addTestScrobble(t *testing.T, queue *Queue, track string) int64 {
+	t.Helper()
+
+	id, err := queue.Add(context.Background(), Scrobble{
+		Artist:    "Cher",
+		Track:     track,
+		Duration:  4 * time.Minute,
+		Timestamp: time.Now().Add(-time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("failed to add scrobble: %v", err)
+	}
+	return id
+}
+
+func getTestScrobble(t *testing.T, queue *Queue, id int64) *QueuedScrobble {
+	t.Helper()
+
+	s, err := queue.Get(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to get scrobble: %v", err)
+	}
+	return s
+}
+
+func TestDeliveriesAreIndependent(t *testing.T) {
+	queue := createMultiBackendQueue(t)
+	ctx := context.Background()
+	id := addTestScrobble(t, queue, "Believe")
+
+	lastFM := queue.For("lastfm")
+	listenBrainz := queue.For("listenbrainz")
+
+	if err := lastFM.MarkScrobbledBatch(ctx, []int64{id}); err != nil {
+		t.Fatalf("failed to mark scrobbled: %v", err)
+	}
+
+	s := getTestScrobble(t, queue, id)
+	if s.Status() != StatusPending {
+		t.Errorf("expected scrobble to stay pending until every backend has it, got %s", s.Status())
+	}
+	if owed := s.Owed(); !slices.Equal(owed, []string{"listenbrainz"}) {
+		t.Errorf("expected only listenbrainz to be owed, got %v", owed)
+	}
+
+	pending, err := lastFM.GetPending(ctx, 0)
+	if err != nil {
+		t.Fatalf("failed to get lastfm pending: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected nothing pending for lastfm, got %d", len(pending))
+	}
+
+	// A failure with one backend backs off that backend only
+	if err := listenBrainz.MarkError(ctx, id, "service unavailable"); err != nil {
+		t.Fatalf("failed to mark error: %v", err)
+	}
+	s = getTestScrobble(t, queue, id)
+	if s.Deliveries[0].Status != StatusScrobbled || s.Deliveries[0].Attempts != 0 {
+		t.Errorf("expected lastfm delivery to be untouched, got %+v", s.Deliveries[0])
+	}
+	if d := s.Deliveries[1]; d.Attempts != 1 || d.Error != "service unavailable" || d.NextAttemptAt.IsZero() {
+		t.Errorf("expected listenbrainz delivery to back off, got %+v", d)
+	}
+	if s.Error != "service unavailable" || s.Attempts != 1 {
+		t.Errorf("expected the summary to carry the error, got %+v", s)
+	}
+
+	pending, err = listenBrainz.GetPending(ctx, 0)
+	if err != nil {
+		t.Fatalf("failed to get listenbrainz pending: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected listenbrainz to wait out its backoff, got %d pending", len(pending))
+	}
+
+	queue.now = func() time.Time { return time.Now().Add(time.Hour) }
+	pending, err = listenBrainz.GetPending(ctx, 0)
+	if err != nil {
+		t.Fatalf("failed to get listenbrainz pending: %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != id {
+		t.Fatalf("expected the scrobble to be due for listenbrainz, got %+v", pending)
+	}
+
+	if err := listenBrainz.MarkScrobbled(ctx, id); err != nil {
+		t.Fatalf("failed to mark scrobbled: %v", err)
+	}
+	s = getTestScrobble(t, queue, id)
+	if !s.Scrobbled || s.Error != "" || len(s.Owed()) != 0 {
+		t.Errorf("expected scrobble to be delivered everywhere, got %+v", s)
+	}
+}
+
+func TestDeliveriesSummary(t *testing.T) {
+	queue := createMultiBackendQueue(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		mark   func(id int64) error
+		status Status
+	}{
+		{
+			name: "scrobbled and ignored",
+			mark: func(id int64) error {
+				if err := queue.For("lastfm").MarkScrobbled(ctx, id); err != nil {
+					return err
+				}
+				return queue.For("listenbrainz").MarkIgnored(ctx, id, 1, "Artist ignored")
+			},
+			status: StatusIgnored,
+		},
+		{
+			name: "ignored and failed",
+			mark: func(id int64) error {
+				if err := queue.For("lastfm").MarkIgnored(ctx, id, 3, "Timestamp too old"); err != nil {
+					return err
+				}
+				return queue.For("listenbrainz").MarkFailed(ctx, id, "bad request")
+			},
+			status: StatusFailed,
+		},
+		{
+			name: "failed and pending",
+			mark: func(id int64) error {
+				return queue.For("lastfm").MarkFailed(ctx, id, "bad request")
+			},
+			status: StatusPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := addTestScrobble(t, queue, tt.name)
+			if err := tt.mark(id); err != nil {
+				t.Fatalf("failed to mark deliveries: %v", err)
+			}
+			if got := getTestScrobble(t, queue, id).Status(); got != tt.status {
+				t.Errorf("expected %s, got %s", tt.status, got)
+			}
+		})
+	}
+}
+
+func TestDeliveriesErrors(t *testing.T) {
+	queue := createTestQueue(t)
+	ctx := context.Background()
+	id := addTestScrobble(t, queue, "Believe")
+
+	err := queue.For("listenbrainz").MarkScrobbled(ctx, id)
+	if err == nil || !strings.Contains(err.Error(), "not queued for listenbrainz") {
+		t.Errorf("expected not-queued error, got %v", err)
+	}
+
+	if err := queue.For("lastfm").MarkFailed(ctx, 999, "bad request"); err == nil {
+		t.Error("expected error for a missing scrobble")
+	}
+
+	// Once delivered, a backend's delivery can no longer fail
+	if err := queue.For("lastfm").MarkScrobbled(ctx, id); err != nil {
+		t.Fatalf("failed to mark scrobbled: %v", err)
+	}
+	if err := queue.For("lastfm").MarkFailed(ctx, id, "late failure"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := getTestScrobble(t, queue, id); !s.Scrobbled {
+		t.Errorf("expected scrobble to stay scrobbled, got %+v", s)
+	}
+}
+
+func TestQueueSetBackends(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "queue.db")
+	ctx := context.Background()
+
+	queue, err := NewQueue(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create queue: %v", err)
+	}
+	old := addTestScrobble(t, queue, "Believe")
+
+	if err := queue.SetBackends(ctx, nil); err == nil {
+		t.Error("expected error for no backends")
+	}
+	if err := queue.SetBackends(ctx, []string{"listenbrainz"}); err != nil {
+		t.Fatalf("failed to set backends: %v", err)
+	}
+	_ = queue.Close()
+
+	// The backends are stored with the queue
+	queue, err = NewQueue(dbPath)
+	if err != nil {
+		t.Fatalf("failed to reopen queue: %v", err)
+	}
+	defer func() { _ = queue.Close() }()
+
+	if got := queue.Backends(); !slices.Equal(got, []string{"listenbrainz"}) {
+		t.Errorf("expected stored backends, got %v", got)
+	}
+
+	// Pending deliveries to a removed backend are retired, not lost
+	s := getTestScrobble(t, queue, old)
+	if s.Status() != StatusFailed || s.Error != errBackendRemoved {
+		t.Errorf("expected retired delivery to be failed, got %+v", s)
+	}
+
+	id := addTestScrobble(t, queue, "Strong Enough")
+	s = getTestScrobble(t, queue, id)
+	if len(s.Deliveries) != 1 || s.Deliveries[0].Backend != "listenbrainz" {
+		t.Errorf("expected new scrobble to be owed to listenbrainz only, got %+v", s.Deliveries)
+	}
+
+	// Retry revives every undelivered backend
+	if err := queue.Retry(ctx, old); err != nil {
+		t.Fatalf("failed to retry: %v", err)
+	}
+	if s := getTestScrobble(t, queue, old); s.Status() != StatusPending || s.Error != "" {
+		t.Errorf("expected retried scrobble to be pending, got %+v", s)
+	}
+}
@@ -57,7 +57,7 @@ func TestIntegration_GetSession(t *testing.T) {
 	client := New(apiKey, apiSecret)
 	ctx := context.Background()
 
-	sessionKey, err := client.GetSession(ctx, token)
+	sessionKey, username, err := client.GetSession(ctx, token)
 	if err != nil {
 		t.Fatalf("Failed to get session: %v", err)
 	}
@@ -66,7 +66,7 @@ func TestIntegration_GetSession(t *testing.T) {
 		t.Error("Expected non-empty session key")
 	}
 
-	t.Logf("Session key: %s", sessionKey)
+	t.Logf("Session key: %s (user %s)", sessionKey, username)
 	t.Log("Save this session key for future tests")
 }
 
@@ -152,10 +152,15 @@ func TestIntegration_ScrobbleBatch(t *testing.T) {
 		},
 	}
 
-	err := client.ScrobbleBatch(ctx, scrobbles)
+	results, err := client.ScrobbleBatch(ctx, scrobbles)
 	if err != nil {
 		t.Fatalf("Failed to scrobble batch: %v", err)
 	}
+	for i, r := range results {
+		if !r.Accepted {
+			t.Errorf("Scrobble %d was ignored: %d %s", i, r.IgnoredCode, r.IgnoredMessage)
+		}
+	}
 
 	t.Logf("Successfully scrobbled %d tracks", len(scrobbles))
 }
@@ -0,0 +1,247 @@
+package scrobbler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultListenBrainzURL is the root of the ListenBrainz API
+const DefaultListenBrainzURL = "https://api.listenbrainz.org"
+
+// ListenBrainz listen types for submit-listens
+const (
+	listenTypeSingle     = "single"
+	listenTypePlayingNow = "playing_now"
+	listenTypeImport     = "import"
+)
+
+// ListenBrainzConfig holds ListenBrainz client configuration
+type ListenBrainzConfig struct {
+	Token      string       // Required: user token from https://listenbrainz.org/settings/
+	BaseURL    string       // Optional: API root (defaults to DefaultListenBrainzURL)
+	HTTPClient *http.Client // Optional: HTTP client (defaults to a client with a 30s timeout)
+}
+
+// ListenBrainz submits listens to the ListenBrainz API, or a compatible
+// server, with a user token
+type ListenBrainz struct {
+	token      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// ListenBrainzError is an error response from the ListenBrainz API
+type ListenBrainzError struct {
+	StatusCode int    // HTTP status code
+	Message    string // Error message from ListenBrainz
+}
+
+// Error returns the error message
+func (e *ListenBrainzError) Error() string {
+	return fmt.Sprintf("listenbrainz: HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// NewListenBrainz creates a new ListenBrainz client
+func NewListenBrainz(cfg ListenBrainzConfig) (*ListenBrainz, error) {
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("listenbrainz: token is required")
+	}
+
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultListenBrainzURL
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &ListenBrainz{
+		token:      cfg.Token,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+	}, nil
+}
+
+// Name identifies ListenBrainz as a Backend
+func (lb *ListenBrainz) Name() string {
+	return "listenbrainz"
+}
+
+// lbListen is a listen in a submit-listens payload
+type lbListen struct {
+	ListenedAt    int64           `json:"listened_at,omitempty"`
+	TrackMetadata lbTrackMetadata `json:"track_metadata"`
+}
+
+// lbTrackMetadata describes the track of a listen
+type lbTrackMetadata struct {
+	ArtistName     string           `json:"artist_name"`
+	TrackName      string           `json:"track_name"`
+	ReleaseName    string           `json:"release_name,omitempty"`
+	AdditionalInfo lbAdditionalInfo `json:"additional_info"`
+}
+
+// lbAdditionalInfo holds the optional listen fields scribbles sends
+type lbAdditionalInfo struct {
+	DurationMs       int64  `json:"duration_ms,omitempty"`
+	SubmissionClient string `json:"submission_client"`
+}
+
+// lbSubmission is the body of a submit-listens request
+type lbSubmission struct {
+	ListenType string     `json:"listen_type"`
+	Payload    []lbListen `json:"payload"`
+}
+
+// newListen builds a listen, leaving out the timestamp when it is zero as
+// playing_now requires
+func newListen(artist, track, album string, timestamp time.Time, duration time.Duration) lbListen {
+	listen := lbListen{
+		TrackMetadata: lbTrackMetadata{
+			ArtistName:  artist,
+			TrackName:   track,
+			ReleaseName: album,
+			AdditionalInfo: lbAdditionalInfo{
+				DurationMs:       duration.Milliseconds(),
+				SubmissionClient: "scribbles",
+			},
+		},
+	}
+	if !timestamp.IsZero() {
+		listen.ListenedAt = timestamp.Unix()
+	}
+	return listen
+}
+
+// UpdateNowPlaying submits a playing_now listen
+func (lb *ListenBrainz) UpdateNowPlaying(ctx context.Context, artist, track, album string, duration time.Duration) error {
+	listen := newListen(artist, track, album, time.Time{}, duration)
+	if err := lb.submit(ctx, listenTypePlayingNow, []lbListen{listen}); err != nil {
+		return fmt.Errorf("failed to update now playing: %w", err)
+	}
+	return nil
+}
+
+// ScrobbleTrack submits a single listen
+func (lb *ListenBrainz) ScrobbleTrack(ctx context.Context, artist, track, album string, timestamp time.Time, duration time.Duration) error {
+	listen := newListen(artist, track, album, timestamp, duration)
+	if err := lb.submit(ctx, listenTypeSingle, []lbListen{listen}); err != nil {
+		return fmt.Errorf("failed to scrobble track: %w", err)
+	}
+	return nil
+}
+
+// ScrobbleBatch submits up to MaxBatchSize listens in one request.
+// ListenBrainz accepts or rejects a submission as a whole, so every
+// scrobble is accepted when the request succeeds.
+func (lb *ListenBrainz) ScrobbleBatch(ctx context.Context, scrobbles []Scrobble) ([]ScrobbleResult, error) {
+	if len(scrobbles) == 0 {
+		return nil, nil
+	}
+
+	if len(scrobbles) > MaxBatchSize {
+		return nil, fmt.Errorf("cannot scrobble more than %d tracks at once (got %d)", MaxBatchSize, len(scrobbles))
+	}
+
+	listens := make([]lbListen, len(scrobbles))
+	for i, s := range scrobbles {
+		listens[i] = newListen(s.Artist, s.Track, s.Album, s.Timestamp, s.Duration)
+	}
+
+	listenType := listenTypeImport
+	if len(listens) == 1 {
+		listenType = listenTypeSingle
+	}
+
+	if err := lb.submit(ctx, listenType, listens); err != nil {
+		return nil, fmt.Errorf("failed to scrobble batch: %w", err)
+	}
+
+	results := make([]ScrobbleResult, len(scrobbles))
+	for i := range results {
+		results[i].Accepted = true
+	}
+	return results, nil
+}
+
+// ValidateToken checks the token with ListenBrainz and returns the name
+// of the user it belongs to
+func (lb *ListenBrainz) ValidateToken(ctx context.Context) (string, error) {
+	var resp struct {
+		Valid    bool   `json:"valid"`
+		Message  string `json:"message"`
+		UserName string `json:"user_name"`
+	}
+	if err := lb.do(ctx, http.MethodGet, "/1/validate-token", nil, &resp); err != nil {
+		return "", err
+	}
+
+	if !resp.Valid {
+		return "", fmt.Errorf("listenbrainz: invalid token: %s", resp.Message)
+	}
+
+	return resp.UserName, nil
+}
+
+// submit posts listens to submit-listens
+func (lb *ListenBrainz) submit(ctx context.Context, listenType string, listens []lbListen) error {
+	body, err := json.Marshal(lbSubmission{ListenType: listenType, Payload: listens})
+	if err != nil {
+		return fmt.Errorf("listenbrainz: failed to encode listens: %w", err)
+	}
+
+	return lb.do(ctx, http.MethodPost, "/1/submit-listens", body, nil)
+}
+
+// do sends an authenticated request and decodes a successful JSON
+// response into out, if given. Error responses become *ListenBrainzError.
+func (lb *ListenBrainz) do(ctx context.Context, method, path string, body []byte, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, lb.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("listenbrainz: failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Token "+lb.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := lb.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("listenbrainz: request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("listenbrainz: failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &ListenBrainzError{StatusCode: resp.StatusCode}
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &errResp) == nil && errResp.Error != "" {
+			apiErr.Message = errResp.Error
+		} else {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return apiErr
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("listenbrainz: failed to parse response: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package scrobbler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeListenBrainz is a stand-in for the ListenBrainz API that records
+// submissions and replies with a fixed status
+type fakeListenBrainz struct {
+	status      int
+	body        string
+	submissions []lbSubmission
+}
+
+func (f *fakeListenBrainz) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Token test-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"code": 401, "error": "Invalid authorization token."}`))
+		return
+	}
+
+	switch r.URL.Path {
+	case "/1/validate-token":
+		_, _ = w.Write([]byte(`{"code": 200, "message": "Token valid.", "valid": true, "user_name": "rob"}`))
+		return
+	case "/1/submit-listens":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var sub lbSubmission
+	if err := json.NewDecoder(r.Body).Decode(&sub); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code": 400, "error": "Cannot parse JSON document."}`))
+		return
+	}
+	f.submissions = append(f.submissions, sub)
+
+	if f.status != 0 {
+		w.WriteHeader(f.status)
+		_, _ = w.Write([]byte(f.body))
+		return
+	}
+	_, _ = w.Write([]byte(`{"status": "ok"}`))
+}
+
+// newTestListenBrainz starts a fake ListenBrainz server and returns a
+// client pointed at it
+func newTestListenBrainz(t *testing.T, token string) (*ListenBrainz, *fakeListenBrainz) {
+	t.Helper()
+
+	fake := &fakeListenBrainz{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	lb, err := NewListenBrainz(ListenBrainzConfig{Token: token, BaseURL: server.URL + "/"})
+	if err != nil {
+		t.Fatalf("NewListenBrainz: %v", err)
+	}
+	return lb, fake
+}
+
+func TestNewListenBrainz(t *testing.T) {
+	if _, err := NewListenBrainz(ListenBrainzConfig{}); err == nil {
+		t.Error("expected an error without a token")
+	}
+
+	lb, err := NewListenBrainz(ListenBrainzConfig{Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewListenBrainz: %v", err)
+	}
+	if lb.baseURL != DefaultListenBrainzURL {
+		t.Errorf("expected default base URL, got %q", lb.baseURL)
+	}
+	if lb.Name() != "listenbrainz" {
+		t.Errorf("expected name listenbrainz, got %q", lb.Name())
+	}
+}
+
+func TestListenBrainz_UpdateNowPlaying(t *testing.T) {
+	lb, fake := newTestListenBrainz(t, "test-token")
+
+	err := lb.UpdateNowPlaying(context.Background(), "Cher", "Believe", "Believe", 239*time.Second)
+	if err != nil {
+		t.Fatalf("UpdateNowPlaying: %v", err)
+	}
+
+	if len(fake.submissions) != 1 {
+		t.Fatalf("expected 1 submission, got %d", len(fake.submissions))
+	}
+	sub := fake.submissions[0]
+	if sub.ListenType != "playing_now" || len(sub.Payload) != 1 {
+		t.Fatalf("unexpected submission: %+v", sub)
+	}
+	listen := sub.Payload[0]
+	if listen.ListenedAt != 0 {
+		t.Errorf("playing_now must not have listened_at, got %d", listen.ListenedAt)
+	}
+	meta := listen.TrackMetadata
+	if meta.ArtistName != "Cher" || meta.TrackName != "Believe" || meta.ReleaseName != "Believe" {
+		t.Errorf("unexpected track metadata: %+v", meta)
+	}
+	if meta.AdditionalInfo.DurationMs != 239000 || meta.AdditionalInfo.SubmissionClient != "scribbles" {
+		t.Errorf("unexpected additional info: %+v", meta.AdditionalInfo)
+	}
+}
+
+func TestListenBrainz_ScrobbleTrack(t *testing.T) {
+	lb, fake := newTestListenBrainz(t, "test-token")
+	timestamp := time.Date(2026, 3, 1, 18, 30, 0, 0, time.UTC)
+
+	err := lb.ScrobbleTrack(context.Background(), "Cher", "Believe", "", timestamp, 0)
+	if err != nil {
+		t.Fatalf("ScrobbleTrack: %v", err)
+	}
+
+	sub := fake.submissions[0]
+	if sub.ListenType != "single" || sub.Payload[0].ListenedAt != timestamp.Unix() {
+		t.Errorf("unexpected submission: %+v", sub)
+	}
+}
+
+func TestListenBrainz_ScrobbleBatch(t *testing.T) {
+	lb, fake := newTestListenBrainz(t, "test-token")
+	base := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+
+	scrobbles := []Scrobble{
+		{Artist: "Cher", Track: "Believe", Timestamp: base, Duration: 239 * time.Second},
+		{Artist: "Cher", Track: "Strong Enough", Timestamp: base.Add(4 * time.Minute)},
+	}
+
+	results, err := lb.ScrobbleBatch(context.Background(), scrobbles)
+	if err != nil {
+		t.Fatalf("ScrobbleBatch: %v", err)
+	}
+
+	if len(results) != 2 || !results[0].Accepted || !results[1].Accepted {
+		t.Errorf("expected every scrobble to be accepted, got %+v", results)
+	}
+	sub := fake.submissions[0]
+	if sub.ListenType != "import" || len(sub.Payload) != 2 {
+		t.Fatalf("unexpected submission: %+v", sub)
+	}
+	if sub.Payload[1].ListenedAt != base.Add(4*time.Minute).Unix() {
+		t.Errorf("unexpected listened_at %d", sub.Payload[1].ListenedAt)
+	}
+
+	// Empty and oversized batches never reach the server
+	if results, err := lb.ScrobbleBatch(context.Background(), nil); err != nil || results != nil {
+		t.Errorf("expected empty batch to be a no-op, got %v, %v", results, err)
+	}
+	if _, err := lb.ScrobbleBatch(context.Background(), make([]Scrobble, MaxBatchSize+1)); err == nil {
+		t.Error("expected an error for an oversized batch")
+	}
+	if len(fake.submissions) != 1 {
+		t.Errorf("expected 1 submission, got %d", len(fake.submissions))
+	}
+}
+
+func TestListenBrainz_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     string
+		status    int
+		body      string
+		wantCode  int
+		permanent bool
+	}{
+		{
+			name:     "invalid token",
+			token:    "wrong-token",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:      "bad request",
+			token:     "test-token",
+			status:    http.StatusBadRequest,
+			body:      `{"code": 400, "error": "JSON document must contain track_metadata."}`,
+			wantCode:  http.StatusBadRequest,
+			permanent: true,
+		},
+		{
+			name:     "rate limited",
+			token:    "test-token",
+			status:   http.StatusTooManyRequests,
+			wantCode: http.StatusTooManyRequests,
+		},
+		{
+			name:     "server error",
+			token:    "test-token",
+			status:   http.StatusServiceUnavailable,
+			body:     "<html>Service Unavailable</html>",
+			wantCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lb, fake := newTestListenBrainz(t, tt.token)
+			fake.status = tt.status
+			fake.body = tt.body
+
+			_, err := lb.ScrobbleBatch(context.Background(), []Scrobble{
+				{Artist: "Cher", Track: "Believe", Timestamp: time.Now()},
+			})
+
+			var lbErr *ListenBrainzError
+			if !errors.As(err, &lbErr) {
+				t.Fatalf("expected a ListenBrainzError, got %v", err)
+			}
+			if lbErr.StatusCode != tt.wantCode || lbErr.Message == "" {
+				t.Errorf("unexpected error: %+v", lbErr)
+			}
+			if got := IsPermanentError(err); got != tt.permanent {
+				t.Errorf("IsPermanentError() = %v, want %v", got, tt.permanent)
+			}
+		})
+	}
+}
+
+func TestListenBrainz_ValidateToken(t *testing.T) {
+	lb, _ := newTestListenBrainz(t, "test-token")
+
+	user, err := lb.ValidateToken(context.Background())
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if user != "rob" {
+		t.Errorf("expected user rob, got %q", user)
+	}
+
+	bad, _ := newTestListenBrainz(t, "wrong-token")
+	if _, err := bad.ValidateToken(context.Background()); err == nil {
+		t.Error("expected an error for an invalid token")
+	}
+}
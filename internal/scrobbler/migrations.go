@@ -0,0 +1,121 @@
+package scrobbler
+
+import (
+	"database/sql"
+
+	"github.com/jfmyers9/scribbles/internal/sqlitemigrate"
+)
+
+// ErrSchemaTooNew is returned by NewQueue when the database was written by
+// a newer version of scribbles than this one
+var ErrSchemaTooNew = sqlitemigrate.ErrSchemaTooNew
+
+// migrations are applied in order. The schema version is stored in the
+// database's user_version pragma; a migration runs only if its version is
+// greater than the stored one. Never edit a released migration: append a
+// new one instead.
+var migrations = []sqlitemigrate.Migration{
+	{
+		Version:     1,
+		Description: "create scrobbles table",
+		// IF NOT EXISTS because databases created before versioning have
+		// this table with user_version 0
+		SQL: `
+			CREATE TABLE IF NOT EXISTS scrobbles (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				track_name TEXT NOT NULL,
+				artist TEXT NOT NULL,
+				album TEXT,
+				duration INTEGER NOT NULL,
+				timestamp INTEGER NOT NULL,
+				scrobbled BOOLEAN DEFAULT 0,
+				error TEXT,
+				created_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now'))
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_scrobbled ON scrobbles(scrobbled, timestamp);
+			CREATE INDEX IF NOT EXISTS idx_timestamp ON scrobbles(timestamp);
+		`,
+	},
+	{
+		Version:     2,
+		Description: "track scrobbles ignored by Last.fm",
+		SQL: `
+			ALTER TABLE scrobbles ADD COLUMN ignored BOOLEAN DEFAULT 0;
+			ALTER TABLE scrobbles ADD COLUMN ignored_code INTEGER;
+			ALTER TABLE scrobbles ADD COLUMN ignored_message TEXT;
+		`,
+	},
+	{
+		Version:     3,
+		Description: "track retry attempts and failed scrobbles",
+		SQL: `
+			ALTER TABLE scrobbles ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+			ALTER TABLE scrobbles ADD COLUMN next_attempt_at INTEGER NOT NULL DEFAULT 0;
+			ALTER TABLE scrobbles ADD COLUMN failed BOOLEAN DEFAULT 0;
+		`,
+	},
+	{
+		Version:     4,
+		Description: "track delivery to each backend",
+		// Every existing scrobble was queued for Last.fm, so its current
+		// state becomes its Last.fm delivery
+		SQL: `
+			CREATE TABLE backends (
+				name TEXT PRIMARY KEY
+			);
+
+			INSERT INTO backends (name) VALUES ('lastfm');
+
+			CREATE TABLE deliveries (
+				scrobble_id INTEGER NOT NULL REFERENCES scrobbles(id) ON DELETE CASCADE,
+				backend TEXT NOT NULL,
+				status TEXT NOT NULL DEFAULT 'pending',
+				attempts INTEGER NOT NULL DEFAULT 0,
+				next_attempt_at INTEGER NOT NULL DEFAULT 0,
+				error TEXT,
+				ignored_code INTEGER,
+				ignored_message TEXT,
+				updated_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now')),
+				PRIMARY KEY (scrobble_id, backend)
+			);
+
+			CREATE INDEX idx_deliveries_due ON deliveries(backend, status, next_attempt_at);
+
+			INSERT INTO deliveries (scrobble_id, backend, status, attempts, next_attempt_at, error, ignored_code, ignored_message)
+			SELECT id, 'lastfm',
+				CASE
+					WHEN scrobbled = 1 THEN 'scrobbled'
+					WHEN ignored = 1 THEN 'ignored'
+					WHEN failed = 1 THEN 'failed'
+					ELSE 'pending'
+				END,
+				attempts, next_attempt_at, error, ignored_code, ignored_message
+			FROM scrobbles;
+		`,
+	},
+	{
+		Version:     5,
+		Description: "record the music source of each scrobble",
+		SQL: `
+			ALTER TABLE scrobbles ADD COLUMN source TEXT NOT NULL DEFAULT '';
+		`,
+	},
+	{
+		Version:     6,
+		Description: "count failures toward the retry limit apart from attempts",
+		// Existing attempts may have been outages, so pending deliveries
+		// start with a fresh retry limit
+		SQL: `
+			ALTER TABLE deliveries ADD COLUMN failures INTEGER NOT NULL DEFAULT 0;
+		`,
+	},
+}
+
+// schemaVersion is the schema version this build of scribbles writes
+var schemaVersion = sqlitemigrate.Latest(migrations)
+
+// migrate brings the database schema up to schemaVersion
+func migrate(db *sql.DB) error {
+	return sqlitemigrate.Migrate(db, migrations)
+}
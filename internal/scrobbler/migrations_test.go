@@ -0,0 +1,177 @@
+package scrobbler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfmyers9/scribbles/internal/sqlitemigrate"
+)
+
+// copyFixture copies a testdata database into a temp dir so tests can
+// migrate it without modifying the checked-in file
+func copyFixture(t *testing.T, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	dbPath := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(dbPath, data, 0o600); err != nil {
+		t.Fatalf("failed to write fixture copy: %v", err)
+	}
+
+	return dbPath
+}
+
+func readUserVersion(t *testing.T, dbPath string) int {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	version, err := sqlitemigrate.UserVersion(db)
+	if err != nil {
+		t.Fatalf("failed to read user_version: %v", err)
+	}
+	return version
+}
+
+func TestMigrationsAreOrdered(t *testing.T) {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("migration %d has version %d, expected %d", i, m.Version, i+1)
+		}
+	}
+}
+
+func TestNewQueueSetsSchemaVersion(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "queue.db")
+
+	queue, err := NewQueue(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create queue: %v", err)
+	}
+	_ = queue.Close()
+
+	if got := readUserVersion(t, dbPath); got != schemaVersion {
+		t.Errorf("expected user_version %d, got %d", schemaVersion, got)
+	}
+
+	// Reopening an up-to-date database is a no-op
+	queue, err = NewQueue(dbPath)
+	if err != nil {
+		t.Fatalf("failed to reopen queue: %v", err)
+	}
+	_ = queue.Close()
+}
+
+func TestNewQueueUpgradesV1Fixture(t *testing.T) {
+	// queue_v1.db was created by scribbles 0.5.0, before schema versioning,
+	// so its user_version is 0
+	dbPath := copyFixture(t, "queue_v1.db")
+	if got := readUserVersion(t, dbPath); got != 0 {
+		t.Fatalf("expected fixture user_version 0, got %d", got)
+	}
+
+	queue, err := NewQueue(dbPath)
+	if err != nil {
+		t.Fatalf("failed to open v1 queue: %v", err)
+	}
+	defer func() { _ = queue.Close() }()
+
+	ctx := context.Background()
+
+	all, err := queue.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("failed to get all: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 scrobbles to survive the upgrade, got %d", len(all))
+	}
+
+	byTrack := make(map[string]QueuedScrobble)
+	for _, s := range all {
+		byTrack[s.TrackName] = s
+	}
+	if s := byTrack["Yesterday"]; !s.Scrobbled || s.Artist != "The Beatles" {
+		t.Errorf("unexpected scrobbled row: %+v", s)
+	}
+	if s := byTrack["Strong Enough"]; s.Scrobbled || s.Error == "" || s.Attempts != 0 || s.Failed || s.Ignored {
+		t.Errorf("unexpected failed row defaults after upgrade: %+v", s)
+	}
+
+	pending, err := queue.GetPending(ctx, 0)
+	if err != nil {
+		t.Fatalf("failed to get pending: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Errorf("expected 2 pending scrobbles after upgrade, got %d", len(pending))
+	}
+
+	// Columns added by later migrations are usable
+	if err := queue.MarkIgnored(ctx, byTrack["Believe"].ID, 1, "Artist ignored"); err != nil {
+		t.Errorf("failed to mark ignored on upgraded queue: %v", err)
+	}
+	if err := queue.MarkError(ctx, byTrack["Strong Enough"].ID, "network timeout"); err != nil {
+		t.Errorf("failed to mark error on upgraded queue: %v", err)
+	}
+
+	if got := readUserVersion(t, dbPath); got != schemaVersion {
+		t.Errorf("expected user_version %d after upgrade, got %d", schemaVersion, got)
+	}
+}
+
+func TestNewQueueRefusesNewerSchema(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "queue.db")
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if _, err := db.Exec("PRAGMA user_version = 999"); err != nil {
+		t.Fatalf("failed to set user_version: %v", err)
+	}
+	_ = db.Close()
+
+	_, err = NewQueue(dbPath)
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew, got %v", err)
+	}
+}
+
+func TestNewQueueBackfillsDeliveries(t *testing.T) {
+	queue, err := NewQueue(copyFixture(t, "queue_v1.db"))
+	if err != nil {
+		t.Fatalf("failed to open v1 queue: %v", err)
+	}
+	defer func() { _ = queue.Close() }()
+
+	if got := queue.Backends(); len(got) != 1 || got[0] != DefaultBackend {
+		t.Errorf("expected upgraded queue to deliver to %s, got %v", DefaultBackend, got)
+	}
+
+	all, err := queue.GetAll(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get all: %v", err)
+	}
+
+	// Each existing scrobble becomes a Last.fm delivery in the same state
+	for _, s := range all {
+		if len(s.Deliveries) != 1 {
+			t.Fatalf("%s: expected 1 delivery, got %+v", s.TrackName, s.Deliveries)
+		}
+		d := s.Deliveries[0]
+		if d.Backend != DefaultBackend || d.Status != s.Status() || d.Error != s.Error || d.Attempts != s.Attempts {
+			t.Errorf("%s: delivery %+v does not match scrobble %+v", s.TrackName, d, s)
+		}
+	}
+}
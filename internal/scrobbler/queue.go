@@ -3,7 +3,11 @@ package scrobbler
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	_ "modernc.org/sqlite"
@@ -11,10 +15,17 @@ import (
 
 // Queue manages a persistent queue of scrobbles using SQLite
 type Queue struct {
-	db *sql.DB
+	db    *sql.DB
+	retry RetryPolicy
+	now   func() time.Time // Clock used for retry scheduling, overridable in tests
+
+	mu       sync.RWMutex
+	backends []string // Backends new scrobbles are owed to, see SetBackends
 }
 
-// QueuedScrobble represents a scrobble in the queue
+// QueuedScrobble represents a scrobble in the queue. Its state fields
+// summarize its Deliveries: it is Scrobbled once every backend accepted it,
+// and pending while any backend is still owed it.
 type QueuedScrobble struct {
 	ID        int64
 	TrackName string
@@ -22,8 +33,174 @@ type QueuedScrobble struct {
 	Album     string
 	Duration  time.Duration
 	Timestamp time.Time
+	Source    string // Music source the play came from (empty if unknown)
 	Scrobbled bool
 	Error     string
+
+	// Ignored is set when a backend rejected the scrobble with an ignore
+	// code. Ignored scrobbles are not pending and are not successes.
+	Ignored        bool
+	IgnoredCode    int
+	IgnoredMessage string
+
+	// Attempts counts failed submissions. After a failure the scrobble is
+	// not returned by GetPending until NextAttemptAt.
+	Attempts      int
+	NextAttemptAt time.Time
+
+	// Failed is set once a scrobble has exhausted its retries or hit a
+	// permanent error. Failed scrobbles are kept as a dead letter for
+	// inspection but are no longer retried.
+	Failed bool
+
+	// Deliveries holds the scrobble's state with each backend, ordered by
+	// backend name
+	Deliveries []Delivery
+}
+
+// Status is the delivery state of a queued scrobble
+type Status string
+
+// Queued scrobble statuses
+const (
+	StatusPending   Status = "pending"   // Waiting to be submitted, possibly after a retry backoff
+	StatusScrobbled Status = "scrobbled" // Accepted by the backend
+	StatusIgnored   Status = "ignored"   // Rejected by the backend with an ignore code
+	StatusFailed    Status = "failed"    // Out of retries or failed permanently
+)
+
+// ParseStatus parses a status name as accepted by Filter
+func ParseStatus(s string) (Status, error) {
+	switch status := Status(strings.ToLower(s)); status {
+	case StatusPending, StatusScrobbled, StatusIgnored, StatusFailed:
+		return status, nil
+	default:
+		return "", fmt.Errorf("invalid status %q (must be pending, scrobbled, ignored or failed)", s)
+	}
+}
+
+// Status returns the scrobble's delivery state
+func (s QueuedScrobble) Status() Status {
+	switch {
+	case s.Scrobbled:
+		return StatusScrobbled
+	case s.Ignored:
+		return StatusIgnored
+	case s.Failed:
+		return StatusFailed
+	default:
+		return StatusPending
+	}
+}
+
+// queuedScrobbleJSON is the JSON representation of a queued scrobble
+type queuedScrobbleJSON struct {
+	ID             int64      `json:"id"`
+	Status         Status     `json:"status"`
+	Artist         string     `json:"artist"`
+	Track          string     `json:"track"`
+	Album          string     `json:"album,omitempty"`
+	Duration       int        `json:"duration_seconds"`
+	Timestamp      time.Time  `json:"timestamp"`
+	Source         string     `json:"source,omitempty"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	IgnoredCode    int        `json:"ignored_code,omitempty"`
+	IgnoredMessage string     `json:"ignored_message,omitempty"`
+
+	Deliveries []deliveryJSON `json:"deliveries"`
+}
+
+// deliveryJSON is the JSON representation of a scrobble's delivery to one
+// backend
+type deliveryJSON struct {
+	Backend        string     `json:"backend"`
+	Status         Status     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	IgnoredCode    int        `json:"ignored_code,omitempty"`
+	IgnoredMessage string     `json:"ignored_message,omitempty"`
+}
+
+// MarshalJSON encodes the scrobble as shown by "scribbles queue -o json"
+// and the daemon's HTTP API
+func (s QueuedScrobble) MarshalJSON() ([]byte, error) {
+	entry := queuedScrobbleJSON{
+		ID:             s.ID,
+		Status:         s.Status(),
+		Artist:         s.Artist,
+		Track:          s.TrackName,
+		Album:          s.Album,
+		Duration:       int(s.Duration.Seconds()),
+		Timestamp:      s.Timestamp,
+		Source:         s.Source,
+		Attempts:       s.Attempts,
+		Error:          s.Error,
+		IgnoredCode:    s.IgnoredCode,
+		IgnoredMessage: s.IgnoredMessage,
+		Deliveries:     make([]deliveryJSON, len(s.Deliveries)),
+	}
+	if s.Status() == StatusPending && !s.NextAttemptAt.IsZero() {
+		next := s.NextAttemptAt
+		entry.NextAttemptAt = &next
+	}
+	for i, d := range s.Deliveries {
+		entry.Deliveries[i] = deliveryJSON{
+			Backend:        d.Backend,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			Error:          d.Error,
+			IgnoredCode:    d.IgnoredCode,
+			IgnoredMessage: d.IgnoredMessage,
+		}
+		if d.Status == StatusPending && !d.NextAttemptAt.IsZero() {
+			next := d.NextAttemptAt
+			entry.Deliveries[i].NextAttemptAt = &next
+		}
+	}
+	return json.Marshal(entry)
+}
+
+// Filter selects scrobbles for List and Purge. Zero fields match everything.
+type Filter struct {
+	Status Status    // Only scrobbles in this state
+	Artist string    // Only scrobbles by this artist (case-insensitive)
+	Since  time.Time // Only scrobbles played at or after this time
+	Until  time.Time // Only scrobbles played before this time
+	Limit  int       // Maximum number of results for List (0 = no limit)
+}
+
+// where returns the SQL condition and arguments for the filter
+func (f Filter) where() (string, []any) {
+	conditions := []string{"1 = 1"}
+	var args []any
+
+	switch f.Status {
+	case StatusPending:
+		conditions = append(conditions, "scrobbled = 0 AND ignored = 0 AND failed = 0")
+	case StatusScrobbled:
+		conditions = append(conditions, "scrobbled = 1")
+	case StatusIgnored:
+		conditions = append(conditions, "scrobbled = 0 AND ignored = 1")
+	case StatusFailed:
+		conditions = append(conditions, "scrobbled = 0 AND ignored = 0 AND failed = 1")
+	}
+	if f.Artist != "" {
+		conditions = append(conditions, "artist = ? COLLATE NOCASE")
+		args = append(args, f.Artist)
+	}
+	if !f.Since.IsZero() {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, f.Since.Unix())
+	}
+	if !f.Until.IsZero() {
+		conditions = append(conditions, "timestamp < ?")
+		args = append(args, f.Until.Unix())
+	}
+
+	return strings.Join(conditions, " AND "), args
 }
 
 // NewQueue creates a new scrobble queue backed by SQLite
@@ -54,30 +231,29 @@ func NewQueue(dbPath string) (*Queue, error) {
 		}
 	}
 
-	// Create the schema
-	schema := `
-		CREATE TABLE IF NOT EXISTS scrobbles (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			track_name TEXT NOT NULL,
-			artist TEXT NOT NULL,
-			album TEXT,
-			duration INTEGER NOT NULL,
-			timestamp INTEGER NOT NULL,
-			scrobbled BOOLEAN DEFAULT 0,
-			error TEXT,
-			created_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now'))
-		);
-
-		CREATE INDEX IF NOT EXISTS idx_scrobbled ON scrobbles(scrobbled, timestamp);
-		CREATE INDEX IF NOT EXISTS idx_timestamp ON scrobbles(timestamp);
-	`
+	// Create or upgrade the schema
+	if err := migrate(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
 
-	if _, err := db.Exec(schema); err != nil {
+	backends, err := loadBackends(db)
+	if err != nil {
 		_ = db.Close()
-		return nil, fmt.Errorf("failed to create schema: %w", err)
+		return nil, err
 	}
 
-	return &Queue{db: db}, nil
+	return &Queue{
+		db:       db,
+		retry:    DefaultRetryPolicy,
+		now:      time.Now,
+		backends: backends,
+	}, nil
+}
+
+// SetRetryPolicy replaces the policy used by MarkError to schedule retries
+func (q *Queue) SetRetryPolicy(policy RetryPolicy) {
+	q.retry = policy
 }
 
 // Close closes the database connection
@@ -88,19 +264,26 @@ func (q *Queue) Close() error {
 	return nil
 }
 
-// Add adds a new scrobble to the queue
+// Add adds a new scrobble to the queue, owed to every configured backend
 func (q *Queue) Add(ctx context.Context, scrobble Scrobble) (int64, error) {
 	query := `
-		INSERT INTO scrobbles (track_name, artist, album, duration, timestamp)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO scrobbles (track_name, artist, album, duration, timestamp, source)
+		VALUES (?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := q.db.ExecContext(ctx, query,
+	tx, err := q.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, query,
 		scrobble.Track,
 		scrobble.Artist,
 		scrobble.Album,
 		int64(scrobble.Duration.Seconds()),
 		scrobble.Timestamp.Unix(),
+		scrobble.Source,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("failed to insert scrobble: %w", err)
@@ -111,107 +294,107 @@ func (q *Queue) Add(ctx context.Context, scrobble Scrobble) (int64, error) {
 		return 0, fmt.Errorf("failed to get insert id: %w", err)
 	}
 
-	return id, nil
-}
-
-// MarkScrobbled marks a scrobble as successfully scrobbled
-func (q *Queue) MarkScrobbled(ctx context.Context, id int64) error {
-	query := `
-		UPDATE scrobbles
-		SET scrobbled = 1, error = NULL
-		WHERE id = ?
-	`
-
-	result, err := q.db.ExecContext(ctx, query, id)
-	if err != nil {
-		return fmt.Errorf("failed to mark scrobble as scrobbled: %w", err)
+	for _, backend := range q.Backends() {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO deliveries (scrobble_id, backend) VALUES (?, ?)", id, backend); err != nil {
+			return 0, fmt.Errorf("failed to insert delivery: %w", err)
+		}
 	}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("scrobble with id %d not found", id)
-	}
+	return id, nil
+}
 
-	return nil
+// MarkScrobbled marks a scrobble as successfully scrobbled by every backend
+func (q *Queue) MarkScrobbled(ctx context.Context, id int64) error {
+	return q.all().MarkScrobbled(ctx, id)
 }
 
-// MarkScrobbledBatch marks multiple scrobbles as successfully scrobbled
+// MarkScrobbledBatch marks multiple scrobbles as successfully scrobbled by
+// every backend
 func (q *Queue) MarkScrobbledBatch(ctx context.Context, ids []int64) error {
-	if len(ids) == 0 {
-		return nil
-	}
-
-	tx, err := q.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer func() { _ = tx.Rollback() }()
+	return q.all().MarkScrobbledBatch(ctx, ids)
+}
 
-	stmt, err := tx.PrepareContext(ctx, "UPDATE scrobbles SET scrobbled = 1, error = NULL WHERE id = ?")
-	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
-	}
-	defer func() { _ = stmt.Close() }()
+// MarkError records a failed submission attempt to every backend still
+// owed the scrobble. See Deliveries.MarkError.
+func (q *Queue) MarkError(ctx context.Context, id int64, errMsg string) error {
+	return q.all().MarkError(ctx, id, errMsg)
+}
 
-	for _, id := range ids {
-		if _, err := stmt.ExecContext(ctx, id); err != nil {
-			return fmt.Errorf("failed to mark scrobble %d: %w", id, err)
-		}
-	}
+// MarkTransientError records an attempt that failed with a transient error
+// to every backend still owed the scrobble. See
+// Deliveries.MarkTransientError.
+func (q *Queue) MarkTransientError(ctx context.Context, id int64, errMsg string) error {
+	return q.all().MarkTransientError(ctx, id, errMsg)
+}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
+// MarkFailed moves every undelivered backend of a scrobble straight to the
+// failed state without further retries. Use it for permanent errors (see
+// IsPermanentError).
+func (q *Queue) MarkFailed(ctx context.Context, id int64, errMsg string) error {
+	return q.all().MarkFailed(ctx, id, errMsg)
+}
 
-	return nil
+// MarkIgnored records that a scrobble was ignored, with its ignore code and
+// message, for every backend not yet delivered to
+func (q *Queue) MarkIgnored(ctx context.Context, id int64, code int, message string) error {
+	return q.all().MarkIgnored(ctx, id, code, message)
 }
 
-// MarkError marks a scrobble as failed with an error message
-func (q *Queue) MarkError(ctx context.Context, id int64, errMsg string) error {
+// GetPending retrieves pending scrobbles that are due for submission,
+// ordered by timestamp. Scrobbles waiting out a retry backoff, ignored
+// scrobbles and failed scrobbles are excluded.
+// Optionally limits the number of results
+func (q *Queue) GetPending(ctx context.Context, limit int) ([]QueuedScrobble, error) {
 	query := `
-		UPDATE scrobbles
-		SET error = ?
-		WHERE id = ?
+		SELECT ` + queuedScrobbleColumns + `
+		FROM scrobbles
+		WHERE scrobbled = 0 AND ignored = 0 AND failed = 0
+		AND next_attempt_at <= ?
+		ORDER BY timestamp ASC
 	`
 
-	result, err := q.db.ExecContext(ctx, query, errMsg, id)
-	if err != nil {
-		return fmt.Errorf("failed to mark scrobble error: %w", err)
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
 	}
 
-	rows, err := result.RowsAffected()
+	scrobbles, err := q.query(ctx, query, q.now().Unix())
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rows == 0 {
-		return fmt.Errorf("scrobble with id %d not found", id)
+		return nil, fmt.Errorf("failed to query pending scrobbles: %w", err)
 	}
 
-	return nil
+	return scrobbles, nil
 }
 
-// GetPending retrieves all pending (unscrobbled) scrobbles, ordered by timestamp
-// Optionally limits the number of results
-func (q *Queue) GetPending(ctx context.Context, limit int) ([]QueuedScrobble, error) {
+// GetAll retrieves all scrobbles (for debugging/testing)
+func (q *Queue) GetAll(ctx context.Context) ([]QueuedScrobble, error) {
 	query := `
-		SELECT id, track_name, artist, album, duration, timestamp, scrobbled, COALESCE(error, '')
+		SELECT ` + queuedScrobbleColumns + `
 		FROM scrobbles
-		WHERE scrobbled = 0
-		ORDER BY timestamp ASC
+		ORDER BY timestamp DESC
 	`
 
-	if limit > 0 {
-		query += fmt.Sprintf(" LIMIT %d", limit)
+	scrobbles, err := q.query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query all scrobbles: %w", err)
 	}
 
-	rows, err := q.db.QueryContext(ctx, query)
+	return scrobbles, nil
+}
+
+// queuedScrobbleColumns lists the columns scanned by query, in order
+const queuedScrobbleColumns = `id, track_name, artist, album, duration, timestamp, scrobbled, COALESCE(error, ''),
+			ignored, COALESCE(ignored_code, 0), COALESCE(ignored_message, ''),
+			attempts, next_attempt_at, failed, source`
+
+// query runs a SELECT of queuedScrobbleColumns and scans the results
+func (q *Queue) query(ctx context.Context, query string, args ...any) ([]QueuedScrobble, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query pending scrobbles: %w", err)
+		return nil, err
 	}
 	defer func() { _ = rows.Close() }()
 
@@ -220,6 +403,7 @@ func (q *Queue) GetPending(ctx context.Context, limit int) ([]QueuedScrobble, er
 		var s QueuedScrobble
 		var durationSecs int64
 		var timestampUnix int64
+		var nextAttemptUnix int64
 
 		err := rows.Scan(
 			&s.ID,
@@ -230,6 +414,13 @@ func (q *Queue) GetPending(ctx context.Context, limit int) ([]QueuedScrobble, er
 			&timestampUnix,
 			&s.Scrobbled,
 			&s.Error,
+			&s.Ignored,
+			&s.IgnoredCode,
+			&s.IgnoredMessage,
+			&s.Attempts,
+			&nextAttemptUnix,
+			&s.Failed,
+			&s.Source,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan scrobble: %w", err)
@@ -237,6 +428,9 @@ func (q *Queue) GetPending(ctx context.Context, limit int) ([]QueuedScrobble, er
 
 		s.Duration = time.Duration(durationSecs) * time.Second
 		s.Timestamp = time.Unix(timestampUnix, 0)
+		if nextAttemptUnix > 0 {
+			s.NextAttemptAt = time.Unix(nextAttemptUnix, 0)
+		}
 
 		scrobbles = append(scrobbles, s)
 	}
@@ -245,64 +439,193 @@ func (q *Queue) GetPending(ctx context.Context, limit int) ([]QueuedScrobble, er
 		return nil, fmt.Errorf("error iterating scrobbles: %w", err)
 	}
 
+	// The single connection is held by rows until they are closed
+	_ = rows.Close()
+
+	if err := q.loadDeliveries(ctx, scrobbles); err != nil {
+		return nil, err
+	}
+
 	return scrobbles, nil
 }
 
-// GetAll retrieves all scrobbles (for debugging/testing)
-func (q *Queue) GetAll(ctx context.Context) ([]QueuedScrobble, error) {
+// List retrieves scrobbles matching the filter, newest first
+func (q *Queue) List(ctx context.Context, filter Filter) ([]QueuedScrobble, error) {
+	where, args := filter.where()
 	query := `
-		SELECT id, track_name, artist, album, duration, timestamp, scrobbled, COALESCE(error, '')
+		SELECT ` + queuedScrobbleColumns + `
 		FROM scrobbles
-		ORDER BY timestamp DESC
+		WHERE ` + where + `
+		ORDER BY timestamp DESC, id DESC
 	`
 
-	rows, err := q.db.QueryContext(ctx, query)
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	scrobbles, err := q.query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query all scrobbles: %w", err)
+		return nil, fmt.Errorf("failed to list scrobbles: %w", err)
 	}
-	defer func() { _ = rows.Close() }()
 
-	var scrobbles []QueuedScrobble
-	for rows.Next() {
-		var s QueuedScrobble
-		var durationSecs int64
-		var timestampUnix int64
+	return scrobbles, nil
+}
 
-		err := rows.Scan(
-			&s.ID,
-			&s.TrackName,
-			&s.Artist,
-			&s.Album,
-			&durationSecs,
-			&timestampUnix,
-			&s.Scrobbled,
-			&s.Error,
+// Get retrieves a single scrobble by ID
+func (q *Queue) Get(ctx context.Context, id int64) (*QueuedScrobble, error) {
+	query := `
+		SELECT ` + queuedScrobbleColumns + `
+		FROM scrobbles
+		WHERE id = ?
+	`
+
+	scrobbles, err := q.query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scrobble: %w", err)
+	}
+	if len(scrobbles) == 0 {
+		return nil, fmt.Errorf("scrobble with id %d not found", id)
+	}
+
+	return &scrobbles[0], nil
+}
+
+// Has reports whether the queue holds a scrobble of the same track by the
+// same artist (case-insensitive) at the same second, in any state. It is
+// used to skip duplicates when importing scrobbles.
+func (q *Queue) Has(ctx context.Context, scrobble Scrobble) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM scrobbles
+			WHERE artist = ? COLLATE NOCASE AND track_name = ? COLLATE NOCASE AND timestamp = ?
 		)
+	`
+
+	var exists bool
+	if err := q.db.QueryRowContext(ctx, query, scrobble.Artist, scrobble.Track, scrobble.Timestamp.Unix()).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to look up scrobble: %w", err)
+	}
+
+	return exists, nil
+}
+
+// Retry clears a scrobble's error, ignore and failed state and makes it
+// due for submission immediately with a fresh attempt count, for every
+// backend it has not been delivered to. Scrobbled entries cannot be
+// retried.
+func (q *Queue) Retry(ctx context.Context, id int64) error {
+	return q.updateUnscrobbled(ctx, id, "", nil)
+}
+
+// ScrobbleEdit holds replacement metadata for Edit. Nil fields are left
+// unchanged.
+type ScrobbleEdit struct {
+	Artist *string
+	Track  *string
+	Album  *string
+}
+
+// Edit corrects the metadata of a scrobble that has not been scrobbled yet
+// and, like Retry, schedules it for immediate resubmission
+func (q *Queue) Edit(ctx context.Context, id int64, edit ScrobbleEdit) error {
+	if edit.Artist != nil && *edit.Artist == "" {
+		return fmt.Errorf("artist cannot be empty")
+	}
+	if edit.Track != nil && *edit.Track == "" {
+		return fmt.Errorf("track cannot be empty")
+	}
+
+	query := `
+		UPDATE scrobbles
+		SET artist = COALESCE(?, artist), track_name = COALESCE(?, track_name), album = COALESCE(?, album)
+		WHERE id = ?
+	`
+
+	return q.updateUnscrobbled(ctx, id, query, []any{edit.Artist, edit.Track, edit.Album, id})
+}
+
+// updateUnscrobbled runs an optional UPDATE of a scrobble that has not been
+// scrobbled by every backend and resets its undelivered backends to
+// pending. It reports whether the row was missing or already scrobbled.
+func (q *Queue) updateUnscrobbled(ctx context.Context, id int64, query string, args []any) error {
+	return q.inTx(ctx, func(tx *sql.Tx) error {
+		var scrobbled bool
+		err := tx.QueryRowContext(ctx, "SELECT scrobbled FROM scrobbles WHERE id = ?", id).Scan(&scrobbled)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("scrobble with id %d not found", id)
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan scrobble: %w", err)
+			return fmt.Errorf("failed to read scrobble: %w", err)
+		}
+		if scrobbled {
+			return fmt.Errorf("scrobble with id %d has already been scrobbled", id)
 		}
 
-		s.Duration = time.Duration(durationSecs) * time.Second
-		s.Timestamp = time.Unix(timestampUnix, 0)
+		if query != "" {
+			if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				return fmt.Errorf("failed to update scrobble: %w", err)
+			}
+		}
 
-		scrobbles = append(scrobbles, s)
+		reset := `
+			UPDATE deliveries
+			SET status = 'pending', error = NULL, attempts = 0, failures = 0, next_attempt_at = 0,
+				ignored_code = NULL, ignored_message = NULL, updated_at = strftime('%s', 'now')
+			WHERE scrobble_id = ? AND status != 'scrobbled'
+		`
+		if _, err := tx.ExecContext(ctx, reset, id); err != nil {
+			return fmt.Errorf("failed to reset deliveries: %w", err)
+		}
+
+		return refreshSummary(ctx, tx, id)
+	})
+}
+
+// Delete removes a scrobble from the queue
+func (q *Queue) Delete(ctx context.Context, id int64) error {
+	result, err := q.db.ExecContext(ctx, "DELETE FROM scrobbles WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete scrobble: %w", err)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating scrobbles: %w", err)
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
-	return scrobbles, nil
+	if rows == 0 {
+		return fmt.Errorf("scrobble with id %d not found", id)
+	}
+
+	return nil
 }
 
-// Cleanup removes old scrobbled records to prevent unbounded growth
-// Keeps scrobbles newer than the given age, and always keeps unscrobbled ones
+// Purge deletes all scrobbles matching the filter and returns how many were
+// removed. Filter.Limit is ignored.
+func (q *Queue) Purge(ctx context.Context, filter Filter) (int64, error) {
+	where, args := filter.where()
+
+	result, err := q.db.ExecContext(ctx, "DELETE FROM scrobbles WHERE "+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge scrobbles: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// Cleanup removes old scrobbled and ignored records to prevent unbounded growth
+// Keeps scrobbles newer than the given age, and always keeps pending ones
 func (q *Queue) Cleanup(ctx context.Context, maxAge time.Duration) (int64, error) {
 	cutoff := time.Now().Add(-maxAge).Unix()
 
 	query := `
 		DELETE FROM scrobbles
-		WHERE scrobbled = 1
+		WHERE (scrobbled = 1 OR ignored = 1)
 		AND timestamp < ?
 	`
 
@@ -344,12 +667,30 @@ func (q *Queue) CleanupOldFailed(ctx context.Context) (int64, error) {
 	return deleted, nil
 }
 
+// OldestPending returns when the oldest pending scrobble was queued, or
+// the zero time if none are pending
+func (q *Queue) OldestPending(ctx context.Context) (time.Time, error) {
+	var createdAt sql.NullInt64
+	err := q.db.QueryRowContext(ctx, `
+		SELECT MIN(created_at) FROM scrobbles
+		WHERE scrobbled = 0 AND ignored = 0 AND failed = 0
+	`).Scan(&createdAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to find oldest pending scrobble: %w", err)
+	}
+	if !createdAt.Valid {
+		return time.Time{}, nil
+	}
+	return time.Unix(createdAt.Int64, 0), nil
+}
+
 // Count returns the number of scrobbles in the queue
-// If includeScrobbled is false, only counts pending scrobbles
+// If includeScrobbled is false, only counts pending scrobbles, including
+// those waiting out a retry backoff
 func (q *Queue) Count(ctx context.Context, includeScrobbled bool) (int, error) {
 	query := "SELECT COUNT(*) FROM scrobbles"
 	if !includeScrobbled {
-		query += " WHERE scrobbled = 0"
+		query += " WHERE scrobbled = 0 AND ignored = 0 AND failed = 0"
 	}
 
 	var count int
@@ -68,6 +68,7 @@ func TestQueueAdd(t *testing.T) {
 		Album:     "Test Album",
 		Duration:  3 * time.Minute,
 		Timestamp: time.Now(),
+		Source:    "mpd",
 	}
 
 	id, err := queue.Add(ctx, scrobble)
@@ -88,6 +89,52 @@ func TestQueueAdd(t *testing.T) {
 	if count != 1 {
 		t.Errorf("expected 1 pending scrobble, got %d", count)
 	}
+
+	pending, err := queue.GetPending(ctx, 0)
+	if err != nil {
+		t.Fatalf("failed to get pending scrobbles: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Source != "mpd" {
+		t.Errorf("expected the scrobble's source to be kept, got %+v", pending)
+	}
+}
+
+func TestQueueOldestPending(t *testing.T) {
+	queue := createTestQueue(t)
+	ctx := context.Background()
+
+	oldest, err := queue.OldestPending(ctx)
+	if err != nil {
+		t.Fatalf("OldestPending: %v", err)
+	}
+	if !oldest.IsZero() {
+		t.Errorf("expected the zero time for an empty queue, got %v", oldest)
+	}
+
+	queuedAt := time.Now().Add(-3 * time.Hour).Truncate(time.Second)
+	for i, track := range []string{"Delivered", "Stuck", "Recent"} {
+		id, err := queue.Add(ctx, Scrobble{Artist: "Artist", Track: track, Timestamp: time.Now()})
+		if err != nil {
+			t.Fatalf("failed to add scrobble: %v", err)
+		}
+		createdAt := queuedAt.Add(time.Duration(i) * time.Hour)
+		if _, err := queue.db.ExecContext(ctx, "UPDATE scrobbles SET created_at = ? WHERE id = ?", createdAt.Unix(), id); err != nil {
+			t.Fatalf("failed to backdate scrobble: %v", err)
+		}
+		if track == "Delivered" {
+			if err := queue.MarkScrobbled(ctx, id); err != nil {
+				t.Fatalf("failed to mark scrobbled: %v", err)
+			}
+		}
+	}
+
+	oldest, err = queue.OldestPending(ctx)
+	if err != nil {
+		t.Fatalf("OldestPending: %v", err)
+	}
+	if want := queuedAt.Add(time.Hour); !oldest.Equal(want) {
+		t.Errorf("expected the stuck scrobble queued at %v, got %v", want, oldest)
+	}
 }
 
 func TestQueueMarkScrobbled(t *testing.T) {
@@ -194,11 +241,30 @@ func TestQueueMarkError(t *testing.T) {
 		t.Fatalf("failed to mark error: %v", err)
 	}
 
-	// Verify it's still pending
+	// Not due again until the backoff has elapsed
 	pending, err := queue.GetPending(ctx, 0)
 	if err != nil {
 		t.Fatalf("failed to get pending: %v", err)
 	}
+	if len(pending) != 0 {
+		t.Fatalf("expected no due scrobbles during backoff, got %d", len(pending))
+	}
+
+	// Still counted as pending
+	count, err := queue.Count(ctx, false)
+	if err != nil {
+		t.Fatalf("failed to count: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected pending count 1, got %d", count)
+	}
+
+	// Verify it's pending again once due
+	queue.now = func() time.Time { return time.Now().Add(DefaultRetryPolicy.BaseDelay) }
+	pending, err = queue.GetPending(ctx, 0)
+	if err != nil {
+		t.Fatalf("failed to get pending: %v", err)
+	}
 
 	if len(pending) != 1 {
 		t.Fatalf("expected 1 pending scrobble, got %d", len(pending))
@@ -207,6 +273,239 @@ func TestQueueMarkError(t *testing.T) {
 	if pending[0].Error != errMsg {
 		t.Errorf("expected error %q, got %q", errMsg, pending[0].Error)
 	}
+	if pending[0].Attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", pending[0].Attempts)
+	}
+}
+
+func TestQueueRetryBackoff(t *testing.T) {
+	queue := createTestQueue(t)
+	ctx := context.Background()
+
+	now := time.Unix(1700000000, 0)
+	queue.now = func() time.Time { return now }
+	queue.SetRetryPolicy(RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Minute,
+		MaxDelay:    time.Hour,
+	})
+
+	id, err := queue.Add(ctx, Scrobble{
+		Artist:    "Artist",
+		Track:     "Track",
+		Duration:  3 * time.Minute,
+		Timestamp: now,
+	})
+	if err != nil {
+		t.Fatalf("failed to add scrobble: %v", err)
+	}
+
+	for attempt, wantDelay := range []time.Duration{time.Minute, 2 * time.Minute} {
+		if err := queue.MarkError(ctx, id, "network error"); err != nil {
+			t.Fatalf("failed to mark error: %v", err)
+		}
+
+		all, err := queue.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("failed to get all: %v", err)
+		}
+		s := all[0]
+		if s.Attempts != attempt+1 {
+			t.Errorf("expected %d attempts, got %d", attempt+1, s.Attempts)
+		}
+		if got := s.NextAttemptAt.Sub(now); got != wantDelay {
+			t.Errorf("attempt %d: expected next attempt in %v, got %v", attempt+1, wantDelay, got)
+		}
+		if s.Failed {
+			t.Fatalf("attempt %d: expected scrobble not to be failed yet", attempt+1)
+		}
+
+		now = s.NextAttemptAt
+	}
+
+	// Third failure reaches MaxAttempts and moves it to the dead letter state
+	if err := queue.MarkError(ctx, id, "network error"); err != nil {
+		t.Fatalf("failed to mark error: %v", err)
+	}
+
+	all, err := queue.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("failed to get all: %v", err)
+	}
+	if !all[0].Failed || all[0].Attempts != 3 {
+		t.Errorf("expected failed scrobble after 3 attempts, got %+v", all[0])
+	}
+
+	queue.now = func() time.Time { return now.Add(24 * time.Hour) }
+	pending, err := queue.GetPending(ctx, 0)
+	if err != nil {
+		t.Fatalf("failed to get pending: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected failed scrobble not to be retried, got %d pending", len(pending))
+	}
+
+	if err := queue.MarkError(ctx, 999, "network error"); err == nil {
+		t.Error("expected error for non-existent ID")
+	}
+}
+
+func TestQueueTransientErrorsDontCountTowardMaxAttempts(t *testing.T) {
+	queue := createTestQueue(t)
+	ctx := context.Background()
+
+	start := time.Unix(1700000000, 0)
+	now := start
+	queue.now = func() time.Time { return now }
+
+	id, err := queue.Add(ctx, Scrobble{
+		Artist:    "Artist",
+		Track:     "Track",
+		Duration:  3 * time.Minute,
+		Timestamp: now,
+	})
+	if err != nil {
+		t.Fatalf("failed to add scrobble: %v", err)
+	}
+
+	// A three day outage, retried whenever the backoff allows
+	var s QueuedScrobble
+	for now.Sub(start) < 72*time.Hour {
+		if err := queue.MarkTransientError(ctx, id, "connection refused"); err != nil {
+			t.Fatalf("failed to mark transient error: %v", err)
+		}
+		all, err := queue.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("failed to get all: %v", err)
+		}
+		s = all[0]
+		if s.Failed {
+			t.Fatalf("expected an outage not to fail the scrobble, failed after %d attempts", s.Attempts)
+		}
+		now = s.NextAttemptAt
+	}
+	if s.Attempts <= DefaultRetryPolicy.MaxAttempts {
+		t.Errorf("expected more than %d attempts, got %d", DefaultRetryPolicy.MaxAttempts, s.Attempts)
+	}
+	if got := queue.retry.Backoff(s.Attempts); got != DefaultRetryPolicy.MaxDelay {
+		t.Errorf("expected the backoff to reach %v, got %v", DefaultRetryPolicy.MaxDelay, got)
+	}
+
+	// Errors of the scrobble's own still have the whole retry limit
+	if err := queue.MarkError(ctx, id, "unexpected response"); err != nil {
+		t.Fatalf("failed to mark error: %v", err)
+	}
+	got, err := queue.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("failed to get scrobble: %v", err)
+	}
+	if got.Failed {
+		t.Errorf("expected one failure after the outage not to fail the scrobble, got %+v", got)
+	}
+
+	// Transient errors fail a scrobble that has grown too old
+	now = start.Add(DefaultRetryPolicy.MaxAge)
+	if err := queue.MarkTransientError(ctx, id, "connection refused"); err != nil {
+		t.Fatalf("failed to mark transient error: %v", err)
+	}
+	got, err = queue.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("failed to get scrobble: %v", err)
+	}
+	if !got.Failed {
+		t.Errorf("expected a scrobble past MaxAge to fail, got %+v", got)
+	}
+}
+
+func TestQueueMarkFailed(t *testing.T) {
+	queue := createTestQueue(t)
+	ctx := context.Background()
+
+	id, err := queue.Add(ctx, Scrobble{
+		Artist:    "Artist",
+		Track:     "Track",
+		Duration:  3 * time.Minute,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("failed to add scrobble: %v", err)
+	}
+
+	if err := queue.MarkFailed(ctx, id, "lastfm: error 6: Invalid parameters"); err != nil {
+		t.Fatalf("failed to mark failed: %v", err)
+	}
+
+	count, err := queue.Count(ctx, false)
+	if err != nil {
+		t.Fatalf("failed to count: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected failed scrobble not to be pending, got %d", count)
+	}
+
+	all, err := queue.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("failed to get all: %v", err)
+	}
+	if !all[0].Failed || all[0].Attempts != 1 || all[0].Error == "" {
+		t.Errorf("unexpected failed scrobble: %+v", all[0])
+	}
+
+	if err := queue.MarkFailed(ctx, 999, "boom"); err == nil {
+		t.Error("expected error for non-existent ID")
+	}
+}
+
+func TestQueueMarkIgnored(t *testing.T) {
+	queue := createTestQueue(t)
+	ctx := context.Background()
+
+	id, err := queue.Add(ctx, Scrobble{
+		Artist:    "Test Artist",
+		Track:     "Test Track",
+		Duration:  3 * time.Minute,
+		Timestamp: time.Now().Add(-30 * 24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("failed to add scrobble: %v", err)
+	}
+
+	if err := queue.MarkIgnored(ctx, id, 3, "Timestamp too old"); err != nil {
+		t.Fatalf("failed to mark ignored: %v", err)
+	}
+
+	// Ignored scrobbles are neither pending nor scrobbled
+	pending, err := queue.GetPending(ctx, 0)
+	if err != nil {
+		t.Fatalf("failed to get pending: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected 0 pending scrobbles, got %d", len(pending))
+	}
+
+	count, err := queue.Count(ctx, false)
+	if err != nil {
+		t.Fatalf("failed to count: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected pending count 0, got %d", count)
+	}
+
+	all, err := queue.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("failed to get all: %v", err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("expected 1 scrobble, got %d", len(all))
+	}
+	s := all[0]
+	if s.Scrobbled || !s.Ignored || s.IgnoredCode != 3 || s.IgnoredMessage != "Timestamp too old" {
+		t.Errorf("unexpected ignored scrobble: %+v", s)
+	}
+
+	if err := queue.MarkIgnored(ctx, 999, 1, "Artist ignored"); err == nil {
+		t.Error("expected error for non-existent ID")
+	}
 }
 
 func TestQueueGetPending(t *testing.T) {
@@ -616,3 +915,222 @@ func BenchmarkQueueGetPending(b *testing.B) {
 		_, _ = queue.GetPending(ctx, 50)
 	}
 }
+
+// seedStatuses adds one scrobble in each status and returns their IDs by status
+func seedStatuses(t *testing.T, queue *Queue, base time.Time) map[Status]int64 {
+	t.Helper()
+	ctx := context.Background()
+
+	ids := make(map[Status]int64)
+	for i, status := range []Status{StatusPending, StatusScrobbled, StatusIgnored, StatusFailed} {
+		artist := "Cher"
+		if status == StatusFailed {
+			artist = "Unknown Artist"
+		}
+		id, err := queue.Add(ctx, Scrobble{
+			Artist:    artist,
+			Track:     string(status),
+			Album:     "Album",
+			Duration:  3 * time.Minute,
+			Timestamp: base.Add(time.Duration(i) * time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("failed to add scrobble: %v", err)
+		}
+		ids[status] = id
+	}
+
+	if err := queue.MarkScrobbled(ctx, ids[StatusScrobbled]); err != nil {
+		t.Fatalf("failed to mark scrobbled: %v", err)
+	}
+	if err := queue.MarkIgnored(ctx, ids[StatusIgnored], 3, "Timestamp too old"); err != nil {
+		t.Fatalf("failed to mark ignored: %v", err)
+	}
+	if err := queue.MarkFailed(ctx, ids[StatusFailed], "Invalid parameters"); err != nil {
+		t.Fatalf("failed to mark failed: %v", err)
+	}
+
+	return ids
+}
+
+func TestQueueList(t *testing.T) {
+	queue := createTestQueue(t)
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ids := seedStatuses(t, queue, base)
+
+	t.Run("by status", func(t *testing.T) {
+		for status, id := range ids {
+			got, err := queue.List(ctx, Filter{Status: status})
+			if err != nil {
+				t.Fatalf("failed to list: %v", err)
+			}
+			if len(got) != 1 || got[0].ID != id || got[0].Status() != status {
+				t.Errorf("status %s: expected only scrobble %d, got %+v", status, id, got)
+			}
+		}
+	})
+
+	t.Run("by artist", func(t *testing.T) {
+		got, err := queue.List(ctx, Filter{Artist: "cher"})
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+		if len(got) != 3 {
+			t.Errorf("expected 3 scrobbles by Cher, got %d", len(got))
+		}
+	})
+
+	t.Run("by time range", func(t *testing.T) {
+		got, err := queue.List(ctx, Filter{Since: base.Add(time.Hour), Until: base.Add(3 * time.Hour)})
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+		if len(got) != 2 || got[0].ID != ids[StatusIgnored] || got[1].ID != ids[StatusScrobbled] {
+			t.Errorf("expected ignored and scrobbled entries newest first, got %+v", got)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		got, err := queue.List(ctx, Filter{Limit: 2})
+		if err != nil {
+			t.Fatalf("failed to list: %v", err)
+		}
+		if len(got) != 2 || got[0].ID != ids[StatusFailed] {
+			t.Errorf("expected 2 newest scrobbles, got %+v", got)
+		}
+	})
+}
+
+func TestParseStatus(t *testing.T) {
+	if s, err := ParseStatus("Failed"); err != nil || s != StatusFailed {
+		t.Errorf("ParseStatus(Failed) = %q, %v", s, err)
+	}
+	if _, err := ParseStatus("stuck"); err == nil {
+		t.Error("expected error for unknown status")
+	}
+}
+
+func TestQueueRetry(t *testing.T) {
+	queue := createTestQueue(t)
+	ctx := context.Background()
+	ids := seedStatuses(t, queue, time.Now())
+
+	for _, status := range []Status{StatusIgnored, StatusFailed} {
+		if err := queue.Retry(ctx, ids[status]); err != nil {
+			t.Fatalf("failed to retry %s scrobble: %v", status, err)
+		}
+		s, err := queue.Get(ctx, ids[status])
+		if err != nil {
+			t.Fatalf("failed to get scrobble: %v", err)
+		}
+		if s.Status() != StatusPending || s.Error != "" || s.Attempts != 0 || s.IgnoredCode != 0 {
+			t.Errorf("expected %s scrobble to be reset to pending, got %+v", status, s)
+		}
+	}
+
+	pending, err := queue.GetPending(ctx, 0)
+	if err != nil {
+		t.Fatalf("failed to get pending: %v", err)
+	}
+	if len(pending) != 3 {
+		t.Errorf("expected retried scrobbles to be due immediately, got %d pending", len(pending))
+	}
+
+	if err := queue.Retry(ctx, ids[StatusScrobbled]); err == nil {
+		t.Error("expected error retrying a scrobbled entry")
+	}
+	if err := queue.Retry(ctx, 999); err == nil {
+		t.Error("expected error for non-existent ID")
+	}
+}
+
+func TestQueueEdit(t *testing.T) {
+	queue := createTestQueue(t)
+	ctx := context.Background()
+	ids := seedStatuses(t, queue, time.Now())
+
+	artist := "Cher"
+	if err := queue.Edit(ctx, ids[StatusFailed], ScrobbleEdit{Artist: &artist}); err != nil {
+		t.Fatalf("failed to edit: %v", err)
+	}
+
+	s, err := queue.Get(ctx, ids[StatusFailed])
+	if err != nil {
+		t.Fatalf("failed to get scrobble: %v", err)
+	}
+	if s.Artist != "Cher" || s.TrackName != "failed" || s.Album != "Album" {
+		t.Errorf("expected only the artist to change, got %+v", s)
+	}
+	if s.Status() != StatusPending || s.Error != "" {
+		t.Errorf("expected edited scrobble to be rescheduled, got %+v", s)
+	}
+
+	empty := ""
+	if err := queue.Edit(ctx, ids[StatusPending], ScrobbleEdit{Track: &empty}); err == nil {
+		t.Error("expected error for empty track")
+	}
+	if err := queue.Edit(ctx, ids[StatusScrobbled], ScrobbleEdit{Artist: &artist}); err == nil {
+		t.Error("expected error editing a scrobbled entry")
+	}
+}
+
+func TestQueueDeleteAndPurge(t *testing.T) {
+	queue := createTestQueue(t)
+	ctx := context.Background()
+	ids := seedStatuses(t, queue, time.Now())
+
+	if err := queue.Delete(ctx, ids[StatusPending]); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+	if err := queue.Delete(ctx, ids[StatusPending]); err == nil {
+		t.Error("expected error deleting a missing scrobble")
+	}
+
+	deleted, err := queue.Purge(ctx, Filter{Status: StatusFailed})
+	if err != nil {
+		t.Fatalf("failed to purge: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected 1 failed scrobble purged, got %d", deleted)
+	}
+
+	all, err := queue.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("failed to get all: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("expected scrobbled and ignored entries to remain, got %d", len(all))
+	}
+}
+
+func TestQueueHas(t *testing.T) {
+	queue := createTestQueue(t)
+	ctx := context.Background()
+
+	played := time.Date(2026, 3, 1, 18, 40, 0, 0, time.UTC)
+	if _, err := queue.Add(ctx, Scrobble{Artist: "Cher", Track: "Believe", Duration: 4 * time.Minute, Timestamp: played}); err != nil {
+		t.Fatalf("failed to add scrobble: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		scrobble Scrobble
+		want     bool
+	}{
+		{"same", Scrobble{Artist: "Cher", Track: "Believe", Timestamp: played}, true},
+		{"different case", Scrobble{Artist: "CHER", Track: "believe", Timestamp: played}, true},
+		{"different time", Scrobble{Artist: "Cher", Track: "Believe", Timestamp: played.Add(time.Second)}, false},
+		{"different track", Scrobble{Artist: "Cher", Track: "Strong Enough", Timestamp: played}, false},
+	}
+
+	for _, tt := range tests {
+		got, err := queue.Has(ctx, tt.scrobble)
+		if err != nil {
+			t.Fatalf("%s: failed to look up scrobble: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: Has = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
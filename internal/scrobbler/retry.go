@@ -0,0 +1,143 @@
+package scrobbler
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/jfmyers9/scribbles/pkg/lastfm"
+)
+
+// RetryPolicy controls how failed scrobbles are retried by the Queue
+type RetryPolicy struct {
+	MaxAttempts int           // Failures, apart from transient errors, before a scrobble is moved to the failed state
+	BaseDelay   time.Duration // Delay after the first failure
+	MaxDelay    time.Duration // Upper bound for the backoff delay
+	MaxAge      time.Duration // Age of a scrobble after which transient errors fail it too (0 for no limit)
+}
+
+// DefaultRetryPolicy backs off from one minute up to six hours. A scrobble
+// fails after 10 errors of its own (about 8.5 hours of retries at the
+// least). Transient errors are retried until the scrobble is 14 days old,
+// when Last.fm would ignore it anyway.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 10,
+	BaseDelay:   time.Minute,
+	MaxDelay:    6 * time.Hour,
+	MaxAge:      14 * 24 * time.Hour,
+}
+
+// Backoff returns how long to wait before the next attempt after the
+// given number of failed attempts. The delay doubles on each attempt.
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+
+	if delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// IsPermanentError reports whether a scrobble submission error will recur
+// no matter how often the same scrobble is retried, such as Last.fm
+// rejecting its parameters, ListenBrainz rejecting the request as
+// malformed, a webhook answering with a client error, its template
+// failing to render the scrobble or Last.fm ignoring scrobbles of a batch
+// without saying which. Network, service,
+// rate limit and authentication errors are not permanent: they clear up
+// once the service or session recovers.
+func IsPermanentError(err error) bool {
+	var tmplErr *WebhookTemplateError
+	if errors.As(err, &tmplErr) {
+		return true
+	}
+
+	var resultsErr *BatchResultsError
+	if errors.As(err, &resultsErr) {
+		return true
+	}
+
+	var lbErr *ListenBrainzError
+	if errors.As(err, &lbErr) {
+		return lbErr.StatusCode == http.StatusBadRequest
+	}
+
+	var whErr *WebhookError
+	if errors.As(err, &whErr) {
+		return whErr.Permanent()
+	}
+
+	var lfmErr *lastfm.Error
+	if !errors.As(err, &lfmErr) {
+		return false
+	}
+
+	switch lfmErr.Code {
+	case lastfm.ErrCodeInvalidParameters, lastfm.ErrCodeInvalidResourceSpec:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTransientError reports whether a scrobble submission error is caused
+// by the service rather than the scrobble: a network error, a server error,
+// a rate limit or the service being offline. Transient errors are retried
+// without counting toward the retry policy's MaxAttempts, so an outage
+// doesn't fail the whole queue. Any other error counts, including unknown
+// ones and rejected credentials, which need the user to step in.
+func IsTransientError(err error) bool {
+	if IsPermanentError(err) {
+		return false
+	}
+
+	var lbErr *ListenBrainzError
+	if errors.As(err, &lbErr) {
+		return isTransientStatus(lbErr.StatusCode)
+	}
+
+	var whErr *WebhookError
+	if errors.As(err, &whErr) {
+		return isTransientStatus(whErr.StatusCode)
+	}
+
+	var statusErr *lastfm.StatusError
+	if errors.As(err, &statusErr) {
+		return isTransientStatus(statusErr.StatusCode)
+	}
+
+	var lfmErr *lastfm.Error
+	if errors.As(err, &lfmErr) {
+		switch lfmErr.Code {
+		case lastfm.ErrCodeServiceOffline, lastfm.ErrCodeTempUnavailable,
+			lastfm.ErrCodeRateLimitExceeded:
+			return true
+		default:
+			return false
+		}
+	}
+
+	// The request never got an answer
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, context.Canceled)
+}
+
+// isTransientStatus reports whether an HTTP status means the service is
+// down or limiting requests
+func isTransientStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
@@ -0,0 +1,143 @@
+package scrobbler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jfmyers9/scribbles/pkg/lastfm"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   time.Minute,
+		MaxDelay:    10 * time.Minute,
+	}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{4, 8 * time.Minute},
+		{5, 10 * time.Minute},
+		{50, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestIsPermanentError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "invalid parameters",
+			err:  fmt.Errorf("failed to scrobble batch: %w", &lastfm.Error{Code: lastfm.ErrCodeInvalidParameters}),
+			want: true,
+		},
+		{
+			name: "invalid resource",
+			err:  &lastfm.Error{Code: lastfm.ErrCodeInvalidResourceSpec},
+			want: true,
+		},
+		{
+			name: "service offline",
+			err:  &lastfm.Error{Code: lastfm.ErrCodeServiceOffline},
+			want: false,
+		},
+		{
+			name: "invalid session key",
+			err:  &lastfm.Error{Code: lastfm.ErrCodeInvalidSessionKey},
+			want: false,
+		},
+		{
+			name: "network error",
+			err:  errors.New("connection refused"),
+			want: false,
+		},
+		{
+			name: "listenbrainz bad request",
+			err:  fmt.Errorf("failed to scrobble batch: %w", &ListenBrainzError{StatusCode: 400, Message: "invalid listen"}),
+			want: true,
+		},
+		{
+			name: "listenbrainz rate limited",
+			err:  &ListenBrainzError{StatusCode: 429, Message: "Too Many Requests"},
+			want: false,
+		},
+		{
+			name: "listenbrainz invalid token",
+			err:  &ListenBrainzError{StatusCode: 401, Message: "Invalid authorization token."},
+			want: false,
+		},
+		{
+			name: "webhook unprocessable",
+			err:  fmt.Errorf("failed to scrobble track: %w", &WebhookError{StatusCode: 422}),
+			want: true,
+		},
+		{
+			name: "webhook unavailable",
+			err:  &WebhookError{StatusCode: 503},
+			want: false,
+		},
+		{
+			name: "webhook unauthorized",
+			err:  &WebhookError{StatusCode: 401},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPermanentError(tt.err); got != tt.want {
+				t.Errorf("IsPermanentError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"network error", fmt.Errorf("failed to scrobble batch: %w", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}), true},
+		{"deadline exceeded", fmt.Errorf("failed to scrobble batch: %w", context.DeadlineExceeded), true},
+		{"unknown error", errors.New("unexpected response"), false},
+		{"server error", fmt.Errorf("max retries exceeded: %w", &lastfm.StatusError{StatusCode: 503}), true},
+		{"client error", &lastfm.StatusError{StatusCode: 404}, false},
+		{"service offline", &lastfm.Error{Code: lastfm.ErrCodeServiceOffline}, true},
+		{"rate limited", &lastfm.Error{Code: lastfm.ErrCodeRateLimitExceeded}, true},
+		{"invalid session key", &lastfm.Error{Code: lastfm.ErrCodeInvalidSessionKey}, false},
+		{"invalid parameters", &lastfm.Error{Code: lastfm.ErrCodeInvalidParameters}, false},
+		{"listenbrainz unavailable", &ListenBrainzError{StatusCode: 503}, true},
+		{"listenbrainz rate limited", &ListenBrainzError{StatusCode: 429}, true},
+		{"listenbrainz invalid token", &ListenBrainzError{StatusCode: 401}, false},
+		{"listenbrainz too large", &ListenBrainzError{StatusCode: 413}, false},
+		{"webhook forbidden", &WebhookError{StatusCode: 403}, false},
+		{"webhook unprocessable", &WebhookError{StatusCode: 422}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransientError(tt.err); got != tt.want {
+				t.Errorf("IsTransientError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
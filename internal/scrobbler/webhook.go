@@ -0,0 +1,334 @@
+package scrobbler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+// Webhook events
+const (
+	WebhookEventScrobble   = "scrobble"
+	WebhookEventNowPlaying = "now_playing"
+)
+
+// Headers set on every webhook request
+const (
+	WebhookEventHeader     = "X-Scribbles-Event"
+	WebhookTimestampHeader = "X-Scribbles-Timestamp"
+	WebhookSignatureHeader = "X-Scribbles-Signature"
+)
+
+// WebhookConfig holds webhook backend configuration
+type WebhookConfig struct {
+	Name       string            // Required: identifies the webhook in logs and in the queue
+	URL        string            // Required: endpoint every event is POSTed to
+	Secret     string            // Optional: key for the HMAC-SHA256 signature header
+	Template   string            // Optional: text/template for the body (defaults to a JSON event)
+	Headers    map[string]string // Optional: extra request headers, e.g. Authorization
+	NowPlaying bool              // Also POST now playing events, not just scrobbles
+	HTTPClient *http.Client      // Optional: HTTP client (defaults to a client with a 10s timeout)
+}
+
+// Webhook is a scrobbling backend that POSTs each scrobble, and optionally
+// each now playing update, to a URL. Scrobbles go through the queue like
+// any other backend's, so a webhook that is down is retried with backoff.
+//
+// With a secret, each request is signed: the X-Scribbles-Signature header
+// is "sha256=" followed by the hex HMAC-SHA256 of the X-Scribbles-Timestamp
+// header value, a ".", and the body.
+type Webhook struct {
+	name       string
+	url        string
+	secret     []byte
+	template   *template.Template // nil for the default JSON body
+	headers    map[string]string
+	nowPlaying bool
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// WebhookEvent is the data a webhook body is rendered from
+type WebhookEvent struct {
+	Event     string // WebhookEventScrobble or WebhookEventNowPlaying
+	Artist    string
+	Track     string
+	Album     string
+	Duration  time.Duration
+	Timestamp time.Time // When the track was played; zero for now playing
+}
+
+// webhookPayload is the default JSON body of a webhook request
+type webhookPayload struct {
+	Event           string     `json:"event"`
+	Artist          string     `json:"artist"`
+	Track           string     `json:"track"`
+	Album           string     `json:"album,omitempty"`
+	DurationSeconds int64      `json:"duration_seconds,omitempty"`
+	Timestamp       *time.Time `json:"timestamp,omitempty"`
+	Client          string     `json:"client"`
+}
+
+// WebhookError is an unsuccessful response from a webhook endpoint
+type WebhookError struct {
+	StatusCode int    // HTTP status code
+	Body       string // Start of the response body
+}
+
+// Error returns the error message
+func (e *WebhookError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("webhook: HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("webhook: HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// WebhookTemplateError is a body template that failed to render an event.
+// It is permanent: rendering the same event again fails the same way.
+type WebhookTemplateError struct {
+	Webhook string
+	Err     error
+}
+
+// Error returns the error message
+func (e *WebhookTemplateError) Error() string {
+	return fmt.Sprintf("webhook %s: failed to render template: %v", e.Webhook, e.Err)
+}
+
+// Unwrap returns the template's error
+func (e *WebhookTemplateError) Unwrap() error {
+	return e.Err
+}
+
+// sampleWebhookEvent is rendered by NewWebhook to check a template
+var sampleWebhookEvent = WebhookEvent{
+	Event:     WebhookEventScrobble,
+	Artist:    "Artist",
+	Track:     "Track",
+	Album:     "Album",
+	Duration:  3 * time.Minute,
+	Timestamp: time.Unix(1772389800, 0),
+}
+
+// Permanent reports whether the endpoint rejected the request itself, so
+// sending it again will not help. Timeouts, rate limits and authentication
+// failures are not: the last clears up once the config is fixed, so they
+// are retried up to the retry policy's MaxAttempts.
+func (e *WebhookError) Permanent() bool {
+	switch e.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	default:
+		return e.StatusCode >= 400 && e.StatusCode < 500
+	}
+}
+
+// webhookTemplateFuncs are available in webhook body templates
+var webhookTemplateFuncs = template.FuncMap{
+	// json encodes a value as JSON, so strings are quoted and escaped
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// NewWebhook creates a new webhook backend
+func NewWebhook(cfg WebhookConfig) (*Webhook, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("webhook: name is required")
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("webhook %s: url is required", cfg.Name)
+	}
+
+	var tmpl *template.Template
+	if cfg.Template != "" {
+		var err error
+		tmpl, err = template.New(cfg.Name).Funcs(webhookTemplateFuncs).Option("missingkey=error").Parse(cfg.Template)
+		if err != nil {
+			return nil, fmt.Errorf("webhook %s: invalid template: %w", cfg.Name, err)
+		}
+		// Catch references to missing fields now rather than on every scrobble
+		if err := tmpl.Execute(io.Discard, sampleWebhookEvent); err != nil {
+			return nil, fmt.Errorf("webhook %s: invalid template: %w", cfg.Name, err)
+		}
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Webhook{
+		name:       cfg.Name,
+		url:        cfg.URL,
+		secret:     []byte(cfg.Secret),
+		template:   tmpl,
+		headers:    cfg.Headers,
+		nowPlaying: cfg.NowPlaying,
+		httpClient: httpClient,
+		now:        time.Now,
+	}, nil
+}
+
+// Name identifies the webhook as a Backend: "webhook:" and its name
+func (wh *Webhook) Name() string {
+	return "webhook:" + wh.name
+}
+
+// UpdateNowPlaying POSTs a now playing event if the webhook wants them.
+// Now playing events are not queued, so a failed one is not retried.
+func (wh *Webhook) UpdateNowPlaying(ctx context.Context, artist, track, album string, duration time.Duration) error {
+	if !wh.nowPlaying {
+		return nil
+	}
+
+	err := wh.post(ctx, WebhookEvent{
+		Event:    WebhookEventNowPlaying,
+		Artist:   artist,
+		Track:    track,
+		Album:    album,
+		Duration: duration,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update now playing: %w", err)
+	}
+	return nil
+}
+
+// ScrobbleTrack POSTs a single scrobble event
+func (wh *Webhook) ScrobbleTrack(ctx context.Context, artist, track, album string, timestamp time.Time, duration time.Duration) error {
+	err := wh.post(ctx, WebhookEvent{
+		Event:     WebhookEventScrobble,
+		Artist:    artist,
+		Track:     track,
+		Album:     album,
+		Duration:  duration,
+		Timestamp: timestamp,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scrobble track: %w", err)
+	}
+	return nil
+}
+
+// ScrobbleBatch POSTs each scrobble in its own request, in order. A
+// scrobble that fails is reported in its result's Err. After a transient
+// failure the endpoint is assumed to be down, so the rest of the batch is
+// not sent and is reported with the same error.
+func (wh *Webhook) ScrobbleBatch(ctx context.Context, scrobbles []Scrobble) ([]ScrobbleResult, error) {
+	if len(scrobbles) > MaxBatchSize {
+		return nil, fmt.Errorf("cannot scrobble more than %d tracks at once (got %d)", MaxBatchSize, len(scrobbles))
+	}
+
+	results := make([]ScrobbleResult, len(scrobbles))
+	var down error
+	for i, s := range scrobbles {
+		if down != nil {
+			results[i].Err = down
+			continue
+		}
+
+		err := wh.ScrobbleTrack(ctx, s.Artist, s.Track, s.Album, s.Timestamp, s.Duration)
+		switch {
+		case err == nil:
+			results[i].Accepted = true
+		case IsPermanentError(err):
+			results[i].Err = err
+		default:
+			results[i].Err = err
+			down = err
+		}
+	}
+
+	return results, nil
+}
+
+// render builds the request body for an event
+func (wh *Webhook) render(event WebhookEvent) ([]byte, error) {
+	if wh.template != nil {
+		var buf bytes.Buffer
+		if err := wh.template.Execute(&buf, event); err != nil {
+			return nil, &WebhookTemplateError{Webhook: wh.name, Err: err}
+		}
+		return buf.Bytes(), nil
+	}
+
+	payload := webhookPayload{
+		Event:           event.Event,
+		Artist:          event.Artist,
+		Track:           event.Track,
+		Album:           event.Album,
+		DurationSeconds: int64(event.Duration.Seconds()),
+		Client:          "scribbles",
+	}
+	if !event.Timestamp.IsZero() {
+		ts := event.Timestamp.UTC()
+		payload.Timestamp = &ts
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("webhook %s: failed to encode event: %w", wh.name, err)
+	}
+	return body, nil
+}
+
+// sign returns the signature header value for a request body
+func (wh *Webhook) sign(timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, wh.secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// post sends an event to the webhook. Any 2xx response is a success;
+// others become *WebhookError.
+func (wh *Webhook) post(ctx context.Context, event WebhookEvent) error {
+	body, err := wh.render(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook %s: failed to create request: %w", wh.name, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "scribbles")
+	for k, v := range wh.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set(WebhookEventHeader, event.Event)
+	if len(wh.secret) > 0 {
+		timestamp := strconv.FormatInt(wh.now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, wh.sign(timestamp, body))
+	}
+
+	resp, err := wh.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s: request failed: %w", wh.name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Only the start of the body is kept, for the queue's error column
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return &WebhookError{StatusCode: resp.StatusCode, Body: string(bytes.TrimSpace(data))}
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
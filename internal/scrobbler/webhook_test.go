@@ -0,0 +1,232 @@
+package scrobbler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// webhookRequest is a request received by fakeWebhookEndpoint
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// fakeWebhookEndpoint records requests and replies with a status per
+// request, defaulting to 204
+type fakeWebhookEndpoint struct {
+	statuses []int
+	requests []webhookRequest
+}
+
+func (f *fakeWebhookEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	f.requests = append(f.requests, webhookRequest{header: r.Header.Clone(), body: body})
+
+	status := http.StatusNoContent
+	if n := len(f.requests); n <= len(f.statuses) && f.statuses[n-1] != 0 {
+		status = f.statuses[n-1]
+	}
+	w.WriteHeader(status)
+}
+
+// newTestWebhook starts a fake endpoint and returns a webhook posting to it
+func newTestWebhook(t *testing.T, cfg WebhookConfig) (*Webhook, *fakeWebhookEndpoint) {
+	t.Helper()
+
+	endpoint := &fakeWebhookEndpoint{}
+	server := httptest.NewServer(endpoint)
+	t.Cleanup(server.Close)
+
+	if cfg.Name == "" {
+		cfg.Name = "warehouse"
+	}
+	cfg.URL = server.URL
+	wh, err := NewWebhook(cfg)
+	if err != nil {
+		t.Fatalf("NewWebhook: %v", err)
+	}
+	wh.now = func() time.Time { return time.Unix(1772389800, 0) }
+
+	return wh, endpoint
+}
+
+func TestNewWebhook(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  WebhookConfig
+	}{
+		{name: "missing name", cfg: WebhookConfig{URL: "http://localhost"}},
+		{name: "missing url", cfg: WebhookConfig{Name: "ha"}},
+		{name: "bad template", cfg: WebhookConfig{Name: "ha", URL: "http://localhost", Template: "{{.Artist"}},
+		{name: "missing field", cfg: WebhookConfig{Name: "ha", URL: "http://localhost", Template: "{{.Song}}"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewWebhook(tt.cfg); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestWebhook_ScrobbleTrack(t *testing.T) {
+	wh, endpoint := newTestWebhook(t, WebhookConfig{
+		Secret:  "s3cret",
+		Headers: map[string]string{"Authorization": "Bearer ha-token"},
+	})
+
+	played := time.Date(2026, 3, 1, 18, 30, 0, 0, time.UTC)
+	if err := wh.ScrobbleTrack(context.Background(), "Cher", "Believe", "Believe", played, 239*time.Second); err != nil {
+		t.Fatalf("ScrobbleTrack: %v", err)
+	}
+
+	if len(endpoint.requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(endpoint.requests))
+	}
+	req := endpoint.requests[0]
+
+	var payload webhookPayload
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("invalid JSON body: %v\n%s", err, req.body)
+	}
+	if payload.Event != WebhookEventScrobble || payload.Artist != "Cher" || payload.DurationSeconds != 239 ||
+		payload.Timestamp == nil || !payload.Timestamp.Equal(played) {
+		t.Errorf("unexpected payload: %s", req.body)
+	}
+
+	if got := req.header.Get("Authorization"); got != "Bearer ha-token" {
+		t.Errorf("expected custom header, got %q", got)
+	}
+	if got := req.header.Get(WebhookEventHeader); got != WebhookEventScrobble {
+		t.Errorf("expected event header %q, got %q", WebhookEventScrobble, got)
+	}
+
+	// A receiver verifies the signature over the timestamp and body
+	timestamp := req.header.Get(WebhookTimestampHeader)
+	if timestamp != "1772389800" {
+		t.Errorf("unexpected timestamp header %q", timestamp)
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(req.body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); req.header.Get(WebhookSignatureHeader) != want {
+		t.Errorf("expected signature %q, got %q", want, req.header.Get(WebhookSignatureHeader))
+	}
+}
+
+func TestWebhook_Template(t *testing.T) {
+	wh, endpoint := newTestWebhook(t, WebhookConfig{
+		Template: `{"state": {{json .Track}}, "attributes": {"artist": {{json .Artist}}, "seconds": {{.Duration.Seconds}}}}`,
+	})
+
+	if err := wh.ScrobbleTrack(context.Background(), `Guns N' Roses`, `"Sweet Child"`, "", time.Now(), 90*time.Second); err != nil {
+		t.Fatalf("ScrobbleTrack: %v", err)
+	}
+
+	var body struct {
+		State      string `json:"state"`
+		Attributes struct {
+			Artist  string  `json:"artist"`
+			Seconds float64 `json:"seconds"`
+		} `json:"attributes"`
+	}
+	if err := json.Unmarshal(endpoint.requests[0].body, &body); err != nil {
+		t.Fatalf("template did not render valid JSON: %v\n%s", err, endpoint.requests[0].body)
+	}
+	if body.State != `"Sweet Child"` || body.Attributes.Artist != `Guns N' Roses` || body.Attributes.Seconds != 90 {
+		t.Errorf("unexpected rendered body: %s", endpoint.requests[0].body)
+	}
+	if got := endpoint.requests[0].header.Get(WebhookSignatureHeader); got != "" {
+		t.Errorf("expected no signature without a secret, got %q", got)
+	}
+}
+
+func TestWebhook_TemplateErrorIsPermanent(t *testing.T) {
+	// Renders the sample event, but not a scrobble without an album
+	wh, endpoint := newTestWebhook(t, WebhookConfig{Template: `{"album": {{json (slice .Album 0 3)}}}`})
+
+	err := wh.ScrobbleTrack(context.Background(), "Cher", "Believe", "", time.Now(), 239*time.Second)
+	if err == nil || !IsPermanentError(err) {
+		t.Errorf("expected a permanent error, got %v", err)
+	}
+	if len(endpoint.requests) != 0 {
+		t.Errorf("expected nothing to be sent, got %d requests", len(endpoint.requests))
+	}
+}
+
+func TestWebhook_UpdateNowPlaying(t *testing.T) {
+	ctx := context.Background()
+
+	quiet, endpoint := newTestWebhook(t, WebhookConfig{})
+	if err := quiet.UpdateNowPlaying(ctx, "Cher", "Believe", "", time.Minute); err != nil {
+		t.Fatalf("UpdateNowPlaying: %v", err)
+	}
+	if len(endpoint.requests) != 0 {
+		t.Errorf("expected now playing to be skipped, got %d requests", len(endpoint.requests))
+	}
+
+	wh, endpoint := newTestWebhook(t, WebhookConfig{NowPlaying: true})
+	if err := wh.UpdateNowPlaying(ctx, "Cher", "Believe", "", time.Minute); err != nil {
+		t.Fatalf("UpdateNowPlaying: %v", err)
+	}
+	if len(endpoint.requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(endpoint.requests))
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(endpoint.requests[0].body, &payload); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	if payload["event"] != WebhookEventNowPlaying {
+		t.Errorf("expected a now playing event, got %v", payload)
+	}
+	if _, ok := payload["timestamp"]; ok {
+		t.Error("expected no timestamp for now playing")
+	}
+}
+
+func TestWebhook_ScrobbleBatch(t *testing.T) {
+	scrobbles := []Scrobble{
+		{Artist: "Cher", Track: "Believe", Timestamp: time.Now()},
+		{Artist: "Cher", Track: "Bad Data", Timestamp: time.Now()},
+		{Artist: "Cher", Track: "Strong Enough", Timestamp: time.Now()},
+		{Artist: "Cher", Track: "Dov'è l'amore", Timestamp: time.Now()},
+	}
+
+	wh, endpoint := newTestWebhook(t, WebhookConfig{})
+	endpoint.statuses = []int{0, http.StatusUnprocessableEntity, http.StatusServiceUnavailable}
+
+	results, err := wh.ScrobbleBatch(context.Background(), scrobbles)
+	if err != nil {
+		t.Fatalf("ScrobbleBatch: %v", err)
+	}
+
+	if !results[0].Accepted || results[0].Err != nil {
+		t.Errorf("expected first scrobble to be accepted, got %+v", results[0])
+	}
+	if !IsPermanentError(results[1].Err) {
+		t.Errorf("expected a permanent error for the rejected scrobble, got %v", results[1].Err)
+	}
+
+	// The endpoint went down, so the last scrobble is not sent at all
+	var whErr *WebhookError
+	if !errors.As(results[2].Err, &whErr) || whErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected a 503 for the third scrobble, got %v", results[2].Err)
+	}
+	if results[3].Accepted || results[3].Err == nil {
+		t.Errorf("expected the fourth scrobble to fail with the endpoint, got %+v", results[3])
+	}
+	if len(endpoint.requests) != 3 {
+		t.Errorf("expected 3 requests, got %d", len(endpoint.requests))
+	}
+}
@@ -0,0 +1,88 @@
+// Package sqlitemigrate upgrades the schema of a SQLite database with a
+// list of numbered migrations. The schema version is stored in the
+// database's user_version pragma; a migration runs only if its version is
+// greater than the stored one.
+package sqlitemigrate
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrSchemaTooNew is returned by Migrate when the database was written by
+// a newer version of scribbles than this one
+var ErrSchemaTooNew = errors.New("database schema is newer than this version of scribbles supports")
+
+// Migration upgrades a schema by one version
+type Migration struct {
+	Version     int
+	Description string
+	SQL         string
+}
+
+// Latest returns the schema version migrations upgrade to
+func Latest(migrations []Migration) int {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// Migrate brings the database schema up to the latest of migrations,
+// which must be in order, applying each pending one in its own transaction
+func Migrate(db *sql.DB, migrations []Migration) error {
+	current, err := UserVersion(db)
+	if err != nil {
+		return err
+	}
+
+	if latest := Latest(migrations); current > latest {
+		return fmt.Errorf("%w: database is version %d, supported version is %d",
+			ErrSchemaTooNew, current, latest)
+	}
+
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+		if err := Apply(db, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Apply runs a single migration and records its version atomically
+func Apply(db *sql.DB, m Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %d: %w", m.Version, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(m.SQL); err != nil {
+		return fmt.Errorf("failed to apply migration %d (%s): %w", m.Version, m.Description, err)
+	}
+
+	// PRAGMA does not accept bound parameters
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", m.Version)); err != nil {
+		return fmt.Errorf("failed to record schema version %d: %w", m.Version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %d: %w", m.Version, err)
+	}
+
+	return nil
+}
+
+// UserVersion returns the schema version stored in the database
+func UserVersion(db *sql.DB) (int, error) {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
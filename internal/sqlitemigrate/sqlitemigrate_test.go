@@ -0,0 +1,85 @@
+package sqlitemigrate
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+var testMigrations = []Migration{
+	{Version: 1, Description: "create items", SQL: `CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT NOT NULL);`},
+	{Version: 2, Description: "add items.note", SQL: `ALTER TABLE items ADD COLUMN note TEXT;`},
+}
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestMigrate(t *testing.T) {
+	db := openTestDB(t)
+
+	if err := Migrate(db, testMigrations[:1]); err != nil {
+		t.Fatalf("failed to migrate to version 1: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO items (name) VALUES ('kept')`); err != nil {
+		t.Fatalf("failed to insert: %v", err)
+	}
+
+	// Only the pending migration runs: rerunning the first would fail
+	if err := Migrate(db, testMigrations); err != nil {
+		t.Fatalf("failed to migrate to version 2: %v", err)
+	}
+	if version, err := UserVersion(db); err != nil || version != 2 {
+		t.Errorf("expected user_version 2, got %d, %v", version, err)
+	}
+	var name string
+	if err := db.QueryRow(`SELECT name FROM items WHERE note IS NULL`).Scan(&name); err != nil || name != "kept" {
+		t.Errorf("expected the row to survive the upgrade, got %q, %v", name, err)
+	}
+
+	if err := Migrate(db, testMigrations[:1]); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("expected ErrSchemaTooNew, got %v", err)
+	}
+}
+
+func TestApplyRollsBackOnFailure(t *testing.T) {
+	db := openTestDB(t)
+
+	if err := Apply(db, testMigrations[0]); err != nil {
+		t.Fatalf("failed to apply first migration: %v", err)
+	}
+
+	bad := Migration{
+		Version:     2,
+		Description: "broken",
+		SQL: `
+			ALTER TABLE items ADD COLUMN half_done TEXT;
+			ALTER TABLE no_such_table ADD COLUMN x TEXT;
+		`,
+	}
+	if err := Apply(db, bad); err == nil {
+		t.Fatal("expected broken migration to fail")
+	}
+
+	version, err := UserVersion(db)
+	if err != nil {
+		t.Fatalf("failed to read user_version: %v", err)
+	}
+	if version != 1 {
+		t.Errorf("expected user_version to stay 1, got %d", version)
+	}
+
+	// The partially applied statement must have been rolled back
+	if _, err := db.Exec("SELECT half_done FROM items"); err == nil {
+		t.Error("expected half_done column to be rolled back")
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/jfmyers9/scribbles/internal/daemon"
+	"github.com/jfmyers9/scribbles/internal/events"
 	"github.com/jfmyers9/scribbles/internal/music"
 	"github.com/rivo/tview"
 )
@@ -53,7 +54,7 @@ type App struct {
 	// Music client for controls
 	musicClient music.Client
 
-	// Mutex protects shared state accessed by both the channel consumer
+	// Mutex protects shared state accessed by both the event consumer
 	// goroutine and the ticker goroutine in handleUpdates.
 	mu sync.Mutex
 
@@ -66,7 +67,6 @@ type App struct {
 	sessionStart    time.Time
 	tracksPlayed    int
 	scrobblesSubmit int
-	lastScrobbled   bool // tracks scrobble transition for accurate counting
 
 	// Ring buffer for recent tracks (avoids allocation on every track change)
 	recentBuf   [maxRecentTracks]RecentTrack
@@ -208,8 +208,8 @@ func (a *App) handleKeyEvent(event *tcell.EventKey) *tcell.EventKey {
 	return event
 }
 
-// Run starts the TUI with a track update channel from the daemon
-func (a *App) Run(ctx context.Context, updates <-chan daemon.TrackUpdate, stateGetter func() daemon.TrackState, playedGetter func() time.Duration) error {
+// Run starts the TUI with a subscription to the daemon's events
+func (a *App) Run(ctx context.Context, updates <-chan events.Event, stateGetter func() daemon.TrackState, playedGetter func() time.Duration) error {
 	// Create cancellable context
 	ctx, a.cancelFunc = context.WithCancel(ctx)
 
@@ -224,42 +224,49 @@ func (a *App) Run(ctx context.Context, updates <-chan daemon.TrackUpdate, stateG
 	return nil
 }
 
-// handleUpdates processes track updates and refreshes the display.
-// It splits work into two goroutines: one consumes channel updates (state only),
-// and a single ticker drives all redraws to prevent queued redraw buildup.
+// handleUpdates processes daemon events and refreshes the display.
+// It splits work into two goroutines: one consumes events (session stats and
+// recent tracks only), and a single ticker drives all redraws to prevent
+// queued redraw buildup. The ticker takes the current track from the
+// daemon's state, which holds the latest position and play state.
 // All shared App fields are protected by a.mu.
 //
 // To avoid a race between the ticker's state snapshot and tview's deferred
 // QueueUpdateDraw execution, the ticker builds ALL display strings while
 // holding a.mu, then passes them as captured values to QueueUpdateDraw.
 // The closure on tview's event loop never re-acquires a.mu.
-func (a *App) handleUpdates(ctx context.Context, updates <-chan daemon.TrackUpdate, stateGetter func() daemon.TrackState, playedGetter func() time.Duration) {
-	var lastTrackName string
-
-	// Channel consumer goroutine: updates track info but does NOT trigger redraws.
+func (a *App) handleUpdates(ctx context.Context, updates <-chan events.Event, stateGetter func() daemon.TrackState, playedGetter func() time.Duration) {
+	// Event consumer goroutine: updates session stats but does NOT trigger redraws.
 	// The ticker goroutine is the sole caller of stateGetter() and refresh().
 	go func() {
+		var playing *music.Track // Track of the current play
+		var scrobbled bool       // Whether the current play was queued for scrobbling
+
 		for {
 			select {
 			case <-ctx.Done():
 				return
-			case update := <-updates:
-				if update.Err != nil {
-					continue
+			case e, ok := <-updates:
+				if !ok {
+					return
 				}
 
 				a.mu.Lock()
-				// Check for track change
-				if update.Track != nil && update.Track.Name != lastTrackName {
-					// Add previous track to recent list
-					if a.currentTrack != nil && lastTrackName != "" {
-						a.addToRecentTracks(a.currentTrack, a.trackState)
-						a.tracksPlayed++
+				switch e := e.(type) {
+				case events.TrackStarted:
+					a.finishPlay(playing, scrobbled)
+					playing, scrobbled = &e.Track, false
+					if e.Resumed {
+						// Already counted when another source took over
+						playing = nil
 					}
-					lastTrackName = update.Track.Name
+				case events.TrackStopped:
+					a.finishPlay(playing, scrobbled)
+					playing, scrobbled = nil, false
+				case events.ScrobbleQueued:
+					scrobbled = true
+					a.scrobblesSubmit++
 				}
-
-				a.currentTrack = update.Track
 				a.mu.Unlock()
 			}
 		}
@@ -286,12 +293,7 @@ func (a *App) handleUpdates(ctx context.Context, updates <-chan daemon.TrackUpda
 			if stateGetter != nil {
 				state := stateGetter()
 				a.trackState = &state
-
-				// Increment scrobble count on transition from not-scrobbled to scrobbled
-				if a.trackState.Scrobbled && !a.lastScrobbled {
-					a.scrobblesSubmit++
-				}
-				a.lastScrobbled = a.trackState.Scrobbled
+				a.currentTrack = state.Track
 			}
 
 			npText := a.buildNowPlayingText()
@@ -307,16 +309,21 @@ func (a *App) handleUpdates(ctx context.Context, updates <-chan daemon.TrackUpda
 	}
 }
 
-// addToRecentTracks adds a track to the ring buffer of recent tracks.
+// finishPlay adds a finished play to the recent tracks and session stats.
 // Must be called with a.mu held.
-func (a *App) addToRecentTracks(track *music.Track, state *daemon.TrackState) {
+func (a *App) finishPlay(track *music.Track, scrobbled bool) {
 	if track == nil {
 		return
 	}
+	a.addToRecentTracks(track, scrobbled)
+	a.tracksPlayed++
+}
 
-	scrobbled := false
-	if state != nil {
-		scrobbled = state.Scrobbled
+// addToRecentTracks adds a track to the ring buffer of recent tracks.
+// Must be called with a.mu held.
+func (a *App) addToRecentTracks(track *music.Track, scrobbled bool) {
+	if track == nil {
+		return
 	}
 
 	// Write into ring buffer at the current position
@@ -0,0 +1,121 @@
+package lastfm
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// AlbumService provides album metadata operations for the Last.fm API.
+type AlbumService struct {
+	client *Client
+}
+
+// GetInfo returns metadata for an album, including its tracklist and
+// cover art at each available size.
+//
+// If opts.Username is set, the response includes that user's play count
+// for the album.
+//
+// Does not require authentication.
+//
+// Example:
+//
+//	info, err := client.Album().GetInfo(ctx, "Cher", "Believe", lastfm.AlbumInfoOptions{})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println("Cover:", info.ImageURL(lastfm.ImageSizeExtraLarge))
+func (s *AlbumService) GetInfo(ctx context.Context, artist, album string, opts AlbumInfoOptions) (*AlbumInfo, error) {
+	params := map[string]string{}
+	if opts.MBID != "" {
+		params["mbid"] = opts.MBID
+	} else {
+		if artist == "" || album == "" {
+			return nil, fmt.Errorf("lastfm: artist and album are required")
+		}
+		params["artist"] = artist
+		params["album"] = album
+	}
+	if opts.Username != "" {
+		params["username"] = opts.Username
+	}
+	if opts.Autocorrect {
+		params["autocorrect"] = "1"
+	}
+
+	resp, err := s.client.call(ctx, "album.getInfo", params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := unmarshalAlbumInfo(resp)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: failed to parse album info response: %w", err)
+	}
+
+	return info, nil
+}
+
+// albumInfoResponse represents the XML response from album.getInfo.
+type albumInfoResponse struct {
+	Album struct {
+		Name          string     `xml:"name"`
+		Artist        string     `xml:"artist"`
+		MBID          string     `xml:"mbid"`
+		URL           string     `xml:"url"`
+		Images        []imageXML `xml:"image"`
+		Listeners     int        `xml:"listeners"`
+		PlayCount     int        `xml:"playcount"`
+		UserPlayCount int        `xml:"userplaycount"`
+		Tracks        []struct {
+			Rank     int          `xml:"rank,attr"`
+			Name     string       `xml:"name"`
+			URL      string       `xml:"url"`
+			Duration int          `xml:"duration"`
+			Artist   artistRefXML `xml:"artist"`
+		} `xml:"tracks>track"`
+		Tags []tagXML `xml:"tags>tag"`
+		Wiki struct {
+			Summary string `xml:"summary"`
+		} `xml:"wiki"`
+	} `xml:"album"`
+}
+
+// unmarshalAlbumInfo parses the XML response from album.getInfo.
+func unmarshalAlbumInfo(data []byte) (*AlbumInfo, error) {
+	// Wrap inner XML in root element for proper unmarshaling
+	wrapped := []byte("<root>" + string(data) + "</root>")
+
+	var resp albumInfoResponse
+	if err := xml.Unmarshal(wrapped, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal album info response: %w", err)
+	}
+
+	a := resp.Album
+	info := &AlbumInfo{
+		Name:          a.Name,
+		Artist:        a.Artist,
+		MBID:          a.MBID,
+		URL:           a.URL,
+		Images:        toImages(a.Images),
+		Listeners:     a.Listeners,
+		PlayCount:     a.PlayCount,
+		UserPlayCount: a.UserPlayCount,
+		Tracks:        make([]AlbumTrack, len(a.Tracks)),
+		Tags:          toTags(a.Tags),
+		Summary:       strings.TrimSpace(a.Wiki.Summary),
+	}
+	for i, t := range a.Tracks {
+		info.Tracks[i] = AlbumTrack{
+			Rank:     t.Rank,
+			Name:     t.Name,
+			URL:      t.URL,
+			Duration: t.Duration,
+			Artist:   t.Artist.name(),
+		}
+	}
+
+	return info, nil
+}
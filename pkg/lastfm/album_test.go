@@ -0,0 +1,128 @@
+package lastfm
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestAlbumService_GetInfo(t *testing.T) {
+	response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<album>
+		<name>Believe</name>
+		<artist>Cher</artist>
+		<mbid>album-mbid</mbid>
+		<url>https://www.last.fm/music/Cher/Believe</url>
+		<image size="small">https://img/s.png</image>
+		<image size="medium">https://img/m.png</image>
+		<image size="large">https://img/l.png</image>
+		<image size="extralarge">https://img/xl.png</image>
+		<listeners>400</listeners>
+		<playcount>9000</playcount>
+		<userplaycount>7</userplaycount>
+		<tracks>
+			<track rank="1">
+				<name>Believe</name>
+				<url>https://www.last.fm/music/Cher/_/Believe</url>
+				<duration>239</duration>
+				<artist><name>Cher</name></artist>
+			</track>
+			<track rank="2">
+				<name>The Power</name>
+				<duration>236</duration>
+				<artist><name>Cher</name></artist>
+			</track>
+		</tracks>
+		<tags>
+			<tag><name>pop</name><url>https://www.last.fm/tag/pop</url></tag>
+		</tags>
+		<wiki>
+			<summary> Cher's 22nd album. </summary>
+		</wiki>
+	</album>
+</lfm>`
+
+	client := newMethodServer(t, "album.getInfo", response, func(r *http.Request) {
+		if got := r.FormValue("artist"); got != "Cher" {
+			t.Errorf("expected artist Cher, got %s", got)
+		}
+		if got := r.FormValue("album"); got != "Believe" {
+			t.Errorf("expected album Believe, got %s", got)
+		}
+		if got := r.FormValue("autocorrect"); got != "1" {
+			t.Errorf("expected autocorrect 1, got %s", got)
+		}
+	})
+
+	info, err := client.Album().GetInfo(context.Background(), "Cher", "Believe", AlbumInfoOptions{Autocorrect: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Name != "Believe" || info.Artist != "Cher" || info.MBID != "album-mbid" {
+		t.Errorf("unexpected album: %+v", info)
+	}
+	if info.Listeners != 400 || info.PlayCount != 9000 || info.UserPlayCount != 7 {
+		t.Errorf("unexpected stats: %+v", info)
+	}
+	if len(info.Tracks) != 2 || info.Tracks[1].Name != "The Power" || info.Tracks[1].Rank != 2 || info.Tracks[1].Duration != 236 {
+		t.Errorf("unexpected tracks: %+v", info.Tracks)
+	}
+	if len(info.Tags) != 1 || info.Summary != "Cher's 22nd album." {
+		t.Errorf("unexpected tags or summary: %+v, %q", info.Tags, info.Summary)
+	}
+}
+
+func TestAlbumService_GetInfo_ByMBID(t *testing.T) {
+	client := newMethodServer(t, "album.getInfo", `<lfm status="ok"><album><name>Believe</name></album></lfm>`, func(r *http.Request) {
+		if got := r.FormValue("mbid"); got != "album-mbid" {
+			t.Errorf("expected mbid album-mbid, got %s", got)
+		}
+		if _, ok := r.Form["album"]; ok {
+			t.Error("expected no album param when looking up by mbid")
+		}
+	})
+
+	if _, err := client.Album().GetInfo(context.Background(), "", "", AlbumInfoOptions{MBID: "album-mbid"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAlbumService_RequiresArtistAndAlbum(t *testing.T) {
+	client, err := NewClient(Config{APIKey: "key", APISecret: "secret"})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if _, err := client.Album().GetInfo(context.Background(), "Cher", "", AlbumInfoOptions{}); err == nil {
+		t.Error("expected error for empty album")
+	}
+}
+
+func TestAlbumInfo_ImageURL(t *testing.T) {
+	info := &AlbumInfo{Images: []Image{
+		{Size: ImageSizeSmall, URL: "https://img/s.png"},
+		{Size: ImageSizeLarge, URL: "https://img/l.png"},
+		{Size: ImageSizeExtraLarge, URL: "https://img/xl.png"},
+	}}
+
+	tests := []struct {
+		size string
+		want string
+	}{
+		{ImageSizeSmall, "https://img/s.png"},
+		{ImageSizeExtraLarge, "https://img/xl.png"},
+		{ImageSizeMedium, ""},
+		{"", "https://img/xl.png"},
+	}
+	for _, tt := range tests {
+		if got := info.ImageURL(tt.size); got != tt.want {
+			t.Errorf("ImageURL(%q) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+
+	if got := (&AlbumInfo{}).ImageURL(""); got != "" {
+		t.Errorf("expected empty URL without images, got %q", got)
+	}
+}
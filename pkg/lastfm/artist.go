@@ -0,0 +1,378 @@
+package lastfm
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// ArtistService provides artist metadata operations for the Last.fm API.
+type ArtistService struct {
+	client *Client
+}
+
+// GetInfo returns metadata for an artist, including similar artists and
+// top tags.
+//
+// If opts.Username is set, the response includes that user's play count
+// for the artist.
+//
+// Does not require authentication.
+//
+// Example:
+//
+//	info, err := client.Artist().GetInfo(ctx, "Cher", lastfm.ArtistInfoOptions{})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("%s has %d listeners\n", info.Name, info.Listeners)
+func (s *ArtistService) GetInfo(ctx context.Context, artist string, opts ArtistInfoOptions) (*ArtistInfo, error) {
+	params := map[string]string{}
+	if opts.MBID != "" {
+		params["mbid"] = opts.MBID
+	} else {
+		var err error
+		params, err = artistParams(artist)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opts.Username != "" {
+		params["username"] = opts.Username
+	}
+	if opts.Autocorrect {
+		params["autocorrect"] = "1"
+	}
+
+	resp, err := s.client.call(ctx, "artist.getInfo", params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := unmarshalArtistInfo(resp)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: failed to parse artist info response: %w", err)
+	}
+
+	return info, nil
+}
+
+// GetSimilar returns artists similar to the given artist, most similar
+// first. A limit <= 0 uses the Last.fm default.
+//
+// Does not require authentication.
+func (s *ArtistService) GetSimilar(ctx context.Context, artist string, limit int) ([]SimilarArtist, error) {
+	params, err := artistParams(artist)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 {
+		params["limit"] = fmt.Sprintf("%d", limit)
+	}
+
+	resp, err := s.client.call(ctx, "artist.getSimilar", params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	similar, err := unmarshalSimilarArtists(resp)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: failed to parse similar artists response: %w", err)
+	}
+
+	return similar, nil
+}
+
+// GetTopTracks returns an artist's most popular tracks.
+//
+// Does not require authentication.
+func (s *ArtistService) GetTopTracks(ctx context.Context, artist string, opts ListOptions) (*ArtistTopTracksResponse, error) {
+	params, err := artistParams(artist)
+	if err != nil {
+		return nil, err
+	}
+	addListParams(params, opts)
+
+	resp, err := s.client.call(ctx, "artist.getTopTracks", params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	top, err := unmarshalArtistTopTracks(resp)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: failed to parse artist top tracks response: %w", err)
+	}
+
+	return top, nil
+}
+
+// TopTracksPager returns a Pager over an artist's most popular tracks.
+func (s *ArtistService) TopTracksPager(artist string, opts ListOptions) *Pager[TopTrack] {
+	return NewPager(func(ctx context.Context, page int) ([]TopTrack, Pagination, error) {
+		pageOpts := opts
+		pageOpts.Page = page
+		resp, err := s.GetTopTracks(ctx, artist, pageOpts)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		return resp.Tracks, resp.Pagination, nil
+	}, opts.Page)
+}
+
+// GetTopTags returns the most applied tags for an artist, with their
+// weights in Tag.Count.
+//
+// Does not require authentication.
+func (s *ArtistService) GetTopTags(ctx context.Context, artist string) ([]Tag, error) {
+	params, err := artistParams(artist)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.call(ctx, "artist.getTopTags", params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := unmarshalTopTags(resp)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: failed to parse top tags response: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetCorrection checks whether Last.fm has a canonical spelling for an
+// artist name.
+//
+// If there is no correction, the returned ArtistCorrection holds the
+// input name with Corrected false.
+//
+// Does not require authentication.
+//
+// Example:
+//
+//	c, err := client.Artist().GetCorrection(ctx, "guns and roses")
+//	if err == nil && c.Corrected {
+//	    fmt.Println("Did you mean", c.Artist)
+//	}
+func (s *ArtistService) GetCorrection(ctx context.Context, artist string) (*ArtistCorrection, error) {
+	params, err := artistParams(artist)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.call(ctx, "artist.getCorrection", params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	correction, err := unmarshalArtistCorrection(resp)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: failed to parse correction response: %w", err)
+	}
+	if correction == nil || correction.Artist == "" {
+		return &ArtistCorrection{Artist: artist}, nil
+	}
+	correction.Corrected = correction.Artist != artist
+
+	return correction, nil
+}
+
+// artistParams builds the artist parameter shared by artist methods.
+func artistParams(artist string) (map[string]string, error) {
+	if artist == "" {
+		return nil, fmt.Errorf("lastfm: artist is required")
+	}
+	return map[string]string{
+		"artist": artist,
+	}, nil
+}
+
+// similarArtistXML represents an <artist> element in similar artist lists.
+type similarArtistXML struct {
+	Name   string     `xml:"name"`
+	MBID   string     `xml:"mbid"`
+	URL    string     `xml:"url"`
+	Match  float64    `xml:"match"`
+	Images []imageXML `xml:"image"`
+}
+
+// toSimilarArtists converts parsed similar artist elements.
+func toSimilarArtists(artists []similarArtistXML) []SimilarArtist {
+	result := make([]SimilarArtist, len(artists))
+	for i, a := range artists {
+		result[i] = SimilarArtist{
+			Name:   a.Name,
+			MBID:   a.MBID,
+			URL:    a.URL,
+			Match:  a.Match,
+			Images: toImages(a.Images),
+		}
+	}
+	return result
+}
+
+// artistInfoResponse represents the XML response from artist.getInfo.
+type artistInfoResponse struct {
+	Artist struct {
+		Name   string     `xml:"name"`
+		MBID   string     `xml:"mbid"`
+		URL    string     `xml:"url"`
+		Images []imageXML `xml:"image"`
+		OnTour bool       `xml:"ontour"`
+		Stats  struct {
+			Listeners     int `xml:"listeners"`
+			PlayCount     int `xml:"playcount"`
+			UserPlayCount int `xml:"userplaycount"`
+		} `xml:"stats"`
+		Similar []similarArtistXML `xml:"similar>artist"`
+		Tags    []tagXML           `xml:"tags>tag"`
+		Bio     struct {
+			Summary string `xml:"summary"`
+		} `xml:"bio"`
+	} `xml:"artist"`
+}
+
+// unmarshalArtistInfo parses the XML response from artist.getInfo.
+func unmarshalArtistInfo(data []byte) (*ArtistInfo, error) {
+	// Wrap inner XML in root element for proper unmarshaling
+	wrapped := []byte("<root>" + string(data) + "</root>")
+
+	var resp artistInfoResponse
+	if err := xml.Unmarshal(wrapped, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal artist info response: %w", err)
+	}
+
+	a := resp.Artist
+	return &ArtistInfo{
+		Name:          a.Name,
+		MBID:          a.MBID,
+		URL:           a.URL,
+		Images:        toImages(a.Images),
+		OnTour:        a.OnTour,
+		Listeners:     a.Stats.Listeners,
+		PlayCount:     a.Stats.PlayCount,
+		UserPlayCount: a.Stats.UserPlayCount,
+		Similar:       toSimilarArtists(a.Similar),
+		Tags:          toTags(a.Tags),
+		Summary:       strings.TrimSpace(a.Bio.Summary),
+	}, nil
+}
+
+// similarArtistsResponse represents the XML response from artist.getSimilar.
+type similarArtistsResponse struct {
+	Artists []similarArtistXML `xml:"similarartists>artist"`
+}
+
+// unmarshalSimilarArtists parses the XML response from artist.getSimilar.
+func unmarshalSimilarArtists(data []byte) ([]SimilarArtist, error) {
+	// Wrap inner XML in root element for proper unmarshaling
+	wrapped := []byte("<root>" + string(data) + "</root>")
+
+	var resp similarArtistsResponse
+	if err := xml.Unmarshal(wrapped, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal similar artists response: %w", err)
+	}
+
+	return toSimilarArtists(resp.Artists), nil
+}
+
+// artistTopTracksResponse represents the XML response from artist.getTopTracks.
+type artistTopTracksResponse struct {
+	TopTracks struct {
+		paginationXML
+		Artist string `xml:"artist,attr"`
+		Tracks []struct {
+			Rank      int          `xml:"rank,attr"`
+			Name      string       `xml:"name"`
+			MBID      string       `xml:"mbid"`
+			URL       string       `xml:"url"`
+			PlayCount int          `xml:"playcount"`
+			Listeners int          `xml:"listeners"`
+			Artist    artistRefXML `xml:"artist"`
+			Images    []imageXML   `xml:"image"`
+		} `xml:"track"`
+	} `xml:"toptracks"`
+}
+
+// unmarshalArtistTopTracks parses the XML response from artist.getTopTracks.
+func unmarshalArtistTopTracks(data []byte) (*ArtistTopTracksResponse, error) {
+	// Wrap inner XML in root element for proper unmarshaling
+	wrapped := []byte("<root>" + string(data) + "</root>")
+
+	var resp artistTopTracksResponse
+	if err := xml.Unmarshal(wrapped, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal artist top tracks response: %w", err)
+	}
+
+	result := &ArtistTopTracksResponse{
+		Artist:     resp.TopTracks.Artist,
+		Pagination: resp.TopTracks.toPagination(),
+		Tracks:     make([]TopTrack, len(resp.TopTracks.Tracks)),
+	}
+	for i, t := range resp.TopTracks.Tracks {
+		result.Tracks[i] = TopTrack{
+			Rank:      t.Rank,
+			Name:      t.Name,
+			MBID:      t.MBID,
+			Artist:    t.Artist.name(),
+			URL:       t.URL,
+			PlayCount: t.PlayCount,
+			Listeners: t.Listeners,
+			Images:    toImages(t.Images),
+		}
+	}
+
+	return result, nil
+}
+
+// topTagsResponse represents the XML response from artist.getTopTags.
+type topTagsResponse struct {
+	Tags []tagXML `xml:"toptags>tag"`
+}
+
+// unmarshalTopTags parses the XML response from artist.getTopTags.
+func unmarshalTopTags(data []byte) ([]Tag, error) {
+	// Wrap inner XML in root element for proper unmarshaling
+	wrapped := []byte("<root>" + string(data) + "</root>")
+
+	var resp topTagsResponse
+	if err := xml.Unmarshal(wrapped, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal top tags response: %w", err)
+	}
+
+	return toTags(resp.Tags), nil
+}
+
+// artistCorrectionResponse represents the XML response from artist.getCorrection.
+type artistCorrectionResponse struct {
+	Corrections []struct {
+		Artist struct {
+			Name string `xml:"name"`
+			MBID string `xml:"mbid"`
+		} `xml:"artist"`
+	} `xml:"corrections>correction"`
+}
+
+// unmarshalArtistCorrection parses the XML response from artist.getCorrection.
+// Returns nil if Last.fm returned no correction.
+func unmarshalArtistCorrection(data []byte) (*ArtistCorrection, error) {
+	// Wrap inner XML in root element for proper unmarshaling
+	wrapped := []byte("<root>" + string(data) + "</root>")
+
+	var resp artistCorrectionResponse
+	if err := xml.Unmarshal(wrapped, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal correction response: %w", err)
+	}
+	if len(resp.Corrections) == 0 {
+		return nil, nil
+	}
+
+	c := resp.Corrections[0]
+	return &ArtistCorrection{
+		Artist: c.Artist.Name,
+		MBID:   c.Artist.MBID,
+	}, nil
+}
@@ -0,0 +1,244 @@
+package lastfm
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestArtistService_GetInfo(t *testing.T) {
+	response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<artist>
+		<name>Cher</name>
+		<mbid>artist-mbid</mbid>
+		<url>https://www.last.fm/music/Cher</url>
+		<image size="small">https://img/s.png</image>
+		<image size="mega"></image>
+		<streamable>0</streamable>
+		<ontour>1</ontour>
+		<stats>
+			<listeners>1234</listeners>
+			<playcount>56789</playcount>
+			<userplaycount>42</userplaycount>
+		</stats>
+		<similar>
+			<artist><name>Madonna</name><url>https://www.last.fm/music/Madonna</url></artist>
+			<artist><name>Kylie Minogue</name><url>https://www.last.fm/music/Kylie+Minogue</url></artist>
+		</similar>
+		<tags>
+			<tag><name>pop</name><url>https://www.last.fm/tag/pop</url></tag>
+		</tags>
+		<bio>
+			<summary> Cher is a singer. </summary>
+		</bio>
+	</artist>
+</lfm>`
+
+	client := newMethodServer(t, "artist.getInfo", response, func(r *http.Request) {
+		if got := r.FormValue("artist"); got != "Cher" {
+			t.Errorf("expected artist Cher, got %s", got)
+		}
+		if got := r.FormValue("username"); got != "rj" {
+			t.Errorf("expected username rj, got %s", got)
+		}
+	})
+
+	info, err := client.Artist().GetInfo(context.Background(), "Cher", ArtistInfoOptions{Username: "rj"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Name != "Cher" || info.MBID != "artist-mbid" || !info.OnTour {
+		t.Errorf("unexpected artist: %+v", info)
+	}
+	if info.Listeners != 1234 || info.PlayCount != 56789 || info.UserPlayCount != 42 {
+		t.Errorf("unexpected stats: %d listeners, %d plays, %d user plays", info.Listeners, info.PlayCount, info.UserPlayCount)
+	}
+	if len(info.Images) != 1 {
+		t.Errorf("expected empty image URLs to be dropped, got %+v", info.Images)
+	}
+	if len(info.Similar) != 2 || info.Similar[1].Name != "Kylie Minogue" {
+		t.Errorf("unexpected similar artists: %+v", info.Similar)
+	}
+	if len(info.Tags) != 1 || info.Tags[0].Name != "pop" {
+		t.Errorf("unexpected tags: %+v", info.Tags)
+	}
+	if info.Summary != "Cher is a singer." {
+		t.Errorf("expected trimmed summary, got %q", info.Summary)
+	}
+}
+
+func TestArtistService_GetSimilar(t *testing.T) {
+	response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<similarartists artist="Cher">
+		<artist>
+			<name>Madonna</name>
+			<mbid>madonna-mbid</mbid>
+			<match>1</match>
+			<url>https://www.last.fm/music/Madonna</url>
+			<image size="large">https://img/l.png</image>
+		</artist>
+		<artist>
+			<name>Kylie Minogue</name>
+			<match>0.85</match>
+		</artist>
+	</similarartists>
+</lfm>`
+
+	client := newMethodServer(t, "artist.getSimilar", response, func(r *http.Request) {
+		if got := r.FormValue("limit"); got != "2" {
+			t.Errorf("expected limit 2, got %s", got)
+		}
+	})
+
+	similar, err := client.Artist().GetSimilar(context.Background(), "Cher", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(similar) != 2 {
+		t.Fatalf("expected 2 similar artists, got %d", len(similar))
+	}
+	if similar[0].Name != "Madonna" || similar[0].Match != 1 || len(similar[0].Images) != 1 {
+		t.Errorf("unexpected first artist: %+v", similar[0])
+	}
+	if similar[1].Match != 0.85 {
+		t.Errorf("expected match 0.85, got %v", similar[1].Match)
+	}
+}
+
+func TestArtistService_GetTopTracks(t *testing.T) {
+	response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<toptracks artist="Cher" page="1" perPage="2" totalPages="10" total="20">
+		<track rank="1">
+			<name>Believe</name>
+			<playcount>500</playcount>
+			<listeners>100</listeners>
+			<mbid>track-mbid</mbid>
+			<url>https://www.last.fm/music/Cher/_/Believe</url>
+			<artist><name>Cher</name><mbid>artist-mbid</mbid></artist>
+		</track>
+		<track rank="2">
+			<name>Strong Enough</name>
+			<playcount>300</playcount>
+			<listeners>80</listeners>
+			<artist><name>Cher</name></artist>
+		</track>
+	</toptracks>
+</lfm>`
+
+	client := newMethodServer(t, "artist.getTopTracks", response, func(r *http.Request) {
+		if got := r.FormValue("limit"); got != "2" {
+			t.Errorf("expected limit 2, got %s", got)
+		}
+	})
+
+	top, err := client.Artist().GetTopTracks(context.Background(), "Cher", ListOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if top.Artist != "Cher" || top.TotalPages != 10 || top.Total != 20 {
+		t.Errorf("unexpected response: %+v", top)
+	}
+	if len(top.Tracks) != 2 {
+		t.Fatalf("expected 2 tracks, got %d", len(top.Tracks))
+	}
+	first := top.Tracks[0]
+	if first.Rank != 1 || first.Name != "Believe" || first.Artist != "Cher" || first.PlayCount != 500 || first.Listeners != 100 {
+		t.Errorf("unexpected first track: %+v", first)
+	}
+}
+
+func TestArtistService_GetTopTags(t *testing.T) {
+	response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<toptags artist="Cher">
+		<tag><count>100</count><name>pop</name><url>https://www.last.fm/tag/pop</url></tag>
+		<tag><count>40</count><name>dance</name><url>https://www.last.fm/tag/dance</url></tag>
+	</toptags>
+</lfm>`
+
+	client := newMethodServer(t, "artist.getTopTags", response, nil)
+
+	tags, err := client.Artist().GetTopTags(context.Background(), "Cher")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tags) != 2 || tags[0].Name != "pop" || tags[0].Count != 100 || tags[1].Count != 40 {
+		t.Errorf("unexpected tags: %+v", tags)
+	}
+}
+
+func TestArtistService_GetCorrection(t *testing.T) {
+	t.Run("corrected", func(t *testing.T) {
+		response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<corrections>
+		<correction index="0">
+			<artist>
+				<name>Guns N' Roses</name>
+				<mbid>artist-mbid</mbid>
+				<url>https://www.last.fm/music/Guns+N%27+Roses</url>
+			</artist>
+		</correction>
+	</corrections>
+</lfm>`
+		client := newMethodServer(t, "artist.getCorrection", response, nil)
+
+		c, err := client.Artist().GetCorrection(context.Background(), "guns and roses")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.Artist != "Guns N' Roses" || c.MBID != "artist-mbid" || !c.Corrected {
+			t.Errorf("unexpected correction: %+v", c)
+		}
+	})
+
+	t.Run("already canonical", func(t *testing.T) {
+		response := `<lfm status="ok"><corrections><correction index="0"><artist><name>Cher</name></artist></correction></corrections></lfm>`
+		client := newMethodServer(t, "artist.getCorrection", response, nil)
+
+		c, err := client.Artist().GetCorrection(context.Background(), "Cher")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.Artist != "Cher" || c.Corrected {
+			t.Errorf("expected no correction, got %+v", c)
+		}
+	})
+
+	t.Run("no correction", func(t *testing.T) {
+		client := newMethodServer(t, "artist.getCorrection", `<lfm status="ok"><corrections/></lfm>`, nil)
+
+		c, err := client.Artist().GetCorrection(context.Background(), "Cher")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.Artist != "Cher" || c.Corrected {
+			t.Errorf("expected input to be returned unchanged, got %+v", c)
+		}
+	})
+}
+
+func TestArtistService_RequiresArtist(t *testing.T) {
+	client, err := NewClient(Config{APIKey: "key", APISecret: "secret"})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := client.Artist().GetInfo(ctx, "", ArtistInfoOptions{}); err == nil {
+		t.Error("expected error for empty artist")
+	}
+	if _, err := client.Artist().GetSimilar(ctx, "", 0); err == nil {
+		t.Error("expected error for empty artist")
+	}
+	if _, err := client.Artist().GetCorrection(ctx, ""); err == nil {
+		t.Error("expected error for empty artist")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/xml"
 	"fmt"
+	"net/url"
 )
 
 // AuthService provides authentication operations for the Last.fm API.
@@ -41,14 +42,15 @@ func (a *AuthService) GetToken(ctx context.Context) (*Token, error) {
 //
 // After calling GetToken, direct the user to this URL to authorize
 // the application. Once authorized, call GetSession to exchange the
-// token for a session key.
+// token for a session key. The page is Config.AuthURL, which defaults
+// to Last.fm's.
 //
 // Example:
 //
 //	authURL := client.Auth().GetAuthURL(token.Token)
 //	fmt.Println("Please visit:", authURL)
 func (a *AuthService) GetAuthURL(token string) string {
-	return "https://www.last.fm/api/auth/?api_key=" + a.client.apiKey + "&token=" + token
+	return a.client.authURL + "?api_key=" + url.QueryEscape(a.client.apiKey) + "&token=" + url.QueryEscape(token)
 }
 
 // GetSession exchanges an authorized token for a session key.
@@ -51,6 +51,12 @@ func TestAuthService_GetToken(t *testing.T) {
 			wantErr:     true,
 			errContains: "error 11",
 		},
+		{
+			name:        "http error without body",
+			statusCode:  http.StatusNotFound,
+			wantErr:     true,
+			errContains: "unexpected status code: 404",
+		},
 	}
 
 	for _, tt := range tests {
@@ -141,6 +147,27 @@ func TestAuthService_GetAuthURL(t *testing.T) {
 	}
 }
 
+// TestAuthService_GetAuthURL_CustomServer tests GetAuthURL against an
+// Audioscrobbler-compatible server such as Libre.fm.
+func TestAuthService_GetAuthURL_CustomServer(t *testing.T) {
+	client, err := NewClient(Config{
+		APIKey:    "my-api-key",
+		APISecret: "my-secret",
+		BaseURL:   "https://libre.fm/2.0/",
+		AuthURL:   "https://libre.fm/api/auth/",
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	url := client.Auth().GetAuthURL("test-token-123")
+
+	expectedURL := "https://libre.fm/api/auth/?api_key=my-api-key&token=test-token-123"
+	if url != expectedURL {
+		t.Errorf("expected URL %q, got %q", expectedURL, url)
+	}
+}
+
 // TestAuthService_GetSession tests the GetSession method.
 func TestAuthService_GetSession(t *testing.T) {
 	tests := []struct {
@@ -32,7 +32,8 @@ type Config struct {
 	APISecret  string       // Required: Last.fm API secret
 	SessionKey string       // Optional: Session key for authenticated requests
 	HTTPClient *http.Client // Optional: HTTP client (defaults to http.DefaultClient)
-	BaseURL    string       // Optional: Base URL for API (defaults to Last.fm API, used for testing)
+	BaseURL    string       // Optional: Base URL for API (defaults to Last.fm API, or another Audioscrobbler 2.0 server)
+	AuthURL    string       // Optional: Page where users authorize tokens (defaults to Last.fm's)
 	Logger     Logger       // Optional: Logger interface for debug logging
 }
 
@@ -49,15 +50,23 @@ type Client struct {
 	sessionKey string
 	httpClient *http.Client
 	baseURL    string
+	authURL    string
 	logger     Logger
 
 	auth     *AuthService
 	scrobble *ScrobbleService
+	user     *UserService
+	track    *TrackService
+	artist   *ArtistService
+	album    *AlbumService
 }
 
 const (
 	// DefaultBaseURL is the default Last.fm API endpoint.
 	DefaultBaseURL = "https://ws.audioscrobbler.com/2.0/"
+
+	// DefaultAuthURL is the default page where users authorize tokens.
+	DefaultAuthURL = "https://www.last.fm/api/auth/"
 )
 
 // NewClient creates a new Last.fm API client.
@@ -81,17 +90,27 @@ func NewClient(cfg Config) (*Client, error) {
 		baseURL = DefaultBaseURL
 	}
 
+	authURL := cfg.AuthURL
+	if authURL == "" {
+		authURL = DefaultAuthURL
+	}
+
 	c := &Client{
 		apiKey:     cfg.APIKey,
 		apiSecret:  cfg.APISecret,
 		sessionKey: cfg.SessionKey,
 		httpClient: httpClient,
 		baseURL:    baseURL,
+		authURL:    authURL,
 		logger:     cfg.Logger,
 	}
 
 	c.auth = &AuthService{client: c}
 	c.scrobble = &ScrobbleService{client: c}
+	c.user = &UserService{client: c}
+	c.track = &TrackService{client: c}
+	c.artist = &ArtistService{client: c}
+	c.album = &AlbumService{client: c}
 
 	return c, nil
 }
@@ -106,6 +125,26 @@ func (c *Client) Scrobble() *ScrobbleService {
 	return c.scrobble
 }
 
+// User returns the user service.
+func (c *Client) User() *UserService {
+	return c.user
+}
+
+// Track returns the track service.
+func (c *Client) Track() *TrackService {
+	return c.track
+}
+
+// Artist returns the artist service.
+func (c *Client) Artist() *ArtistService {
+	return c.artist
+}
+
+// Album returns the album service.
+func (c *Client) Album() *AlbumService {
+	return c.album
+}
+
 // SetSessionKey sets the session key for authenticated requests.
 func (c *Client) SetSessionKey(key string) {
 	c.sessionKey = key
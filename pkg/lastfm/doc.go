@@ -78,6 +78,62 @@
 //	}
 //	resp, err := client.Scrobble().ScrobbleBatch(ctx, scrobbles)
 //
+// # User Data
+//
+// Read-only user endpoints do not require a session key:
+//
+//	// Recent scrobbles, including the currently playing track
+//	recent, err := client.User().GetRecentTracks(ctx, "rj", lastfm.RecentTracksOptions{})
+//
+//	// Profile information
+//	info, err := client.User().GetInfo(ctx, "rj")
+//
+//	// Top charts for a period
+//	top, err := client.User().GetTopArtists(ctx, "rj", lastfm.TopOptions{
+//	    Period: lastfm.Period1Month,
+//	})
+//
+// # Track Operations
+//
+// Love, tag and look up tracks:
+//
+//	// Love a track (requires a session key)
+//	err := client.Track().Love(ctx, "The Beatles", "Yesterday")
+//
+//	// Track metadata including the user's play count and loved status
+//	info, err := client.Track().GetInfo(ctx, "The Beatles", "Yesterday", lastfm.TrackInfoOptions{
+//	    Username: "rj",
+//	})
+//
+//	// Canonical artist/track spelling
+//	c, err := client.Track().GetCorrection(ctx, "beatles", "yesterday")
+//
+// # Artists and Albums
+//
+// Look up artist and album metadata without authentication:
+//
+//	artist, err := client.Artist().GetInfo(ctx, "Cher", lastfm.ArtistInfoOptions{})
+//	similar, err := client.Artist().GetSimilar(ctx, "Cher", 10)
+//	tags, err := client.Artist().GetTopTags(ctx, "Cher")
+//
+//	// Album cover art by size
+//	album, err := client.Album().GetInfo(ctx, "Cher", "Believe", lastfm.AlbumInfoOptions{})
+//	cover := album.ImageURL(lastfm.ImageSizeExtraLarge)
+//
+// # Pagination
+//
+// List responses embed Pagination. A Pager walks every page lazily and
+// stops early when the caller breaks out of the loop:
+//
+//	pager := client.User().RecentTracksPager("rj", lastfm.RecentTracksOptions{}).
+//	    WithMaxItems(1000)
+//	for track, err := range pager.All(ctx) {
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    fmt.Println(track.Artist, "-", track.Track)
+//	}
+//
 // # Error Handling
 //
 // The package provides structured errors with retry information:
@@ -119,6 +175,13 @@
 // Currently implemented:
 //   - Authentication (auth.getToken, auth.getSession)
 //   - Scrobbling (track.scrobble, track.updateNowPlaying)
+//   - User (user.getRecentTracks, user.getInfo, user.getLovedTracks,
+//     user.getTopArtists, user.getTopAlbums, user.getTopTracks)
+//   - Track (track.love, track.unlove, track.getInfo, track.addTags,
+//     track.removeTag, track.getTags, track.getCorrection)
+//   - Artist (artist.getInfo, artist.getSimilar, artist.getTopTracks,
+//     artist.getTopTags, artist.getCorrection)
+//   - Album (album.getInfo)
 //
 // # Last.fm API Documentation
 //
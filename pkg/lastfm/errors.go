@@ -2,6 +2,7 @@ package lastfm
 
 import (
 	"fmt"
+	"net/http"
 )
 
 // Error represents a Last.fm API error.
@@ -50,6 +51,20 @@ func (e *Error) Temporary() bool {
 	}
 }
 
+// StatusError is returned when Last.fm answers with an HTTP status other
+// than 200 OK instead of an API error.
+type StatusError struct {
+	StatusCode int // HTTP status code
+}
+
+// Error returns the error message.
+func (e *StatusError) Error() string {
+	if e.StatusCode >= 500 {
+		return fmt.Sprintf("server error: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
 // Common Last.fm error codes.
 const (
 	ErrCodeInvalidService       = 2
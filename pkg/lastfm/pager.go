@@ -0,0 +1,164 @@
+package lastfm
+
+import (
+	"context"
+	"iter"
+)
+
+// Pagination holds the paging attributes Last.fm returns on list responses.
+type Pagination struct {
+	Page       int // Current page number, starting at 1
+	PerPage    int // Number of results per page
+	TotalPages int // Total number of pages available
+	Total      int // Total number of results across all pages
+}
+
+// paginationXML represents the page/perPage/totalPages/total attributes
+// on list response elements. It is embedded in response structs.
+type paginationXML struct {
+	Page       int `xml:"page,attr"`
+	PerPage    int `xml:"perPage,attr"`
+	TotalPages int `xml:"totalPages,attr"`
+	Total      int `xml:"total,attr"`
+}
+
+// toPagination converts the parsed attributes.
+func (p paginationXML) toPagination() Pagination {
+	return Pagination(p)
+}
+
+// PageFetcher fetches a single page of results.
+//
+// Implementations should return the items on the requested page along
+// with the pagination attributes from the response.
+type PageFetcher[T any] func(ctx context.Context, page int) ([]T, Pagination, error)
+
+// Pager lazily walks a list endpoint page by page.
+//
+// Pages are only requested when the caller asks for more items, so
+// breaking out of a range over All stops further API calls.
+//
+// A Pager is not safe for concurrent use.
+type Pager[T any] struct {
+	fetch    PageFetcher[T]
+	page     int
+	maxItems int
+	yielded  int
+	done     bool
+	last     Pagination
+}
+
+// NewPager creates a Pager starting at the given page (1 if page < 1).
+//
+// Example:
+//
+//	pager := lastfm.NewPager(func(ctx context.Context, page int) ([]lastfm.TopArtist, lastfm.Pagination, error) {
+//	    resp, err := client.User().GetTopArtists(ctx, "rj", lastfm.TopOptions{
+//	        ListOptions: lastfm.ListOptions{Page: page},
+//	    })
+//	    if err != nil {
+//	        return nil, lastfm.Pagination{}, err
+//	    }
+//	    return resp.Artists, resp.Pagination, nil
+//	}, 1)
+func NewPager[T any](fetch PageFetcher[T], page int) *Pager[T] {
+	if page < 1 {
+		page = 1
+	}
+	return &Pager[T]{
+		fetch: fetch,
+		page:  page,
+	}
+}
+
+// WithMaxItems caps the total number of items the pager returns.
+// A value <= 0 means no cap. Returns the pager for chaining.
+func (p *Pager[T]) WithMaxItems(n int) *Pager[T] {
+	p.maxItems = n
+	return p
+}
+
+// Done reports whether the pager has no more pages to fetch.
+func (p *Pager[T]) Done() bool {
+	return p.done
+}
+
+// Pagination returns the pagination attributes of the last fetched page.
+func (p *Pager[T]) Pagination() Pagination {
+	return p.last
+}
+
+// Next fetches the next page of results.
+//
+// Returns a nil slice and nil error once all pages have been read or the
+// max-items cap has been reached. Context cancellation is checked before
+// each request.
+func (p *Pager[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	items, pagination, err := p.fetch(ctx, p.page)
+	if err != nil {
+		return nil, err
+	}
+	p.last = pagination
+
+	// Stop when Last.fm reports this was the last page, or when the page
+	// came back empty (some endpoints omit the totalPages attribute).
+	if len(items) == 0 || (pagination.TotalPages > 0 && p.page >= pagination.TotalPages) {
+		p.done = true
+	}
+	p.page++
+
+	if p.maxItems > 0 {
+		remaining := p.maxItems - p.yielded
+		if len(items) >= remaining {
+			items = items[:remaining]
+			p.done = true
+		}
+	}
+	p.yielded += len(items)
+
+	if len(items) == 0 {
+		return nil, nil
+	}
+	return items, nil
+}
+
+// All returns an iterator over every remaining item.
+//
+// Iteration stops at the first error, which is yielded with the zero
+// value of T.
+//
+// Example:
+//
+//	for track, err := range pager.All(ctx) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(track.Artist, "-", track.Track)
+//	}
+func (p *Pager[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			items, err := p.Next(ctx)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if items == nil {
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package lastfm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakePages returns a PageFetcher serving totalItems ints in pages of
+// perPage, and a counter of how many fetches were made.
+func fakePages(totalItems, perPage int) (PageFetcher[int], *int) {
+	calls := 0
+	totalPages := (totalItems + perPage - 1) / perPage
+	return func(ctx context.Context, page int) ([]int, Pagination, error) {
+		calls++
+		var items []int
+		for i := (page - 1) * perPage; i < page*perPage && i < totalItems; i++ {
+			items = append(items, i)
+		}
+		return items, Pagination{Page: page, PerPage: perPage, TotalPages: totalPages, Total: totalItems}, nil
+	}, &calls
+}
+
+func TestPager_All(t *testing.T) {
+	fetch, calls := fakePages(25, 10)
+	pager := NewPager(fetch, 1)
+
+	var got []int
+	for item, err := range pager.All(context.Background()) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, item)
+	}
+
+	if len(got) != 25 {
+		t.Fatalf("expected 25 items, got %d", len(got))
+	}
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("expected item %d at index %d, got %d", i, i, v)
+		}
+	}
+	if *calls != 3 {
+		t.Errorf("expected 3 page fetches, got %d", *calls)
+	}
+	if !pager.Done() {
+		t.Error("expected pager to be done")
+	}
+	if p := pager.Pagination(); p.TotalPages != 3 || p.Total != 25 {
+		t.Errorf("unexpected pagination: %+v", p)
+	}
+}
+
+func TestPager_MaxItems(t *testing.T) {
+	fetch, calls := fakePages(100, 10)
+	pager := NewPager(fetch, 1).WithMaxItems(15)
+
+	count := 0
+	for _, err := range pager.All(context.Background()) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		count++
+	}
+
+	if count != 15 {
+		t.Errorf("expected 15 items, got %d", count)
+	}
+	if *calls != 2 {
+		t.Errorf("expected 2 page fetches, got %d", *calls)
+	}
+}
+
+func TestPager_Lazy(t *testing.T) {
+	fetch, calls := fakePages(100, 10)
+	pager := NewPager(fetch, 1)
+
+	for item := range pager.All(context.Background()) {
+		if item == 4 {
+			break
+		}
+	}
+
+	if *calls != 1 {
+		t.Errorf("expected breaking early to stop after 1 fetch, got %d", *calls)
+	}
+}
+
+func TestPager_StartPage(t *testing.T) {
+	fetch, _ := fakePages(30, 10)
+	pager := NewPager(fetch, 3)
+
+	items, err := pager.Next(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 10 || items[0] != 20 {
+		t.Errorf("expected page 3 items starting at 20, got %v", items)
+	}
+	if !pager.Done() {
+		t.Error("expected pager to be done after last page")
+	}
+
+	items, err = pager.Next(context.Background())
+	if err != nil || items != nil {
+		t.Errorf("expected nil, nil after done, got %v, %v", items, err)
+	}
+}
+
+func TestPager_EmptyPageWithoutTotals(t *testing.T) {
+	calls := 0
+	pager := NewPager(func(ctx context.Context, page int) ([]string, Pagination, error) {
+		calls++
+		if page > 2 {
+			return nil, Pagination{}, nil
+		}
+		return []string{fmt.Sprintf("page-%d", page)}, Pagination{}, nil
+	}, 1)
+
+	count := 0
+	for _, err := range pager.All(context.Background()) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		count++
+	}
+
+	if count != 2 {
+		t.Errorf("expected 2 items, got %d", count)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 fetches, got %d", calls)
+	}
+}
+
+func TestPager_Error(t *testing.T) {
+	wantErr := errors.New("boom")
+	pager := NewPager(func(ctx context.Context, page int) ([]int, Pagination, error) {
+		if page == 2 {
+			return nil, Pagination{}, wantErr
+		}
+		return []int{1, 2}, Pagination{TotalPages: 5}, nil
+	}, 1)
+
+	var gotErr error
+	count := 0
+	for _, err := range pager.All(context.Background()) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		count++
+	}
+
+	if !errors.Is(gotErr, wantErr) {
+		t.Errorf("expected %v, got %v", wantErr, gotErr)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 items before error, got %d", count)
+	}
+}
+
+func TestPager_ContextCancelled(t *testing.T) {
+	fetch, calls := fakePages(100, 10)
+	pager := NewPager(fetch, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := pager.Next(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if *calls != 0 {
+		t.Errorf("expected no fetches after cancellation, got %d", *calls)
+	}
+}
+
+func TestUserService_RecentTracksPager(t *testing.T) {
+	pages := map[string]string{
+		"1": `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<recenttracks user="rj" page="1" perPage="2" totalPages="2" total="3">
+		<track nowplaying="true"><artist>Now</artist><name>Playing</name></track>
+		<track><artist>A</artist><name>One</name><date uts="300">x</date></track>
+		<track><artist>A</artist><name>Two</name><date uts="200">x</date></track>
+	</recenttracks>
+</lfm>`,
+		"2": `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<recenttracks user="rj" page="2" perPage="2" totalPages="2" total="3">
+		<track nowplaying="true"><artist>Now</artist><name>Playing</name></track>
+		<track><artist>A</artist><name>Three</name><date uts="100">x</date></track>
+	</recenttracks>
+</lfm>`,
+	}
+
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		page := r.FormValue("page")
+		requested = append(requested, page)
+		if _, err := w.Write([]byte(pages[page])); err != nil {
+			t.Fatalf("failed to write response body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{
+		APIKey:    "test-api-key",
+		APISecret: "test-secret",
+		BaseURL:   server.URL,
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	var names []string
+	for track, err := range client.User().RecentTracksPager("rj", RecentTracksOptions{}).All(context.Background()) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		names = append(names, track.Track)
+	}
+
+	if got := strings.Join(names, ","); got != "One,Two,Three" {
+		t.Errorf("expected One,Two,Three (now playing skipped), got %s", got)
+	}
+	if got := strings.Join(requested, ","); got != "1,2" {
+		t.Errorf("expected pages 1,2 to be requested, got %s", got)
+	}
+}
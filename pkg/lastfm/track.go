@@ -0,0 +1,348 @@
+package lastfm
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// TrackService provides track metadata, love and tagging operations for
+// the Last.fm API.
+type TrackService struct {
+	client *Client
+}
+
+const (
+	// MaxTagsPerRequest is the maximum number of tags accepted by track.addTags.
+	MaxTagsPerRequest = 10
+)
+
+// Love marks a track as loved by the authenticated user.
+//
+// Requires authentication (session key must be set via SetSessionKey).
+//
+// Example:
+//
+//	err := client.Track().Love(ctx, "The Beatles", "Yesterday")
+//	if err != nil {
+//	    log.Printf("Failed to love track: %v", err)
+//	}
+func (s *TrackService) Love(ctx context.Context, artist, track string) error {
+	params, err := trackParams(artist, track)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.call(ctx, "track.love", params, true)
+	return err
+}
+
+// Unlove removes a track from the authenticated user's loved tracks.
+//
+// Requires authentication (session key must be set via SetSessionKey).
+func (s *TrackService) Unlove(ctx context.Context, artist, track string) error {
+	params, err := trackParams(artist, track)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.call(ctx, "track.unlove", params, true)
+	return err
+}
+
+// GetInfo returns metadata for a track.
+//
+// If opts.Username is set, the response includes that user's play count
+// and loved status for the track.
+//
+// Does not require authentication.
+//
+// Example:
+//
+//	info, err := client.Track().GetInfo(ctx, "Cher", "Believe", lastfm.TrackInfoOptions{
+//	    Username: "rj",
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("played %d times, loved: %v\n", info.UserPlayCount, info.UserLoved)
+func (s *TrackService) GetInfo(ctx context.Context, artist, track string, opts TrackInfoOptions) (*TrackInfo, error) {
+	params := map[string]string{}
+	if opts.MBID != "" {
+		params["mbid"] = opts.MBID
+	} else {
+		var err error
+		params, err = trackParams(artist, track)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opts.Username != "" {
+		params["username"] = opts.Username
+	}
+	if opts.Autocorrect {
+		params["autocorrect"] = "1"
+	}
+
+	resp, err := s.client.call(ctx, "track.getInfo", params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := unmarshalTrackInfo(resp)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: failed to parse track info response: %w", err)
+	}
+
+	return info, nil
+}
+
+// AddTags tags a track with up to 10 user-supplied tags.
+//
+// Requires authentication (session key must be set via SetSessionKey).
+func (s *TrackService) AddTags(ctx context.Context, artist, track string, tags []string) error {
+	if len(tags) == 0 {
+		return fmt.Errorf("lastfm: at least one tag is required")
+	}
+	if len(tags) > MaxTagsPerRequest {
+		return fmt.Errorf("lastfm: cannot add more than %d tags at once (got %d)", MaxTagsPerRequest, len(tags))
+	}
+
+	params, err := trackParams(artist, track)
+	if err != nil {
+		return err
+	}
+	params["tags"] = strings.Join(tags, ",")
+
+	_, err = s.client.call(ctx, "track.addTags", params, true)
+	return err
+}
+
+// RemoveTags removes the given tags from a track.
+//
+// Last.fm only accepts one tag per track.removeTag call, so this makes
+// one request per tag and stops at the first error.
+//
+// Requires authentication (session key must be set via SetSessionKey).
+func (s *TrackService) RemoveTags(ctx context.Context, artist, track string, tags []string) error {
+	if len(tags) == 0 {
+		return fmt.Errorf("lastfm: at least one tag is required")
+	}
+
+	for _, tag := range tags {
+		params, err := trackParams(artist, track)
+		if err != nil {
+			return err
+		}
+		params["tag"] = tag
+
+		if _, err := s.client.call(ctx, "track.removeTag", params, true); err != nil {
+			return fmt.Errorf("lastfm: failed to remove tag %q: %w", tag, err)
+		}
+	}
+
+	return nil
+}
+
+// GetTags returns the tags a user has applied to a track.
+//
+// If user is empty, the tags of the authenticated user are returned and
+// a session key is required.
+func (s *TrackService) GetTags(ctx context.Context, artist, track, user string) ([]Tag, error) {
+	params, err := trackParams(artist, track)
+	if err != nil {
+		return nil, err
+	}
+	if user != "" {
+		params["user"] = user
+	}
+
+	resp, err := s.client.call(ctx, "track.getTags", params, user == "")
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := unmarshalTags(resp)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: failed to parse tags response: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetCorrection checks whether Last.fm has a canonical spelling for a
+// track and artist.
+//
+// If there is no correction, the returned TrackCorrection holds the
+// input names with both Corrected flags false.
+//
+// Does not require authentication.
+//
+// Example:
+//
+//	c, err := client.Track().GetCorrection(ctx, "guns and roses", "Mrbrownstone")
+//	if err == nil && (c.ArtistCorrected || c.TrackCorrected) {
+//	    fmt.Printf("Did you mean %s - %s?\n", c.Artist, c.Track)
+//	}
+func (s *TrackService) GetCorrection(ctx context.Context, artist, track string) (*TrackCorrection, error) {
+	params, err := trackParams(artist, track)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.call(ctx, "track.getCorrection", params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	correction, err := unmarshalTrackCorrection(resp)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: failed to parse correction response: %w", err)
+	}
+	if correction == nil {
+		return &TrackCorrection{Artist: artist, Track: track}, nil
+	}
+
+	return correction, nil
+}
+
+// trackParams builds the artist/track parameters shared by track methods.
+func trackParams(artist, track string) (map[string]string, error) {
+	if artist == "" || track == "" {
+		return nil, fmt.Errorf("lastfm: artist and track are required")
+	}
+	return map[string]string{
+		"artist": artist,
+		"track":  track,
+	}, nil
+}
+
+// tagXML represents a <tag> element.
+type tagXML struct {
+	Name  string `xml:"name"`
+	URL   string `xml:"url"`
+	Count int    `xml:"count"`
+}
+
+// toTags converts parsed tag elements.
+func toTags(tags []tagXML) []Tag {
+	result := make([]Tag, len(tags))
+	for i, t := range tags {
+		result[i] = Tag(t)
+	}
+	return result
+}
+
+// trackInfoResponse represents the XML response from track.getInfo.
+type trackInfoResponse struct {
+	Track struct {
+		Name      string       `xml:"name"`
+		MBID      string       `xml:"mbid"`
+		URL       string       `xml:"url"`
+		Duration  int          `xml:"duration"` // milliseconds
+		Listeners int          `xml:"listeners"`
+		PlayCount int          `xml:"playcount"`
+		Artist    artistRefXML `xml:"artist"`
+		Album     struct {
+			Title  string     `xml:"title"`
+			Artist string     `xml:"artist"`
+			MBID   string     `xml:"mbid"`
+			URL    string     `xml:"url"`
+			Images []imageXML `xml:"image"`
+		} `xml:"album"`
+		UserPlayCount int      `xml:"userplaycount"`
+		UserLoved     bool     `xml:"userloved"`
+		TopTags       []tagXML `xml:"toptags>tag"`
+		Wiki          struct {
+			Summary string `xml:"summary"`
+		} `xml:"wiki"`
+	} `xml:"track"`
+}
+
+// unmarshalTrackInfo parses the XML response from track.getInfo.
+func unmarshalTrackInfo(data []byte) (*TrackInfo, error) {
+	// Wrap inner XML in root element for proper unmarshaling
+	wrapped := []byte("<root>" + string(data) + "</root>")
+
+	var resp trackInfoResponse
+	if err := xml.Unmarshal(wrapped, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal track info response: %w", err)
+	}
+
+	t := resp.Track
+	return &TrackInfo{
+		Name:          t.Name,
+		MBID:          t.MBID,
+		URL:           t.URL,
+		Duration:      t.Duration / 1000,
+		Listeners:     t.Listeners,
+		PlayCount:     t.PlayCount,
+		Artist:        t.Artist.name(),
+		ArtistMBID:    t.Artist.mbid(),
+		Album:         t.Album.Title,
+		AlbumArtist:   t.Album.Artist,
+		AlbumMBID:     t.Album.MBID,
+		AlbumImages:   toImages(t.Album.Images),
+		UserPlayCount: t.UserPlayCount,
+		UserLoved:     t.UserLoved,
+		TopTags:       toTags(t.TopTags),
+		Summary:       strings.TrimSpace(t.Wiki.Summary),
+	}, nil
+}
+
+// tagsResponse represents the XML response from track.getTags.
+type tagsResponse struct {
+	Tags []tagXML `xml:"tags>tag"`
+}
+
+// unmarshalTags parses the XML response from track.getTags.
+func unmarshalTags(data []byte) ([]Tag, error) {
+	// Wrap inner XML in root element for proper unmarshaling
+	wrapped := []byte("<root>" + string(data) + "</root>")
+
+	var resp tagsResponse
+	if err := xml.Unmarshal(wrapped, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags response: %w", err)
+	}
+
+	return toTags(resp.Tags), nil
+}
+
+// trackCorrectionResponse represents the XML response from track.getCorrection.
+type trackCorrectionResponse struct {
+	Corrections []struct {
+		ArtistCorrected bool `xml:"artistcorrected,attr"`
+		TrackCorrected  bool `xml:"trackcorrected,attr"`
+		Track           struct {
+			Name   string       `xml:"name"`
+			MBID   string       `xml:"mbid"`
+			Artist artistRefXML `xml:"artist"`
+		} `xml:"track"`
+	} `xml:"corrections>correction"`
+}
+
+// unmarshalTrackCorrection parses the XML response from track.getCorrection.
+// Returns nil if Last.fm returned no correction.
+func unmarshalTrackCorrection(data []byte) (*TrackCorrection, error) {
+	// Wrap inner XML in root element for proper unmarshaling
+	wrapped := []byte("<root>" + string(data) + "</root>")
+
+	var resp trackCorrectionResponse
+	if err := xml.Unmarshal(wrapped, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal correction response: %w", err)
+	}
+	if len(resp.Corrections) == 0 {
+		return nil, nil
+	}
+
+	c := resp.Corrections[0]
+	return &TrackCorrection{
+		Artist:          c.Track.Artist.name(),
+		ArtistMBID:      c.Track.Artist.mbid(),
+		Track:           c.Track.Name,
+		MBID:            c.Track.MBID,
+		ArtistCorrected: c.ArtistCorrected,
+		TrackCorrected:  c.TrackCorrected,
+	}, nil
+}
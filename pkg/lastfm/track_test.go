@@ -0,0 +1,317 @@
+package lastfm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const okResponse = `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+</lfm>`
+
+func TestTrackService_LoveUnlove(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		call   func(ctx context.Context, c *Client) error
+	}{
+		{
+			name:   "love",
+			method: "track.love",
+			call: func(ctx context.Context, c *Client) error {
+				return c.Track().Love(ctx, "The Beatles", "Yesterday")
+			},
+		},
+		{
+			name:   "unlove",
+			method: "track.unlove",
+			call: func(ctx context.Context, c *Client) error {
+				return c.Track().Unlove(ctx, "The Beatles", "Yesterday")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMethodServer(t, tt.method, okResponse, func(r *http.Request) {
+				if got := r.FormValue("artist"); got != "The Beatles" {
+					t.Errorf("expected artist The Beatles, got %s", got)
+				}
+				if got := r.FormValue("track"); got != "Yesterday" {
+					t.Errorf("expected track Yesterday, got %s", got)
+				}
+				if got := r.FormValue("sk"); got != "test-session-key" {
+					t.Errorf("expected sk test-session-key, got %s", got)
+				}
+			})
+
+			if err := tt.call(context.Background(), client); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTrackService_LoveRequiresSession(t *testing.T) {
+	client, err := NewClient(Config{APIKey: "key", APISecret: "secret"})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	err = client.Track().Love(context.Background(), "The Beatles", "Yesterday")
+	if !errors.Is(err, ErrNoSessionKey) {
+		t.Errorf("expected ErrNoSessionKey, got %v", err)
+	}
+}
+
+func TestTrackService_GetInfo(t *testing.T) {
+	response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<track>
+		<id>1019817</id>
+		<name>Believe</name>
+		<mbid>track-mbid</mbid>
+		<url>https://www.last.fm/music/Cher/_/Believe</url>
+		<duration>240000</duration>
+		<listeners>69572</listeners>
+		<playcount>281445</playcount>
+		<artist>
+			<name>Cher</name>
+			<mbid>artist-mbid</mbid>
+			<url>https://www.last.fm/music/Cher</url>
+		</artist>
+		<album position="1">
+			<artist>Cher</artist>
+			<title>Believe</title>
+			<mbid>album-mbid</mbid>
+			<image size="small">https://img/s.png</image>
+			<image size="extralarge">https://img/xl.png</image>
+		</album>
+		<userplaycount>12</userplaycount>
+		<userloved>1</userloved>
+		<toptags>
+			<tag><name>pop</name><url>https://www.last.fm/tag/pop</url></tag>
+			<tag><name>dance</name><url>https://www.last.fm/tag/dance</url></tag>
+		</toptags>
+		<wiki>
+			<summary> A song by Cher. </summary>
+		</wiki>
+	</track>
+</lfm>`
+
+	client := newMethodServer(t, "track.getInfo", response, func(r *http.Request) {
+		if got := r.FormValue("username"); got != "rj" {
+			t.Errorf("expected username rj, got %s", got)
+		}
+		if got := r.FormValue("autocorrect"); got != "1" {
+			t.Errorf("expected autocorrect 1, got %s", got)
+		}
+	})
+
+	info, err := client.Track().GetInfo(context.Background(), "Cher", "Believe", TrackInfoOptions{
+		Username:    "rj",
+		Autocorrect: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Name != "Believe" || info.Artist != "Cher" || info.ArtistMBID != "artist-mbid" {
+		t.Errorf("unexpected track: %+v", info)
+	}
+	if info.Duration != 240 {
+		t.Errorf("expected duration 240s, got %d", info.Duration)
+	}
+	if info.Album != "Believe" || info.AlbumArtist != "Cher" || len(info.AlbumImages) != 2 {
+		t.Errorf("unexpected album: %q by %q, images %+v", info.Album, info.AlbumArtist, info.AlbumImages)
+	}
+	if info.UserPlayCount != 12 || !info.UserLoved {
+		t.Errorf("expected user playcount 12 and loved, got %d, %v", info.UserPlayCount, info.UserLoved)
+	}
+	if len(info.TopTags) != 2 || info.TopTags[0].Name != "pop" {
+		t.Errorf("unexpected tags: %+v", info.TopTags)
+	}
+	if info.Summary != "A song by Cher." {
+		t.Errorf("expected trimmed summary, got %q", info.Summary)
+	}
+}
+
+func TestTrackService_GetInfo_ByMBID(t *testing.T) {
+	client := newMethodServer(t, "track.getInfo", `<lfm status="ok"><track><name>Believe</name></track></lfm>`, func(r *http.Request) {
+		if got := r.FormValue("mbid"); got != "track-mbid" {
+			t.Errorf("expected mbid track-mbid, got %s", got)
+		}
+		if _, ok := r.Form["artist"]; ok {
+			t.Error("expected no artist param when looking up by mbid")
+		}
+	})
+
+	if _, err := client.Track().GetInfo(context.Background(), "", "", TrackInfoOptions{MBID: "track-mbid"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTrackService_AddTags(t *testing.T) {
+	client := newMethodServer(t, "track.addTags", okResponse, func(r *http.Request) {
+		if got := r.FormValue("tags"); got != "rock,sixties" {
+			t.Errorf("expected tags rock,sixties, got %s", got)
+		}
+	})
+	ctx := context.Background()
+
+	if err := client.Track().AddTags(ctx, "The Beatles", "Yesterday", []string{"rock", "sixties"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := client.Track().AddTags(ctx, "The Beatles", "Yesterday", nil); err == nil {
+		t.Error("expected error for empty tags")
+	}
+
+	tooMany := make([]string, MaxTagsPerRequest+1)
+	for i := range tooMany {
+		tooMany[i] = "tag"
+	}
+	if err := client.Track().AddTags(ctx, "The Beatles", "Yesterday", tooMany); err == nil {
+		t.Error("expected error for more than 10 tags")
+	}
+}
+
+func TestTrackService_RemoveTags(t *testing.T) {
+	var removed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		if got := r.FormValue("method"); got != "track.removeTag" {
+			t.Errorf("expected method track.removeTag, got %s", got)
+		}
+		removed = append(removed, r.FormValue("tag"))
+		if _, err := w.Write([]byte(okResponse)); err != nil {
+			t.Fatalf("failed to write response body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{
+		APIKey:     "test-api-key",
+		APISecret:  "test-secret",
+		SessionKey: "test-session-key",
+		BaseURL:    server.URL,
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if err := client.Track().RemoveTags(context.Background(), "The Beatles", "Yesterday", []string{"rock", "sixties"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(removed, ","); got != "rock,sixties" {
+		t.Errorf("expected one removeTag call per tag, got %s", got)
+	}
+}
+
+func TestTrackService_GetTags(t *testing.T) {
+	response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<tags artist="The Beatles" track="Yesterday">
+		<tag><name>sad</name><url>https://www.last.fm/tag/sad</url></tag>
+	</tags>
+</lfm>`
+
+	t.Run("for user", func(t *testing.T) {
+		client := newMethodServer(t, "track.getTags", response, func(r *http.Request) {
+			if got := r.FormValue("user"); got != "rj" {
+				t.Errorf("expected user rj, got %s", got)
+			}
+			if got := r.FormValue("sk"); got != "" {
+				t.Errorf("expected no session key, got %s", got)
+			}
+		})
+
+		tags, err := client.Track().GetTags(context.Background(), "The Beatles", "Yesterday", "rj")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(tags) != 1 || tags[0].Name != "sad" {
+			t.Errorf("unexpected tags: %+v", tags)
+		}
+	})
+
+	t.Run("for authenticated user", func(t *testing.T) {
+		client := newMethodServer(t, "track.getTags", response, func(r *http.Request) {
+			if got := r.FormValue("sk"); got != "test-session-key" {
+				t.Errorf("expected sk test-session-key, got %s", got)
+			}
+		})
+
+		if _, err := client.Track().GetTags(context.Background(), "The Beatles", "Yesterday", ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestTrackService_GetCorrection(t *testing.T) {
+	t.Run("corrected", func(t *testing.T) {
+		response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<corrections>
+		<correction index="0" artistcorrected="1" trackcorrected="1">
+			<track>
+				<name>Mr. Brownstone</name>
+				<mbid>track-mbid</mbid>
+				<artist>
+					<name>Guns N' Roses</name>
+					<mbid>artist-mbid</mbid>
+				</artist>
+			</track>
+		</correction>
+	</corrections>
+</lfm>`
+		client := newMethodServer(t, "track.getCorrection", response, nil)
+
+		c, err := client.Track().GetCorrection(context.Background(), "guns and roses", "Mrbrownstone")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.Artist != "Guns N' Roses" || c.Track != "Mr. Brownstone" {
+			t.Errorf("unexpected correction: %+v", c)
+		}
+		if !c.ArtistCorrected || !c.TrackCorrected {
+			t.Errorf("expected both corrected flags, got %+v", c)
+		}
+	})
+
+	t.Run("no correction", func(t *testing.T) {
+		client := newMethodServer(t, "track.getCorrection", `<lfm status="ok"><corrections/></lfm>`, nil)
+
+		c, err := client.Track().GetCorrection(context.Background(), "Cher", "Believe")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.Artist != "Cher" || c.Track != "Believe" || c.ArtistCorrected || c.TrackCorrected {
+			t.Errorf("expected input to be returned unchanged, got %+v", c)
+		}
+	})
+}
+
+func TestTrackService_RequiresArtistAndTrack(t *testing.T) {
+	client, err := NewClient(Config{APIKey: "key", APISecret: "secret", SessionKey: "sk"})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := client.Track().Love(ctx, "", "Yesterday"); err == nil {
+		t.Error("expected error for empty artist")
+	}
+	if _, err := client.Track().GetInfo(ctx, "The Beatles", "", TrackInfoOptions{}); err == nil {
+		t.Error("expected error for empty track")
+	}
+	if _, err := client.Track().GetCorrection(ctx, "", ""); err == nil {
+		t.Error("expected error for empty artist and track")
+	}
+}
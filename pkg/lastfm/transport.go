@@ -105,7 +105,7 @@ func (c *Client) call(ctx context.Context, method string, params map[string]stri
 
 		// Handle HTTP status codes
 		if resp.StatusCode >= 500 {
-			lastErr = fmt.Errorf("server error: %d %s", resp.StatusCode, resp.Status)
+			lastErr = &StatusError{StatusCode: resp.StatusCode}
 			if i < maxRetries-1 {
 				c.logDebugf("lastfm: server error, retrying: %v", lastErr)
 				if !sleep(ctx, backoff) {
@@ -118,7 +118,7 @@ func (c *Client) call(ctx context.Context, method string, params map[string]stri
 		}
 
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+			return nil, &StatusError{StatusCode: resp.StatusCode}
 		}
 
 		// Parse XML response
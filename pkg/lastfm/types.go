@@ -45,6 +45,16 @@ type NowPlayingResponse struct {
 	}
 }
 
+// Ignored message codes returned per scrobble by track.scrobble and
+// track.updateNowPlaying. A code of 0 means the scrobble was accepted.
+const (
+	IgnoredArtist             = 1 // Artist was ignored
+	IgnoredTrack              = 2 // Track was ignored
+	IgnoredTimestampTooOld    = 3 // Timestamp is older than Last.fm accepts
+	IgnoredTimestampTooNew    = 4 // Timestamp is in the future
+	IgnoredDailyLimitExceeded = 5 // Daily scrobble limit exceeded; retry later
+)
+
 // ScrobbleResponse represents the response from track.scrobble.
 type ScrobbleResponse struct {
 	Accepted  int // Number of scrobbles accepted
@@ -60,3 +70,306 @@ type ScrobbleResponse struct {
 		}
 	}
 }
+
+// Image represents an image URL returned by Last.fm at a given size.
+type Image struct {
+	Size string // Image size: "small", "medium", "large", "extralarge", "mega"
+	URL  string // Image URL (may be empty if Last.fm has no image)
+}
+
+// Image sizes returned by Last.fm, smallest first.
+const (
+	ImageSizeSmall      = "small"
+	ImageSizeMedium     = "medium"
+	ImageSizeLarge      = "large"
+	ImageSizeExtraLarge = "extralarge"
+	ImageSizeMega       = "mega"
+)
+
+// Period is a time range for user top charts.
+type Period string
+
+// Supported periods for user.getTopArtists, user.getTopAlbums and
+// user.getTopTracks.
+const (
+	PeriodOverall Period = "overall"
+	Period7Day    Period = "7day"
+	Period1Month  Period = "1month"
+	Period3Month  Period = "3month"
+	Period6Month  Period = "6month"
+	Period12Month Period = "12month"
+)
+
+// ListOptions controls paging for list-returning endpoints.
+type ListOptions struct {
+	Limit int // Optional: Results per page (Last.fm defaults to 50)
+	Page  int // Optional: Page number, starting at 1
+}
+
+// RecentTracksOptions holds the parameters for user.getRecentTracks.
+type RecentTracksOptions struct {
+	ListOptions
+	From     time.Time // Optional: Only return scrobbles after this time
+	To       time.Time // Optional: Only return scrobbles before this time
+	Extended bool      // Optional: Include extended artist data and loved status
+}
+
+// TopOptions holds the parameters for the user top chart endpoints.
+type TopOptions struct {
+	ListOptions
+	Period Period // Optional: Time range (defaults to PeriodOverall)
+}
+
+// RecentTrack represents a single entry from user.getRecentTracks.
+type RecentTrack struct {
+	Artist     string
+	ArtistMBID string
+	Track      string
+	MBID       string
+	Album      string
+	AlbumMBID  string
+	URL        string
+	Images     []Image
+	NowPlaying bool      // True if the user is currently listening to this track
+	Timestamp  time.Time // When the track was scrobbled (zero if now playing)
+	Loved      bool      // Only populated when Extended is set
+}
+
+// RecentTracksResponse represents the response from user.getRecentTracks.
+type RecentTracksResponse struct {
+	Pagination
+	User   string
+	Tracks []RecentTrack
+}
+
+// UserInfo represents the response from user.getInfo.
+type UserInfo struct {
+	Name        string
+	RealName    string
+	URL         string
+	Country     string
+	Subscriber  bool
+	PlayCount   int
+	ArtistCount int
+	AlbumCount  int
+	TrackCount  int
+	Registered  time.Time
+	Images      []Image
+}
+
+// LovedTrack represents a single entry from user.getLovedTracks.
+type LovedTrack struct {
+	Artist     string
+	ArtistMBID string
+	Track      string
+	MBID       string
+	URL        string
+	Images     []Image
+	Timestamp  time.Time // When the track was loved
+}
+
+// LovedTracksResponse represents the response from user.getLovedTracks.
+type LovedTracksResponse struct {
+	Pagination
+	User   string
+	Tracks []LovedTrack
+}
+
+// TopArtist represents a single entry from user.getTopArtists.
+type TopArtist struct {
+	Rank      int
+	Name      string
+	MBID      string
+	URL       string
+	PlayCount int
+	Images    []Image
+}
+
+// TopArtistsResponse represents the response from user.getTopArtists.
+type TopArtistsResponse struct {
+	Pagination
+	User    string
+	Period  Period
+	Artists []TopArtist
+}
+
+// TopAlbum represents a single entry from user.getTopAlbums.
+type TopAlbum struct {
+	Rank      int
+	Name      string
+	MBID      string
+	Artist    string
+	URL       string
+	PlayCount int
+	Images    []Image
+}
+
+// TopAlbumsResponse represents the response from user.getTopAlbums.
+type TopAlbumsResponse struct {
+	Pagination
+	User   string
+	Period Period
+	Albums []TopAlbum
+}
+
+// TopTrack represents a single entry from user.getTopTracks or
+// artist.getTopTracks.
+type TopTrack struct {
+	Rank      int
+	Name      string
+	MBID      string
+	Artist    string
+	URL       string
+	Duration  int // Track duration in seconds
+	PlayCount int
+	Listeners int // Only returned by artist.getTopTracks
+	Images    []Image
+}
+
+// TopTracksResponse represents the response from user.getTopTracks.
+type TopTracksResponse struct {
+	Pagination
+	User   string
+	Period Period
+	Tracks []TopTrack
+}
+
+// Tag represents a Last.fm tag.
+type Tag struct {
+	Name  string
+	URL   string
+	Count int // Tag weight, only returned by some endpoints
+}
+
+// TrackInfoOptions holds the optional parameters for track.getInfo.
+type TrackInfoOptions struct {
+	MBID        string // Optional: Look up by MusicBrainz ID instead of artist/track
+	Username    string // Optional: Include this user's play count and loved status
+	Autocorrect bool   // Optional: Let Last.fm correct misspelled artist/track names
+}
+
+// TrackInfo represents the response from track.getInfo.
+type TrackInfo struct {
+	Name          string
+	MBID          string
+	URL           string
+	Duration      int // Track duration in seconds
+	Listeners     int
+	PlayCount     int
+	Artist        string
+	ArtistMBID    string
+	Album         string
+	AlbumArtist   string
+	AlbumMBID     string
+	AlbumImages   []Image
+	UserPlayCount int  // Only populated when Username is set
+	UserLoved     bool // Only populated when Username is set
+	TopTags       []Tag
+	Summary       string // Short wiki summary, may be empty
+}
+
+// TrackCorrection represents the response from track.getCorrection.
+type TrackCorrection struct {
+	Artist          string
+	ArtistMBID      string
+	Track           string
+	MBID            string
+	ArtistCorrected bool // Whether the artist name was corrected
+	TrackCorrected  bool // Whether the track name was corrected
+}
+
+// ArtistInfoOptions holds the optional parameters for artist.getInfo.
+type ArtistInfoOptions struct {
+	MBID        string // Optional: Look up by MusicBrainz ID instead of name
+	Username    string // Optional: Include this user's play count
+	Autocorrect bool   // Optional: Let Last.fm correct a misspelled artist name
+}
+
+// ArtistInfo represents the response from artist.getInfo.
+type ArtistInfo struct {
+	Name          string
+	MBID          string
+	URL           string
+	Images        []Image
+	OnTour        bool
+	Listeners     int
+	PlayCount     int
+	UserPlayCount int // Only populated when Username is set
+	Similar       []SimilarArtist
+	Tags          []Tag
+	Summary       string // Short biography summary, may be empty
+}
+
+// SimilarArtist represents an artist returned by artist.getSimilar or in
+// the similar section of artist.getInfo.
+type SimilarArtist struct {
+	Name   string
+	MBID   string
+	URL    string
+	Match  float64 // Similarity from 0 to 1, only returned by artist.getSimilar
+	Images []Image
+}
+
+// ArtistTopTracksResponse represents the response from artist.getTopTracks.
+type ArtistTopTracksResponse struct {
+	Pagination
+	Artist string
+	Tracks []TopTrack
+}
+
+// ArtistCorrection represents the response from artist.getCorrection.
+type ArtistCorrection struct {
+	Artist    string
+	MBID      string
+	Corrected bool // Whether the artist name was corrected
+}
+
+// AlbumInfoOptions holds the optional parameters for album.getInfo.
+type AlbumInfoOptions struct {
+	MBID        string // Optional: Look up by MusicBrainz ID instead of artist/album
+	Username    string // Optional: Include this user's play count
+	Autocorrect bool   // Optional: Let Last.fm correct misspelled artist/album names
+}
+
+// AlbumInfo represents the response from album.getInfo.
+type AlbumInfo struct {
+	Name          string
+	Artist        string
+	MBID          string
+	URL           string
+	Images        []Image
+	Listeners     int
+	PlayCount     int
+	UserPlayCount int // Only populated when Username is set
+	Tracks        []AlbumTrack
+	Tags          []Tag
+	Summary       string // Short wiki summary, may be empty
+}
+
+// ImageURL returns the URL of the album cover at the given size (one of
+// the ImageSize constants). If size is empty, the largest available image
+// is returned. Returns "" if no matching image exists.
+func (a *AlbumInfo) ImageURL(size string) string {
+	if size == "" {
+		if len(a.Images) == 0 {
+			return ""
+		}
+		// Last.fm lists images smallest first
+		return a.Images[len(a.Images)-1].URL
+	}
+	for _, img := range a.Images {
+		if img.Size == size {
+			return img.URL
+		}
+	}
+	return ""
+}
+
+// AlbumTrack represents a track in an album's tracklist.
+type AlbumTrack struct {
+	Rank     int
+	Name     string
+	URL      string
+	Duration int // Track duration in seconds
+	Artist   string
+}
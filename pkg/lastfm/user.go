@@ -0,0 +1,674 @@
+package lastfm
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UserService provides read access to Last.fm user data.
+type UserService struct {
+	client *Client
+}
+
+// GetRecentTracks returns the tracks recently scrobbled by a user.
+//
+// If the user is currently listening to something, the first track in
+// the response has NowPlaying set and a zero Timestamp.
+//
+// Does not require authentication.
+//
+// Example:
+//
+//	resp, err := client.User().GetRecentTracks(ctx, "rj", lastfm.RecentTracksOptions{
+//	    ListOptions: lastfm.ListOptions{Limit: 10},
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, t := range resp.Tracks {
+//	    fmt.Printf("%s - %s\n", t.Artist, t.Track)
+//	}
+func (s *UserService) GetRecentTracks(ctx context.Context, user string, opts RecentTracksOptions) (*RecentTracksResponse, error) {
+	if user == "" {
+		return nil, fmt.Errorf("lastfm: user is required")
+	}
+
+	params := map[string]string{
+		"user": user,
+	}
+	addListParams(params, opts.ListOptions)
+	if !opts.From.IsZero() {
+		params["from"] = fmt.Sprintf("%d", opts.From.Unix())
+	}
+	if !opts.To.IsZero() {
+		params["to"] = fmt.Sprintf("%d", opts.To.Unix())
+	}
+	if opts.Extended {
+		params["extended"] = "1"
+	}
+
+	resp, err := s.client.call(ctx, "user.getRecentTracks", params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	recent, err := unmarshalRecentTracks(resp)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: failed to parse recent tracks response: %w", err)
+	}
+
+	return recent, nil
+}
+
+// GetInfo returns profile information about a user.
+//
+// Does not require authentication.
+//
+// Example:
+//
+//	info, err := client.User().GetInfo(ctx, "rj")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("%s has %d scrobbles\n", info.Name, info.PlayCount)
+func (s *UserService) GetInfo(ctx context.Context, user string) (*UserInfo, error) {
+	if user == "" {
+		return nil, fmt.Errorf("lastfm: user is required")
+	}
+
+	params := map[string]string{
+		"user": user,
+	}
+
+	resp, err := s.client.call(ctx, "user.getInfo", params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := unmarshalUserInfo(resp)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: failed to parse user info response: %w", err)
+	}
+
+	return info, nil
+}
+
+// GetLovedTracks returns the tracks a user has loved.
+//
+// Does not require authentication.
+func (s *UserService) GetLovedTracks(ctx context.Context, user string, opts ListOptions) (*LovedTracksResponse, error) {
+	if user == "" {
+		return nil, fmt.Errorf("lastfm: user is required")
+	}
+
+	params := map[string]string{
+		"user": user,
+	}
+	addListParams(params, opts)
+
+	resp, err := s.client.call(ctx, "user.getLovedTracks", params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	loved, err := unmarshalLovedTracks(resp)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: failed to parse loved tracks response: %w", err)
+	}
+
+	return loved, nil
+}
+
+// GetTopArtists returns a user's most played artists over the given period.
+//
+// Does not require authentication.
+//
+// Example:
+//
+//	resp, err := client.User().GetTopArtists(ctx, "rj", lastfm.TopOptions{
+//	    Period: lastfm.Period7Day,
+//	})
+func (s *UserService) GetTopArtists(ctx context.Context, user string, opts TopOptions) (*TopArtistsResponse, error) {
+	params, err := topParams(user, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.call(ctx, "user.getTopArtists", params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	top, err := unmarshalTopArtists(resp)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: failed to parse top artists response: %w", err)
+	}
+
+	return top, nil
+}
+
+// GetTopAlbums returns a user's most played albums over the given period.
+//
+// Does not require authentication.
+func (s *UserService) GetTopAlbums(ctx context.Context, user string, opts TopOptions) (*TopAlbumsResponse, error) {
+	params, err := topParams(user, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.call(ctx, "user.getTopAlbums", params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	top, err := unmarshalTopAlbums(resp)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: failed to parse top albums response: %w", err)
+	}
+
+	return top, nil
+}
+
+// GetTopTracks returns a user's most played tracks over the given period.
+//
+// Does not require authentication.
+func (s *UserService) GetTopTracks(ctx context.Context, user string, opts TopOptions) (*TopTracksResponse, error) {
+	params, err := topParams(user, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.call(ctx, "user.getTopTracks", params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	top, err := unmarshalTopTracks(resp)
+	if err != nil {
+		return nil, fmt.Errorf("lastfm: failed to parse top tracks response: %w", err)
+	}
+
+	return top, nil
+}
+
+// RecentTracksPager returns a Pager over a user's scrobble history.
+//
+// Paging starts at opts.Page (or the first page). The now playing entry
+// Last.fm prepends to each page is skipped so that only completed
+// scrobbles are returned; use GetRecentTracks to see what is playing.
+//
+// Example:
+//
+//	pager := client.User().RecentTracksPager("rj", lastfm.RecentTracksOptions{
+//	    ListOptions: lastfm.ListOptions{Limit: 200},
+//	}).WithMaxItems(5000)
+//	for track, err := range pager.All(ctx) {
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    fmt.Println(track.Timestamp, track.Artist, "-", track.Track)
+//	}
+func (s *UserService) RecentTracksPager(user string, opts RecentTracksOptions) *Pager[RecentTrack] {
+	return NewPager(func(ctx context.Context, page int) ([]RecentTrack, Pagination, error) {
+		pageOpts := opts
+		pageOpts.Page = page
+		resp, err := s.GetRecentTracks(ctx, user, pageOpts)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		tracks := make([]RecentTrack, 0, len(resp.Tracks))
+		for _, t := range resp.Tracks {
+			if !t.NowPlaying {
+				tracks = append(tracks, t)
+			}
+		}
+		return tracks, resp.Pagination, nil
+	}, opts.Page)
+}
+
+// LovedTracksPager returns a Pager over a user's loved tracks.
+func (s *UserService) LovedTracksPager(user string, opts ListOptions) *Pager[LovedTrack] {
+	return NewPager(func(ctx context.Context, page int) ([]LovedTrack, Pagination, error) {
+		pageOpts := opts
+		pageOpts.Page = page
+		resp, err := s.GetLovedTracks(ctx, user, pageOpts)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		return resp.Tracks, resp.Pagination, nil
+	}, opts.Page)
+}
+
+// TopArtistsPager returns a Pager over a user's top artists.
+func (s *UserService) TopArtistsPager(user string, opts TopOptions) *Pager[TopArtist] {
+	return NewPager(func(ctx context.Context, page int) ([]TopArtist, Pagination, error) {
+		pageOpts := opts
+		pageOpts.Page = page
+		resp, err := s.GetTopArtists(ctx, user, pageOpts)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		return resp.Artists, resp.Pagination, nil
+	}, opts.Page)
+}
+
+// TopAlbumsPager returns a Pager over a user's top albums.
+func (s *UserService) TopAlbumsPager(user string, opts TopOptions) *Pager[TopAlbum] {
+	return NewPager(func(ctx context.Context, page int) ([]TopAlbum, Pagination, error) {
+		pageOpts := opts
+		pageOpts.Page = page
+		resp, err := s.GetTopAlbums(ctx, user, pageOpts)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		return resp.Albums, resp.Pagination, nil
+	}, opts.Page)
+}
+
+// TopTracksPager returns a Pager over a user's top tracks.
+func (s *UserService) TopTracksPager(user string, opts TopOptions) *Pager[TopTrack] {
+	return NewPager(func(ctx context.Context, page int) ([]TopTrack, Pagination, error) {
+		pageOpts := opts
+		pageOpts.Page = page
+		resp, err := s.GetTopTracks(ctx, user, pageOpts)
+		if err != nil {
+			return nil, Pagination{}, err
+		}
+		return resp.Tracks, resp.Pagination, nil
+	}, opts.Page)
+}
+
+// addListParams adds the limit and page parameters if they are set.
+func addListParams(params map[string]string, opts ListOptions) {
+	if opts.Limit > 0 {
+		params["limit"] = fmt.Sprintf("%d", opts.Limit)
+	}
+	if opts.Page > 0 {
+		params["page"] = fmt.Sprintf("%d", opts.Page)
+	}
+}
+
+// topParams builds the parameters shared by the user top chart endpoints.
+func topParams(user string, opts TopOptions) (map[string]string, error) {
+	if user == "" {
+		return nil, fmt.Errorf("lastfm: user is required")
+	}
+
+	period := opts.Period
+	if period == "" {
+		period = PeriodOverall
+	}
+	switch period {
+	case PeriodOverall, Period7Day, Period1Month, Period3Month, Period6Month, Period12Month:
+	default:
+		return nil, fmt.Errorf("lastfm: invalid period %q", period)
+	}
+
+	params := map[string]string{
+		"user":   user,
+		"period": string(period),
+	}
+	addListParams(params, opts.ListOptions)
+	return params, nil
+}
+
+// imageXML represents an <image size="..."> element.
+type imageXML struct {
+	Size string `xml:"size,attr"`
+	URL  string `xml:",chardata"`
+}
+
+// toImages converts parsed image elements, dropping entries without a URL.
+func toImages(images []imageXML) []Image {
+	var result []Image
+	for _, img := range images {
+		u := strings.TrimSpace(img.URL)
+		if u == "" {
+			continue
+		}
+		result = append(result, Image{Size: img.Size, URL: u})
+	}
+	return result
+}
+
+// artistRefXML represents an artist reference inside a track or album.
+//
+// Last.fm returns either a plain <artist mbid="...">Name</artist> or, for
+// extended responses, nested <name>, <mbid> and <url> elements.
+type artistRefXML struct {
+	Text     string `xml:",chardata"`
+	MBIDAttr string `xml:"mbid,attr"`
+	Name     string `xml:"name"`
+	MBID     string `xml:"mbid"`
+	URL      string `xml:"url"`
+}
+
+// name returns the artist name from whichever form was returned.
+func (a artistRefXML) name() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return strings.TrimSpace(a.Text)
+}
+
+// mbid returns the artist MBID from whichever form was returned.
+func (a artistRefXML) mbid() string {
+	if a.MBID != "" {
+		return a.MBID
+	}
+	return a.MBIDAttr
+}
+
+// dateXML represents a <date uts="..."> element.
+type dateXML struct {
+	UTS int64 `xml:"uts,attr"`
+}
+
+// toTime converts the unix timestamp, returning the zero time if unset.
+func (d dateXML) toTime() time.Time {
+	if d.UTS == 0 {
+		return time.Time{}
+	}
+	return time.Unix(d.UTS, 0)
+}
+
+// recentTracksResponse represents the XML response from user.getRecentTracks.
+type recentTracksResponse struct {
+	RecentTracks struct {
+		paginationXML
+		User   string `xml:"user,attr"`
+		Tracks []struct {
+			NowPlaying bool         `xml:"nowplaying,attr"`
+			Artist     artistRefXML `xml:"artist"`
+			Name       string       `xml:"name"`
+			MBID       string       `xml:"mbid"`
+			Album      struct {
+				Name string `xml:",chardata"`
+				MBID string `xml:"mbid,attr"`
+			} `xml:"album"`
+			URL    string     `xml:"url"`
+			Images []imageXML `xml:"image"`
+			Date   dateXML    `xml:"date"`
+			Loved  bool       `xml:"loved"`
+		} `xml:"track"`
+	} `xml:"recenttracks"`
+}
+
+// unmarshalRecentTracks parses the XML response from user.getRecentTracks.
+func unmarshalRecentTracks(data []byte) (*RecentTracksResponse, error) {
+	// Wrap inner XML in root element for proper unmarshaling
+	wrapped := []byte("<root>" + string(data) + "</root>")
+
+	var resp recentTracksResponse
+	if err := xml.Unmarshal(wrapped, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal recent tracks response: %w", err)
+	}
+
+	result := &RecentTracksResponse{
+		User:       resp.RecentTracks.User,
+		Pagination: resp.RecentTracks.toPagination(),
+		Tracks:     make([]RecentTrack, len(resp.RecentTracks.Tracks)),
+	}
+	for i, t := range resp.RecentTracks.Tracks {
+		result.Tracks[i] = RecentTrack{
+			Artist:     t.Artist.name(),
+			ArtistMBID: t.Artist.mbid(),
+			Track:      t.Name,
+			MBID:       t.MBID,
+			Album:      t.Album.Name,
+			AlbumMBID:  t.Album.MBID,
+			URL:        t.URL,
+			Images:     toImages(t.Images),
+			NowPlaying: t.NowPlaying,
+			Timestamp:  t.Date.toTime(),
+			Loved:      t.Loved,
+		}
+	}
+
+	return result, nil
+}
+
+// userInfoResponse represents the XML response from user.getInfo.
+type userInfoResponse struct {
+	User struct {
+		Name        string     `xml:"name"`
+		RealName    string     `xml:"realname"`
+		URL         string     `xml:"url"`
+		Country     string     `xml:"country"`
+		Subscriber  int        `xml:"subscriber"`
+		PlayCount   int        `xml:"playcount"`
+		ArtistCount int        `xml:"artist_count"`
+		AlbumCount  int        `xml:"album_count"`
+		TrackCount  int        `xml:"track_count"`
+		Images      []imageXML `xml:"image"`
+		Registered  struct {
+			UnixTime int64 `xml:"unixtime,attr"`
+		} `xml:"registered"`
+	} `xml:"user"`
+}
+
+// unmarshalUserInfo parses the XML response from user.getInfo.
+func unmarshalUserInfo(data []byte) (*UserInfo, error) {
+	// Wrap inner XML in root element for proper unmarshaling
+	wrapped := []byte("<root>" + string(data) + "</root>")
+
+	var resp userInfoResponse
+	if err := xml.Unmarshal(wrapped, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user info response: %w", err)
+	}
+
+	u := resp.User
+	info := &UserInfo{
+		Name:        u.Name,
+		RealName:    strings.TrimSpace(u.RealName),
+		URL:         u.URL,
+		Country:     u.Country,
+		Subscriber:  u.Subscriber == 1,
+		PlayCount:   u.PlayCount,
+		ArtistCount: u.ArtistCount,
+		AlbumCount:  u.AlbumCount,
+		TrackCount:  u.TrackCount,
+		Images:      toImages(u.Images),
+	}
+	if u.Registered.UnixTime > 0 {
+		info.Registered = time.Unix(u.Registered.UnixTime, 0)
+	}
+
+	return info, nil
+}
+
+// lovedTracksResponse represents the XML response from user.getLovedTracks.
+type lovedTracksResponse struct {
+	LovedTracks struct {
+		paginationXML
+		User   string `xml:"user,attr"`
+		Tracks []struct {
+			Name   string       `xml:"name"`
+			MBID   string       `xml:"mbid"`
+			URL    string       `xml:"url"`
+			Date   dateXML      `xml:"date"`
+			Artist artistRefXML `xml:"artist"`
+			Images []imageXML   `xml:"image"`
+		} `xml:"track"`
+	} `xml:"lovedtracks"`
+}
+
+// unmarshalLovedTracks parses the XML response from user.getLovedTracks.
+func unmarshalLovedTracks(data []byte) (*LovedTracksResponse, error) {
+	// Wrap inner XML in root element for proper unmarshaling
+	wrapped := []byte("<root>" + string(data) + "</root>")
+
+	var resp lovedTracksResponse
+	if err := xml.Unmarshal(wrapped, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal loved tracks response: %w", err)
+	}
+
+	result := &LovedTracksResponse{
+		User:       resp.LovedTracks.User,
+		Pagination: resp.LovedTracks.toPagination(),
+		Tracks:     make([]LovedTrack, len(resp.LovedTracks.Tracks)),
+	}
+	for i, t := range resp.LovedTracks.Tracks {
+		result.Tracks[i] = LovedTrack{
+			Artist:     t.Artist.name(),
+			ArtistMBID: t.Artist.mbid(),
+			Track:      t.Name,
+			MBID:       t.MBID,
+			URL:        t.URL,
+			Images:     toImages(t.Images),
+			Timestamp:  t.Date.toTime(),
+		}
+	}
+
+	return result, nil
+}
+
+// topArtistsResponse represents the XML response from user.getTopArtists.
+type topArtistsResponse struct {
+	TopArtists struct {
+		paginationXML
+		User    string `xml:"user,attr"`
+		Period  string `xml:"type,attr"`
+		Artists []struct {
+			Rank      int        `xml:"rank,attr"`
+			Name      string     `xml:"name"`
+			MBID      string     `xml:"mbid"`
+			URL       string     `xml:"url"`
+			PlayCount int        `xml:"playcount"`
+			Images    []imageXML `xml:"image"`
+		} `xml:"artist"`
+	} `xml:"topartists"`
+}
+
+// unmarshalTopArtists parses the XML response from user.getTopArtists.
+func unmarshalTopArtists(data []byte) (*TopArtistsResponse, error) {
+	// Wrap inner XML in root element for proper unmarshaling
+	wrapped := []byte("<root>" + string(data) + "</root>")
+
+	var resp topArtistsResponse
+	if err := xml.Unmarshal(wrapped, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal top artists response: %w", err)
+	}
+
+	result := &TopArtistsResponse{
+		User:       resp.TopArtists.User,
+		Pagination: resp.TopArtists.toPagination(),
+		Period:     Period(resp.TopArtists.Period),
+		Artists:    make([]TopArtist, len(resp.TopArtists.Artists)),
+	}
+	for i, a := range resp.TopArtists.Artists {
+		result.Artists[i] = TopArtist{
+			Rank:      a.Rank,
+			Name:      a.Name,
+			MBID:      a.MBID,
+			URL:       a.URL,
+			PlayCount: a.PlayCount,
+			Images:    toImages(a.Images),
+		}
+	}
+
+	return result, nil
+}
+
+// topAlbumsResponse represents the XML response from user.getTopAlbums.
+type topAlbumsResponse struct {
+	TopAlbums struct {
+		paginationXML
+		User   string `xml:"user,attr"`
+		Period string `xml:"type,attr"`
+		Albums []struct {
+			Rank      int          `xml:"rank,attr"`
+			Name      string       `xml:"name"`
+			MBID      string       `xml:"mbid"`
+			URL       string       `xml:"url"`
+			PlayCount int          `xml:"playcount"`
+			Artist    artistRefXML `xml:"artist"`
+			Images    []imageXML   `xml:"image"`
+		} `xml:"album"`
+	} `xml:"topalbums"`
+}
+
+// unmarshalTopAlbums parses the XML response from user.getTopAlbums.
+func unmarshalTopAlbums(data []byte) (*TopAlbumsResponse, error) {
+	// Wrap inner XML in root element for proper unmarshaling
+	wrapped := []byte("<root>" + string(data) + "</root>")
+
+	var resp topAlbumsResponse
+	if err := xml.Unmarshal(wrapped, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal top albums response: %w", err)
+	}
+
+	result := &TopAlbumsResponse{
+		User:       resp.TopAlbums.User,
+		Pagination: resp.TopAlbums.toPagination(),
+		Period:     Period(resp.TopAlbums.Period),
+		Albums:     make([]TopAlbum, len(resp.TopAlbums.Albums)),
+	}
+	for i, a := range resp.TopAlbums.Albums {
+		result.Albums[i] = TopAlbum{
+			Rank:      a.Rank,
+			Name:      a.Name,
+			MBID:      a.MBID,
+			Artist:    a.Artist.name(),
+			URL:       a.URL,
+			PlayCount: a.PlayCount,
+			Images:    toImages(a.Images),
+		}
+	}
+
+	return result, nil
+}
+
+// topTracksResponse represents the XML response from user.getTopTracks.
+type topTracksResponse struct {
+	TopTracks struct {
+		paginationXML
+		User   string `xml:"user,attr"`
+		Period string `xml:"type,attr"`
+		Tracks []struct {
+			Rank      int          `xml:"rank,attr"`
+			Name      string       `xml:"name"`
+			MBID      string       `xml:"mbid"`
+			URL       string       `xml:"url"`
+			Duration  int          `xml:"duration"`
+			PlayCount int          `xml:"playcount"`
+			Artist    artistRefXML `xml:"artist"`
+			Images    []imageXML   `xml:"image"`
+		} `xml:"track"`
+	} `xml:"toptracks"`
+}
+
+// unmarshalTopTracks parses the XML response from user.getTopTracks.
+func unmarshalTopTracks(data []byte) (*TopTracksResponse, error) {
+	// Wrap inner XML in root element for proper unmarshaling
+	wrapped := []byte("<root>" + string(data) + "</root>")
+
+	var resp topTracksResponse
+	if err := xml.Unmarshal(wrapped, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal top tracks response: %w", err)
+	}
+
+	result := &TopTracksResponse{
+		User:       resp.TopTracks.User,
+		Pagination: resp.TopTracks.toPagination(),
+		Period:     Period(resp.TopTracks.Period),
+		Tracks:     make([]TopTrack, len(resp.TopTracks.Tracks)),
+	}
+	for i, t := range resp.TopTracks.Tracks {
+		result.Tracks[i] = TopTrack{
+			Rank:      t.Rank,
+			Name:      t.Name,
+			MBID:      t.MBID,
+			Artist:    t.Artist.name(),
+			URL:       t.URL,
+			Duration:  t.Duration,
+			PlayCount: t.PlayCount,
+			Images:    toImages(t.Images),
+		}
+	}
+
+	return result, nil
+}
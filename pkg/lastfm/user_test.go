@@ -0,0 +1,417 @@
+package lastfm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newMethodServer creates a mock server that verifies the API method and
+// returns the given response body. The optional check func can inspect the
+// parsed form values.
+func newMethodServer(t *testing.T, method, response string, check func(r *http.Request)) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		if got := r.FormValue("method"); got != method {
+			t.Errorf("expected method %s, got %s", method, got)
+		}
+		if r.FormValue("api_sig") == "" {
+			t.Error("expected api_sig to be present")
+		}
+		if check != nil {
+			check(r)
+		}
+		if _, err := w.Write([]byte(response)); err != nil {
+			t.Fatalf("failed to write response body: %v", err)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(Config{
+		APIKey:     "test-api-key",
+		APISecret:  "test-secret",
+		SessionKey: "test-session-key",
+		BaseURL:    server.URL,
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestUserService_GetRecentTracks(t *testing.T) {
+	response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<recenttracks user="rj" page="1" perPage="2" totalPages="10" total="20">
+		<track nowplaying="true">
+			<artist mbid="artist-mbid">Aretha Franklin</artist>
+			<name>Respect</name>
+			<mbid></mbid>
+			<album mbid="album-mbid">I Never Loved a Man</album>
+			<url>https://www.last.fm/music/Aretha+Franklin/_/Respect</url>
+			<image size="small">https://img/small.png</image>
+			<image size="large"></image>
+		</track>
+		<track>
+			<artist mbid="">The Beatles</artist>
+			<name>Yesterday</name>
+			<mbid>track-mbid</mbid>
+			<album mbid="">Help!</album>
+			<url>https://www.last.fm/music/The+Beatles/_/Yesterday</url>
+			<date uts="1213031819">9 Jun 2008, 17:16</date>
+		</track>
+	</recenttracks>
+</lfm>`
+
+	from := time.Unix(1200000000, 0)
+	client := newMethodServer(t, "user.getRecentTracks", response, func(r *http.Request) {
+		if got := r.FormValue("user"); got != "rj" {
+			t.Errorf("expected user rj, got %s", got)
+		}
+		if got := r.FormValue("limit"); got != "2" {
+			t.Errorf("expected limit 2, got %s", got)
+		}
+		if got := r.FormValue("from"); got != "1200000000" {
+			t.Errorf("expected from 1200000000, got %s", got)
+		}
+		if got := r.FormValue("to"); got != "" {
+			t.Errorf("expected no to param, got %s", got)
+		}
+		if got := r.FormValue("sk"); got != "" {
+			t.Errorf("expected no session key for unauthenticated call, got %s", got)
+		}
+	})
+
+	resp, err := client.User().GetRecentTracks(context.Background(), "rj", RecentTracksOptions{
+		ListOptions: ListOptions{Limit: 2},
+		From:        from,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.User != "rj" {
+		t.Errorf("expected user rj, got %s", resp.User)
+	}
+	if resp.Page != 1 || resp.PerPage != 2 || resp.TotalPages != 10 || resp.Total != 20 {
+		t.Errorf("unexpected pagination: %+v", resp.Pagination)
+	}
+	if len(resp.Tracks) != 2 {
+		t.Fatalf("expected 2 tracks, got %d", len(resp.Tracks))
+	}
+
+	np := resp.Tracks[0]
+	if !np.NowPlaying {
+		t.Error("expected first track to be now playing")
+	}
+	if !np.Timestamp.IsZero() {
+		t.Errorf("expected zero timestamp for now playing, got %v", np.Timestamp)
+	}
+	if np.Artist != "Aretha Franklin" || np.ArtistMBID != "artist-mbid" {
+		t.Errorf("unexpected artist: %q (%q)", np.Artist, np.ArtistMBID)
+	}
+	if np.Album != "I Never Loved a Man" || np.AlbumMBID != "album-mbid" {
+		t.Errorf("unexpected album: %q (%q)", np.Album, np.AlbumMBID)
+	}
+	if len(np.Images) != 1 || np.Images[0].Size != "small" {
+		t.Errorf("expected one small image, got %+v", np.Images)
+	}
+
+	played := resp.Tracks[1]
+	if played.NowPlaying {
+		t.Error("expected second track not to be now playing")
+	}
+	if played.Timestamp.Unix() != 1213031819 {
+		t.Errorf("expected timestamp 1213031819, got %d", played.Timestamp.Unix())
+	}
+	if played.MBID != "track-mbid" {
+		t.Errorf("expected mbid track-mbid, got %s", played.MBID)
+	}
+}
+
+func TestUserService_GetRecentTracks_Extended(t *testing.T) {
+	response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<recenttracks user="rj">
+		<track>
+			<artist>
+				<name>The Beatles</name>
+				<mbid>artist-mbid</mbid>
+				<url>https://www.last.fm/music/The+Beatles</url>
+			</artist>
+			<loved>1</loved>
+			<name>Yesterday</name>
+			<album mbid="">Help!</album>
+			<date uts="1213031819">9 Jun 2008, 17:16</date>
+		</track>
+	</recenttracks>
+</lfm>`
+
+	client := newMethodServer(t, "user.getRecentTracks", response, func(r *http.Request) {
+		if got := r.FormValue("extended"); got != "1" {
+			t.Errorf("expected extended 1, got %s", got)
+		}
+	})
+
+	resp, err := client.User().GetRecentTracks(context.Background(), "rj", RecentTracksOptions{Extended: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Tracks) != 1 {
+		t.Fatalf("expected 1 track, got %d", len(resp.Tracks))
+	}
+	track := resp.Tracks[0]
+	if track.Artist != "The Beatles" {
+		t.Errorf("expected artist The Beatles, got %q", track.Artist)
+	}
+	if track.ArtistMBID != "artist-mbid" {
+		t.Errorf("expected artist mbid artist-mbid, got %q", track.ArtistMBID)
+	}
+	if !track.Loved {
+		t.Error("expected track to be loved")
+	}
+}
+
+func TestUserService_GetInfo(t *testing.T) {
+	response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<user>
+		<name>rj</name>
+		<realname>Richard Jones </realname>
+		<image size="medium">https://img/rj.png</image>
+		<url>https://www.last.fm/user/RJ</url>
+		<country>United Kingdom</country>
+		<subscriber>1</subscriber>
+		<playcount>54189</playcount>
+		<artist_count>3000</artist_count>
+		<album_count>6000</album_count>
+		<track_count>20000</track_count>
+		<registered unixtime="1037793040">2002-11-20 11:50</registered>
+	</user>
+</lfm>`
+
+	client := newMethodServer(t, "user.getInfo", response, nil)
+
+	info, err := client.User().GetInfo(context.Background(), "rj")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Name != "rj" {
+		t.Errorf("expected name rj, got %s", info.Name)
+	}
+	if info.RealName != "Richard Jones" {
+		t.Errorf("expected trimmed real name, got %q", info.RealName)
+	}
+	if !info.Subscriber {
+		t.Error("expected subscriber to be true")
+	}
+	if info.PlayCount != 54189 {
+		t.Errorf("expected playcount 54189, got %d", info.PlayCount)
+	}
+	if info.ArtistCount != 3000 || info.AlbumCount != 6000 || info.TrackCount != 20000 {
+		t.Errorf("unexpected counts: %+v", info)
+	}
+	if info.Registered.Unix() != 1037793040 {
+		t.Errorf("expected registered 1037793040, got %d", info.Registered.Unix())
+	}
+}
+
+func TestUserService_GetLovedTracks(t *testing.T) {
+	response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<lovedtracks user="rj" page="2" perPage="1" totalPages="3" total="3">
+		<track>
+			<name>Yesterday</name>
+			<mbid>track-mbid</mbid>
+			<url>https://www.last.fm/music/The+Beatles/_/Yesterday</url>
+			<date uts="1300000000">13 Mar 2011, 07:06</date>
+			<artist>
+				<name>The Beatles</name>
+				<mbid>artist-mbid</mbid>
+				<url>https://www.last.fm/music/The+Beatles</url>
+			</artist>
+		</track>
+	</lovedtracks>
+</lfm>`
+
+	client := newMethodServer(t, "user.getLovedTracks", response, func(r *http.Request) {
+		if got := r.FormValue("page"); got != "2" {
+			t.Errorf("expected page 2, got %s", got)
+		}
+	})
+
+	resp, err := client.User().GetLovedTracks(context.Background(), "rj", ListOptions{Page: 2, Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Tracks) != 1 {
+		t.Fatalf("expected 1 track, got %d", len(resp.Tracks))
+	}
+	track := resp.Tracks[0]
+	if track.Artist != "The Beatles" || track.Track != "Yesterday" {
+		t.Errorf("unexpected track: %+v", track)
+	}
+	if track.Timestamp.Unix() != 1300000000 {
+		t.Errorf("expected timestamp 1300000000, got %d", track.Timestamp.Unix())
+	}
+}
+
+func TestUserService_TopCharts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("top artists", func(t *testing.T) {
+		response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<topartists user="rj" type="7day">
+		<artist rank="1">
+			<name>Dream Theater</name>
+			<playcount>1337</playcount>
+			<mbid>artist-mbid</mbid>
+			<url>https://www.last.fm/music/Dream+Theater</url>
+		</artist>
+	</topartists>
+</lfm>`
+		client := newMethodServer(t, "user.getTopArtists", response, func(r *http.Request) {
+			if got := r.FormValue("period"); got != "7day" {
+				t.Errorf("expected period 7day, got %s", got)
+			}
+		})
+
+		resp, err := client.User().GetTopArtists(ctx, "rj", TopOptions{Period: Period7Day})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Period != Period7Day {
+			t.Errorf("expected period 7day, got %s", resp.Period)
+		}
+		if len(resp.Artists) != 1 || resp.Artists[0].Rank != 1 || resp.Artists[0].PlayCount != 1337 {
+			t.Errorf("unexpected artists: %+v", resp.Artists)
+		}
+	})
+
+	t.Run("top albums", func(t *testing.T) {
+		response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<topalbums user="rj" type="overall">
+		<album rank="1">
+			<name>Images and Words</name>
+			<playcount>300</playcount>
+			<artist>
+				<name>Dream Theater</name>
+				<mbid>artist-mbid</mbid>
+			</artist>
+			<image size="extralarge">https://img/xl.png</image>
+		</album>
+	</topalbums>
+</lfm>`
+		client := newMethodServer(t, "user.getTopAlbums", response, func(r *http.Request) {
+			if got := r.FormValue("period"); got != "overall" {
+				t.Errorf("expected default period overall, got %s", got)
+			}
+		})
+
+		resp, err := client.User().GetTopAlbums(ctx, "rj", TopOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resp.Albums) != 1 {
+			t.Fatalf("expected 1 album, got %d", len(resp.Albums))
+		}
+		album := resp.Albums[0]
+		if album.Artist != "Dream Theater" || album.PlayCount != 300 {
+			t.Errorf("unexpected album: %+v", album)
+		}
+		if len(album.Images) != 1 || album.Images[0].URL != "https://img/xl.png" {
+			t.Errorf("unexpected images: %+v", album.Images)
+		}
+	})
+
+	t.Run("top tracks", func(t *testing.T) {
+		response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="ok">
+	<toptracks user="rj" type="12month">
+		<track rank="2">
+			<name>Pull Me Under</name>
+			<duration>494</duration>
+			<playcount>42</playcount>
+			<artist>
+				<name>Dream Theater</name>
+			</artist>
+		</track>
+	</toptracks>
+</lfm>`
+		client := newMethodServer(t, "user.getTopTracks", response, nil)
+
+		resp, err := client.User().GetTopTracks(ctx, "rj", TopOptions{Period: Period12Month})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resp.Tracks) != 1 {
+			t.Fatalf("expected 1 track, got %d", len(resp.Tracks))
+		}
+		track := resp.Tracks[0]
+		if track.Rank != 2 || track.Duration != 494 || track.Artist != "Dream Theater" {
+			t.Errorf("unexpected track: %+v", track)
+		}
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		client, err := NewClient(Config{APIKey: "key", APISecret: "secret"})
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+		_, err = client.User().GetTopTracks(ctx, "rj", TopOptions{Period: "fortnight"})
+		if err == nil || !strings.Contains(err.Error(), "invalid period") {
+			t.Errorf("expected invalid period error, got %v", err)
+		}
+	})
+}
+
+func TestUserService_RequiresUser(t *testing.T) {
+	client, err := NewClient(Config{APIKey: "key", APISecret: "secret"})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := client.User().GetRecentTracks(ctx, "", RecentTracksOptions{}); err == nil {
+		t.Error("expected error for empty user in GetRecentTracks")
+	}
+	if _, err := client.User().GetInfo(ctx, ""); err == nil {
+		t.Error("expected error for empty user in GetInfo")
+	}
+	if _, err := client.User().GetLovedTracks(ctx, "", ListOptions{}); err == nil {
+		t.Error("expected error for empty user in GetLovedTracks")
+	}
+	if _, err := client.User().GetTopArtists(ctx, "", TopOptions{}); err == nil {
+		t.Error("expected error for empty user in GetTopArtists")
+	}
+}
+
+func TestUserService_APIError(t *testing.T) {
+	response := `<?xml version="1.0" encoding="utf-8"?>
+<lfm status="failed">
+	<error code="6">User not found</error>
+</lfm>`
+	client := newMethodServer(t, "user.getInfo", response, nil)
+
+	_, err := client.User().GetInfo(context.Background(), "nobody")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	var lfmErr *Error
+	if !errors.As(err, &lfmErr) || lfmErr.Code != ErrCodeInvalidParameters {
+		t.Errorf("expected *Error with code 6, got %v", err)
+	}
+}